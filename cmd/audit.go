@@ -0,0 +1,127 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/urfave/cli/v2"
+)
+
+// AuditShowParams contains parameters for the AuditShow command
+type AuditShowParams struct {
+	FileName string
+	Filter   audit.Filter
+}
+
+// WithAuditShowParams creates a new AuditShowParams with provided values
+func WithAuditShowParams(fileName string, filter audit.Filter) AuditShowParams {
+	return AuditShowParams{
+		FileName: fileName,
+		Filter:   filter,
+	}
+}
+
+// AuditShow prints the records of the audit log that match the filters
+func AuditShow(c *cli.Context) error {
+	paramsResult := validateAuditShowParams(c, time.Now())
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	recordsResult := audit.ReadRecordsResult(params.FileName)
+	if recordsResult.IsFailure() {
+		return recordsResult.GetError()
+	}
+
+	displayAuditRecords(audit.FilterRecords(recordsResult.Unwrap(), params.Filter))
+	return nil
+}
+
+// validateAuditShowParams validates CLI parameters and returns a Result monad
+func validateAuditShowParams(c *cli.Context, now time.Time) functional.Result[AuditShowParams] {
+	fileName := c.String("file")
+	if fileName == "" {
+		fileName = c.String("audit-log")
+	}
+	if fileName == "" {
+		return withFailure[AuditShowParams]("no audit log configured (use --file, --audit-log or SEM_AUDIT_LOG)")
+	}
+	if fileName == audit.SyslogTarget {
+		return withFailure[AuditShowParams]("records sent to syslog cannot be shown; read them with your syslog tools or pass a JSONL file with --file")
+	}
+
+	outcome := c.String("outcome")
+	if outcome != "" && outcome != audit.OutcomeSuccess && outcome != audit.OutcomeFailure {
+		return withFailure[AuditShowParams](fmt.Sprintf("invalid outcome '%s' (expected '%s' or '%s')", outcome, audit.OutcomeSuccess, audit.OutcomeFailure))
+	}
+
+	sinceResult := parseSince(c.String("since"), now)
+	if sinceResult.IsFailure() {
+		return functional.Failure[AuditShowParams](sinceResult.GetError())
+	}
+
+	return functional.Success(WithAuditShowParams(fileName, audit.Filter{
+		Since:   sinceResult.Unwrap(),
+		URI:     c.String("uri"),
+		User:    c.String("user"),
+		Command: c.String("command"),
+		Outcome: outcome,
+	}))
+}
+
+// parseSince parses a duration before now (e.g. 24h) or a date (2006-01-02 or RFC 3339)
+func parseSince(value string, now time.Time) functional.Result[time.Time] {
+	if value == "" {
+		return functional.Success(time.Time{})
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return functional.Success(now.Add(-duration))
+	}
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return functional.Success(since)
+	}
+	if since, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return functional.Success(since)
+	}
+	return withFailure[time.Time](fmt.Sprintf("invalid --since '%s' (use a duration such as 24h, a date such as 2006-01-02, or an RFC 3339 time)", value))
+}
+
+// displayAuditRecords prints one line per record
+func displayAuditRecords(records []audit.Record) {
+	if len(records) == 0 {
+		fmt.Println(formatting.Hint("No matching audit records"))
+		return
+	}
+
+	for _, record := range records {
+		fields := []string{
+			record.Time.Local().Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%s@%s", record.User, record.Host),
+			record.Command,
+			formatOutcome(record.Outcome),
+			record.Cache,
+			formatting.ColorizeKey(record.URI),
+		}
+		if record.Version != "" {
+			fields = append(fields, fmt.Sprintf("(version %s)", record.Version))
+		}
+		fmt.Println(strings.Join(fields, "  "))
+		if record.Error != "" {
+			fmt.Println(formatting.Error("  %s", record.Error))
+		}
+	}
+}
+
+// formatOutcome colors the outcome of an access
+func formatOutcome(outcome string) string {
+	if outcome == audit.OutcomeFailure {
+		return formatting.Error("%s", outcome)
+	}
+	return formatting.Success("%s", outcome)
+}
@@ -0,0 +1,99 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
+	"github.com/urfave/cli/v2"
+)
+
+// CheckParams contains parameters for the Check command
+type CheckParams struct {
+	InputFileName string
+	CacheFileName string
+}
+
+// WithCheckParams creates a new CheckParams with provided values
+func WithCheckParams(inputFileName, cacheFileName string) CheckParams {
+	return CheckParams{
+		InputFileName: inputFileName,
+		CacheFileName: cacheFileName,
+	}
+}
+
+// CheckResult represents the result of a required-variable check
+type CheckResult struct {
+	RequiredCount int
+}
+
+// Check reports required variables that are missing from both the cached secrets and the current environment
+func Check(c *cli.Context) error {
+	paramsResult := functional.MapResultTo(resolveEnvironment(c), func(environment profile.Environment) CheckParams {
+		return WithCheckParams(environment.InputFile, environment.CacheFile)
+	})
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+
+	result := performCheck(paramsResult.Unwrap())
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	checkResult := result.Unwrap()
+	logSuccessInfo(fmt.Sprintf("All %d required variables are set", checkResult.RequiredCount))
+	return nil
+}
+
+// performCheck compares the required entries of the input file with the cache file and the environment
+func performCheck(params CheckParams) functional.Result[CheckResult] {
+	entriesResult := readInputFile(params.InputFileName)
+	if entriesResult.IsFailure() {
+		return withFailure[CheckResult](entriesResult.GetError().Error())
+	}
+	entries := entriesResult.Unwrap()
+
+	// Secrets are taken from the cache so that the check works offline
+	cached := map[string]string{}
+	produced := env.ProducedKeys{}
+	if _, err := os.Stat(params.CacheFileName); err == nil {
+		cacheResult := fileio.ReadEnvVarsAsMap(params.CacheFileName)
+		if cacheResult.IsFailure() {
+			return withFailure[CheckResult](cacheResult.GetError().Error())
+		}
+		cached = cacheResult.Unwrap()
+
+		// The metadata tells which keys each entry produced when its JSON secret was expanded
+		metadataResult := provenance.ReadResult(provenance.FileName(params.CacheFileName))
+		if metadataResult.IsFailure() {
+			return withFailure[CheckResult](metadataResult.GetError().Error())
+		}
+		if metadata := metadataResult.Unwrap(); metadata.IsSome() {
+			produced = metadata.Unwrap().ProducedKeys()
+		}
+	} else {
+		logWarning(fmt.Sprintf("Cache file %s not found, required secrets are reported as missing (run 'sem update')",
+			params.CacheFileName))
+	}
+
+	requiredCount := 0
+	for _, entry := range entries {
+		if entry.IsRequired() {
+			requiredCount++
+		}
+	}
+
+	missing := env.MissingRequired(entries, cached, produced, os.LookupEnv)
+	if len(missing) > 0 {
+		return withFailure[CheckResult](fmt.Sprintf("%d of %d required variables are missing from %s and the environment:\n%s",
+			len(missing), requiredCount, params.CacheFileName, env.FormatMissingRequired(missing)))
+	}
+
+	return withSuccess(CheckResult{RequiredCount: requiredCount})
+}
@@ -0,0 +1,129 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/migrate"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
+	secretvalue "github.com/gumi-tsd/secret-env-manager/internal/secret"
+	"github.com/urfave/cli/v2"
+)
+
+// CopyParams contains parameters for the Copy command
+type CopyParams struct {
+	Source      uri.SecretURI
+	Destination uri.SecretURI
+	DryRun      bool
+	Endpoints   Endpoints
+	AssumeYes   bool
+}
+
+// WithCopyParams creates a new CopyParams with provided values
+func WithCopyParams(source, destination uri.SecretURI, dryRun bool, endpoints Endpoints, assumeYes bool) CopyParams {
+	return CopyParams{
+		Source:      source,
+		Destination: destination,
+		DryRun:      dryRun,
+		Endpoints:   endpoints,
+		AssumeYes:   assumeYes,
+	}
+}
+
+// Copy copies the value of a secret to another secret, on the same or another provider.
+// With ?key= on the source, only that key is copied; with ?key= on the destination, it is set in the JSON secret.
+func Copy(c *cli.Context) error {
+	paramsResult := validateCopyParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	valueResult := readCopyValue(params.Source, params.Endpoints)
+	if valueResult.IsFailure() {
+		return valueResult.GetError()
+	}
+	value := valueResult.Unwrap()
+
+	if problems := migrate.CheckValue(params.Destination.Platform, value); len(problems) > 0 {
+		return fmt.Errorf("cannot copy %s: %s", params.Source.SecretName, strings.Join(problems, ", "))
+	}
+
+	if params.DryRun {
+		logInfoMsg(fmt.Sprintf("Would copy %s to %s (%d bytes)", params.Source.GetUri(), params.Destination.GetUri(), len(value)))
+		return nil
+	}
+
+	confirmResult := confirmWrite(params.Destination, params.AssumeYes)
+	if confirmResult.IsFailure() {
+		return confirmResult.GetError()
+	}
+
+	writeResult := putSecret(params.Destination, value, params.Endpoints)
+	if writeResult.IsFailure() {
+		return writeResult.GetError()
+	}
+
+	displayWritten(params.Destination, writeResult.Unwrap())
+	return nil
+}
+
+// validateCopyParams validates CLI parameters and returns a Result monad
+func validateCopyParams(c *cli.Context) functional.Result[CopyParams] {
+	if c.NArg() != 2 {
+		return withFailure[CopyParams]("usage: sem copy <src-uri> <dst-uri>")
+	}
+
+	sourceResult := uri.ParseResult(c.Args().Get(0))
+	if sourceResult.IsFailure() {
+		return functional.Failure[CopyParams](sourceResult.GetError())
+	}
+	source := sourceResult.Unwrap()
+	if source.Platform != uri.AwsPlatform && source.Platform != uri.GoogleCloudPlatform {
+		return withFailure[CopyParams](fmt.Sprintf("unsupported platform '%s'", source.Platform))
+	}
+
+	destinationResult := writableURIResult(c.Args().Get(1))
+	if destinationResult.IsFailure() {
+		return functional.Failure[CopyParams](destinationResult.GetError())
+	}
+
+	return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) CopyParams {
+		return WithCopyParams(source, destinationResult.Unwrap(), c.Bool("dry-run"), endpoints, c.Bool("yes"))
+	})
+}
+
+// readCopyValue reads the value of the source secret version, or of its key
+func readCopyValue(source uri.SecretURI, endpoints Endpoints) functional.Result[string] {
+	valueResult := readSecretValue(source, endpoints)
+	if valueResult.IsFailure() || source.Key == "" {
+		return valueResult
+	}
+
+	// Values are copied as stored, without removing control characters
+	return secretvalue.ParseValueWithOptionsResult(valueResult.Unwrap(), source.Key, secretvalue.NewValueOptions(false))
+}
+
+// readSecretValue reads the raw value of the secret version referenced by a URI
+func readSecretValue(secretURI uri.SecretURI, endpoints Endpoints) functional.Result[string] {
+	ctx := context.Background()
+
+	if secretURI.Platform == uri.AwsPlatform {
+		client, err := aws.NewAwsProvider().GetClient(ctx, secretURI.Account, secretURI.Region, endpoints.For(secretURI))
+		if err != nil {
+			return withFailure[string](fmt.Sprintf("failed to get AWS client: %v", err))
+		}
+		return aws.FetchSecret(ctx, client, secretURI)
+	}
+
+	client, err := googlecloud.NewGoogleCloudProvider().GetClient(ctx, secretURI.Region, endpoints.For(secretURI))
+	if err != nil {
+		return withFailure[string](fmt.Sprintf("failed to get Google Cloud client: %v", err))
+	}
+	return googlecloud.FetchSecret(ctx, client, secretURI)
+}
@@ -0,0 +1,253 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
+	"github.com/gumi-tsd/secret-env-manager/internal/scan"
+	"github.com/urfave/cli/v2"
+)
+
+// DoctorCheck is the outcome of one check of the doctor command
+type DoctorCheck struct {
+	Name     string
+	Problems []string
+	Err      error // The check could not run; treated as a failure
+}
+
+// Passed reports whether the check ran and found nothing
+func (c DoctorCheck) Passed() bool {
+	return c.Err == nil && len(c.Problems) == 0
+}
+
+// Doctor checks that no secret has been or could be committed to git.
+// It fails when any check finds a problem or cannot run, so that it can be used in CI.
+func Doctor(c *cli.Context) error {
+	insideResult := fileio.IsInsideGitRepositoryResult()
+	if insideResult.IsFailure() {
+		return insideResult.GetError()
+	}
+	if !insideResult.Unwrap() {
+		logWarning("Not inside a git repository, nothing can be committed")
+		return nil
+	}
+
+	checks := []DoctorCheck{
+		checkIgnoreRules(),
+		checkIndex(),
+		checkHistory(),
+		checkPlaintextValues(),
+	}
+	displayDoctorChecks(checks)
+
+	failed := len(functional.Filter(checks, func(check DoctorCheck) bool { return !check.Passed() }))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	logSuccessInfo("No committed or committable secrets found")
+	return nil
+}
+
+// checkIgnoreRules verifies that git ignores every file sem writes for the environments of this directory
+func checkIgnoreRules() DoctorCheck {
+	check := DoctorCheck{Name: "Ignore rules cover the files generated by sem"}
+
+	environmentsResult := profile.Discover(".")
+	if environmentsResult.IsFailure() {
+		check.Err = environmentsResult.GetError()
+		return check
+	}
+
+	paths := scan.GeneratedPaths(environmentsResult.Unwrap())
+	cacheFilesResult := listLocalCacheFiles()
+	if cacheFilesResult.IsFailure() {
+		check.Err = cacheFilesResult.GetError()
+		return check
+	}
+	for _, cacheFile := range cacheFilesResult.Unwrap() {
+		if !functional.Contains(paths, cacheFile) {
+			paths = append(paths, cacheFile)
+		}
+	}
+
+	for _, path := range paths {
+		ignoredResult := fileio.IsFileIgnored(path)
+		if ignoredResult.IsFailure() {
+			check.Err = ignoredResult.GetError()
+			return check
+		}
+		if !ignoredResult.Unwrap() {
+			check.Problems = append(check.Problems, fmt.Sprintf("%s is not ignored (add it or '.cache.*' and '%s' to .gitignore)", path, profile.ActiveEnvFile))
+		}
+	}
+	return check
+}
+
+// checkIndex verifies that no generated file is tracked
+func checkIndex() DoctorCheck {
+	check := DoctorCheck{Name: "No cache file is tracked in the index"}
+
+	trackedResult := fileio.ListTrackedFilesResult()
+	if trackedResult.IsFailure() {
+		check.Err = trackedResult.GetError()
+		return check
+	}
+
+	for _, path := range trackedResult.Unwrap() {
+		if scan.IsGeneratedPath(path) {
+			check.Problems = append(check.Problems, fmt.Sprintf("%s is tracked (remove it with 'git rm --cached %s' and rotate its secrets)", path, path))
+		}
+	}
+	return check
+}
+
+// checkHistory verifies that no generated file was ever committed on any branch or tag
+func checkHistory() DoctorCheck {
+	check := DoctorCheck{Name: "No cache file was ever committed"}
+
+	pathsResult := fileio.ListCommittedPathsResult()
+	if pathsResult.IsFailure() {
+		check.Err = pathsResult.GetError()
+		return check
+	}
+
+	for _, committed := range scan.CommittedCaches(pathsResult.Unwrap()) {
+		check.Problems = append(check.Problems, fmt.Sprintf("%s was committed in %s (rotate its secrets; removing it requires rewriting history)", committed.Path, committed.Commit))
+	}
+	return check
+}
+
+// checkPlaintextValues searches tracked files for the secret values currently held in the cache files
+func checkPlaintextValues() DoctorCheck {
+	check := DoctorCheck{Name: "No tracked file contains a resolved secret value"}
+
+	valuesResult := readCachedValues()
+	if valuesResult.IsFailure() {
+		check.Err = valuesResult.GetError()
+		return check
+	}
+	values := valuesResult.Unwrap()
+	if len(values) == 0 {
+		logWarning("No resolved secret value found in the cache files, tracked files were not searched for secret values (run 'sem update' first)")
+		return check
+	}
+
+	trackedResult := fileio.ListTrackedFilesResult()
+	if trackedResult.IsFailure() {
+		check.Err = trackedResult.GetError()
+		return check
+	}
+
+	for _, path := range trackedResult.Unwrap() {
+		// Tracked cache files are reported by the index check
+		if scan.IsGeneratedPath(path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > scan.MaxFileSize {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			check.Err = fmt.Errorf("failed to read %s: %w", path, err)
+			return check
+		}
+		for _, match := range scan.FindValues(path, content, values) {
+			check.Problems = append(check.Problems, match.String())
+		}
+	}
+	return check
+}
+
+// listLocalCacheFiles lists the cache files in the current directory
+func listLocalCacheFiles() functional.Result[[]string] {
+	matches, err := filepath.Glob(".cache.*")
+	if err != nil {
+		return functional.Failure[[]string](err)
+	}
+	sort.Strings(matches)
+	return functional.Success(functional.Filter(matches, scan.IsCacheFile))
+}
+
+// listCacheFiles lists the cache files in the current directory and those written for the
+// tracked input files, including input files in subdirectories
+func listCacheFiles() functional.Result[[]string] {
+	localResult := listLocalCacheFiles()
+	if localResult.IsFailure() {
+		return localResult
+	}
+	trackedResult := fileio.ListTrackedFilesResult()
+	if trackedResult.IsFailure() {
+		return functional.Failure[[]string](trackedResult.GetError())
+	}
+
+	cacheFiles := localResult.Unwrap()
+	for _, candidate := range scan.CacheFileCandidates(trackedResult.Unwrap()) {
+		if _, err := os.Stat(candidate); err == nil && !functional.Contains(cacheFiles, candidate) {
+			cacheFiles = append(cacheFiles, candidate)
+		}
+	}
+	return functional.Success(cacheFiles)
+}
+
+// readCachedValues reads the secret values of every cache file in the current directory and
+// next to the tracked input files, keyed by variable name. Plain values copied from the input files are left out, since
+// they are expected to appear in tracked files.
+func readCachedValues() functional.Result[map[string]string] {
+	cacheFilesResult := listCacheFiles()
+	if cacheFilesResult.IsFailure() {
+		return functional.Failure[map[string]string](cacheFilesResult.GetError())
+	}
+
+	values := map[string]string{}
+	for _, cacheFile := range cacheFilesResult.Unwrap() {
+		secretsResult := readSecretValues(cacheFile)
+		if secretsResult.IsFailure() {
+			return functional.Failure[map[string]string](secretsResult.GetError())
+		}
+		for key, value := range secretsResult.Unwrap() {
+			values[fmt.Sprintf("%s (%s)", key, cacheFile)] = value
+		}
+	}
+	return functional.Success(values)
+}
+
+// readSecretValues reads the values of a cache file that were resolved from secret URIs, according to its metadata file
+func readSecretValues(cacheFile string) functional.Result[map[string]string] {
+	varsResult := fileio.ReadEnvVarsAsMap(cacheFile)
+	if varsResult.IsFailure() {
+		return functional.Failure[map[string]string](varsResult.GetError())
+	}
+	metadataResult := provenance.ReadResult(provenance.FileName(cacheFile))
+	if metadataResult.IsFailure() {
+		return functional.Failure[map[string]string](metadataResult.GetError())
+	}
+	return withSuccess(scan.SecretValues(unquoteValues(varsResult.Unwrap()), metadataResult.Unwrap()))
+}
+
+// displayDoctorChecks prints each check with its problems
+func displayDoctorChecks(checks []DoctorCheck) {
+	for _, check := range checks {
+		switch {
+		case check.Err != nil:
+			fmt.Println(formatting.Error("✗ %s", check.Name))
+			fmt.Println(formatting.Error("    could not run: %v", check.Err))
+		case len(check.Problems) > 0:
+			fmt.Println(formatting.Error("✗ %s", check.Name))
+			for _, problem := range check.Problems {
+				fmt.Printf("    %s\n", problem)
+			}
+		default:
+			fmt.Println(formatting.Success("✓ %s", check.Name))
+		}
+	}
+	fmt.Println()
+}
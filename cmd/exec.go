@@ -0,0 +1,167 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
+	"github.com/gumi-tsd/secret-env-manager/internal/scan"
+	"github.com/urfave/cli/v2"
+)
+
+// ExecParams contains parameters for the Exec command
+type ExecParams struct {
+	CacheFileName   string
+	Command         []string
+	ExportOnlyUnset bool
+	RedactOutput    bool
+}
+
+// WithExecParams creates a new ExecParams with provided values
+func WithExecParams(cacheFileName string, command []string, exportOnlyUnset, redactOutput bool) ExecParams {
+	return ExecParams{
+		CacheFileName:   cacheFileName,
+		Command:         command,
+		ExportOnlyUnset: exportOnlyUnset,
+		RedactOutput:    redactOutput,
+	}
+}
+
+// Exec runs a command with the cached secrets added to its environment.
+// The exit code of the command becomes the exit code of sem.
+func Exec(c *cli.Context) error {
+	paramsResult := validateExecParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	varsResult := readEnvVarsFromFile(params.CacheFileName)
+	if varsResult.IsFailure() {
+		return varsResult.GetError()
+	}
+	variables := unquoteValues(varsResult.Unwrap())
+	if params.ExportOnlyUnset {
+		variables = filterUnsetVariables(variables)
+	}
+
+	secrets := map[string]string{}
+	if params.RedactOutput {
+		metadataResult := provenance.ReadResult(provenance.FileName(params.CacheFileName))
+		if metadataResult.IsFailure() {
+			return metadataResult.GetError()
+		}
+		secrets = scan.SecretValues(variables, metadataResult.Unwrap())
+	}
+
+	exitCode, err := runCommand(params, variables, secrets)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return cli.Exit("", exitCode)
+	}
+	return nil
+}
+
+// validateExecParams validates CLI parameters and returns a Result monad
+func validateExecParams(c *cli.Context) functional.Result[ExecParams] {
+	if c.NArg() == 0 {
+		return withFailure[ExecParams]("usage: sem exec [options] -- <command> [args...]")
+	}
+
+	return functional.MapResultTo(resolveEnvironment(c), func(environment profile.Environment) ExecParams {
+		return WithExecParams(environment.CacheFile, c.Args().Slice(), c.Bool("only-unset"), c.Bool("redact-output"))
+	})
+}
+
+// runCommand starts the command and waits for it, returning its exit code.
+// With RedactOutput, the values of the secrets (variables resolved from secret URIs) are scrubbed from its stdout and stderr.
+func runCommand(params ExecParams, variables, secrets map[string]string) (int, error) {
+	command := exec.Command(params.Command[0], params.Command[1:]...)
+	command.Env = commandEnvironment(os.Environ(), variables)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	var writers []*redact.Writer
+	if params.RedactOutput {
+		redactor := redact.Default()
+		for _, value := range secrets {
+			redactor.Add(value)
+		}
+		stdout := redact.NewWriter(os.Stdout, redactor)
+		stderr := redact.NewWriter(os.Stderr, redactor)
+		command.Stdout, command.Stderr = stdout, stderr
+		writers = []*redact.Writer{stdout, stderr}
+	}
+
+	// The terminal delivers Ctrl-C to the command as well; sem waits for the command to exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	logDebugInfo(fmt.Sprintf("Running %s with %d variables", params.Command[0], len(variables)))
+	runErr := command.Run()
+
+	for _, writer := range writers {
+		writer.Flush()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		// A command killed by a signal has no exit code
+		if exitErr.ExitCode() < 0 {
+			return 1, nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if runErr != nil {
+		return 0, fmt.Errorf("failed to run %s: %w", params.Command[0], runErr)
+	}
+	return 0, nil
+}
+
+// unquoteValues removes the quotes that cache files put around values
+func unquoteValues(variables map[string]string) map[string]string {
+	result := make(map[string]string, len(variables))
+	for key, value := range variables {
+		result[key] = formatting.UnwrapQuotes(value)
+	}
+	return result
+}
+
+// commandEnvironment adds the variables to the current environment, replacing existing entries
+func commandEnvironment(environ []string, variables map[string]string) []string {
+	result := make([]string, 0, len(environ)+len(variables))
+	for _, entry := range environ {
+		if _, overridden := variables[envKey(entry)]; !overridden {
+			result = append(result, entry)
+		}
+	}
+	for key, value := range variables {
+		result = append(result, key+"="+value)
+	}
+	return result
+}
+
+// envKey returns the name of a KEY=value entry of os.Environ.
+// The search starts after the first character because Windows has entries such as "=C:=C:\\".
+func envKey(entry string) string {
+	if len(entry) == 0 {
+		return entry
+	}
+	if index := strings.Index(entry[1:], "="); index >= 0 {
+		return entry[:index+1]
+	}
+	return entry
+}
@@ -0,0 +1,159 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider"
+	"github.com/urfave/cli/v2"
+)
+
+// ExplainParams contains parameters for the Explain command
+type ExplainParams struct {
+	Key           string
+	InputFileName string
+	CacheFileName string
+	Endpoints     Endpoints
+	Expand        expand.Options
+}
+
+// WithExplainParams creates a new ExplainParams with provided values
+func WithExplainParams(key, inputFileName, cacheFileName string, endpoints Endpoints, expandOptions expand.Options) ExplainParams {
+	return ExplainParams{
+		Key:           key,
+		InputFileName: inputFileName,
+		CacheFileName: cacheFileName,
+		Endpoints:     endpoints,
+		Expand:        expandOptions,
+	}
+}
+
+// ExplainResult describes where a variable came from
+type ExplainResult struct {
+	Record    provenance.Record
+	Source    string    // Metadata file the record was read from (empty when resolved from providers)
+	UpdatedAt time.Time // Time the metadata was recorded
+}
+
+// Explain prints the provenance of a variable (input line, secret URI, version, JSON path), without printing its value.
+// The provenance is read from the metadata file written by update, or resolved from the providers if there is none.
+func Explain(c *cli.Context) error {
+	paramsResult := validateExplainParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+
+	result := performExplain(paramsResult.Unwrap())
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	displayExplainResult(result.Unwrap())
+	return nil
+}
+
+// validateExplainParams validates CLI parameters and returns a Result monad
+func validateExplainParams(c *cli.Context) functional.Result[ExplainParams] {
+	if c.NArg() != 1 {
+		return withFailure[ExplainParams]("usage: sem explain <KEY>")
+	}
+
+	expandResult := resolveExpandOptions(c)
+	if expandResult.IsFailure() {
+		return functional.Failure[ExplainParams](expandResult.GetError())
+	}
+
+	return functional.Chain(resolveEnvironment(c), func(environment profile.Environment) functional.Result[ExplainParams] {
+		return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) ExplainParams {
+			return WithExplainParams(c.Args().First(), environment.InputFile, environment.CacheFile,
+				endpoints, expandResult.Unwrap())
+		})
+	})
+}
+
+// performExplain looks up the provenance of the key in the metadata file, resolving the input file if there is none
+func performExplain(params ExplainParams) functional.Result[ExplainResult] {
+	metadataFileName := provenance.FileName(params.CacheFileName)
+	metadataResult := provenance.ReadResult(metadataFileName)
+	if metadataResult.IsFailure() {
+		return withFailure[ExplainResult](metadataResult.GetError().Error())
+	}
+
+	if metadata := metadataResult.Unwrap(); metadata.IsSome() {
+		record := metadata.Unwrap().Find(params.Key)
+		if record.IsNone() {
+			return withFailure[ExplainResult](fmt.Sprintf("variable '%s' is not recorded in %s (run 'sem update' if the input file changed)",
+				params.Key, metadataFileName))
+		}
+		return withSuccess(ExplainResult{
+			Record:    record.Unwrap(),
+			Source:    metadataFileName,
+			UpdatedAt: metadata.Unwrap().UpdatedAt,
+		})
+	}
+
+	logWarning(fmt.Sprintf("Metadata file %s not found, resolving secrets from providers", metadataFileName))
+	return resolveExplainResult(params)
+}
+
+// resolveExplainResult resolves the input file and looks up the origin of the key
+func resolveExplainResult(params ExplainParams) functional.Result[ExplainResult] {
+	entriesResult := readInputFile(params.InputFileName)
+	if entriesResult.IsFailure() {
+		return withFailure[ExplainResult](entriesResult.GetError().Error())
+	}
+
+	resolveResult := resolveSecrets(entriesResult.Unwrap(), params.Endpoints, params.Expand)
+	if resolveResult.IsFailure() {
+		return withFailure[ExplainResult](resolveResult.GetError().Error())
+	}
+	resolved := resolveResult.Unwrap()
+
+	origin, exists := resolved.Origins[params.Key]
+	if !exists {
+		return withFailure[ExplainResult](fmt.Sprintf("variable '%s' is not produced by %s", params.Key, params.InputFileName))
+	}
+
+	return withSuccess(ExplainResult{
+		Record: provenance.NewRecord(params.Key, origin, provider.CollisionsFor(resolved.Collisions, params.Key)),
+	})
+}
+
+// displayExplainResult prints the provenance of the variable and the definitions it replaced
+func displayExplainResult(result ExplainResult) {
+	record := result.Record
+
+	fmt.Println(formatting.ColorizeKey(record.Key))
+	fmt.Printf("  Defined at: %s\n", record.Location)
+	if record.URI == "" {
+		fmt.Println("  Secret URI: (plain value)")
+	} else {
+		fmt.Printf("  Secret URI: %s\n", record.URI)
+	}
+	if record.Version != "" {
+		fmt.Printf("  Version:    %s\n", record.Version)
+	}
+	if record.Path != "" {
+		fmt.Printf("  JSON path:  %s\n", record.Path)
+	}
+	if record.Expanded {
+		fmt.Println("  Name:       expanded from the secret")
+	} else {
+		fmt.Println("  Name:       entry name")
+	}
+	for _, source := range record.Overrides {
+		fmt.Printf("  Overrides:  %s\n", source)
+	}
+
+	if result.Source != "" {
+		fmt.Println(formatting.Hint("  Recorded in %s at %s", result.Source, result.UpdatedAt.Local().Format(time.RFC3339)))
+	} else {
+		fmt.Println(formatting.Hint("  Resolved from providers (run 'sem update' to record it)"))
+	}
+}
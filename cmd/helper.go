@@ -4,8 +4,14 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/logging"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v2"
 )
 
 // logger is a package-level logger instance used for command logging
@@ -38,10 +44,108 @@ func logWarning(message string) {
 
 // logDebugInfo logs debug information
 func logDebugInfo(message string) {
-	logger.Info("%s", message)
+	logger.Debug("%s", message)
 }
 
 // logErrorMsg logs an error message
 func logErrorMsg(message string) {
 	logger.Error("%s", message)
 }
+
+// resolveEnvironment determines the environment for a command.
+// Priority: --env flag (which cannot be combined with --input), explicit --input flag, active environment recorded by 'sem use', default input file.
+func resolveEnvironment(c *cli.Context) functional.Result[profile.Environment] {
+	if name := c.String("env"); name != "" {
+		if c.IsSet("input") {
+			return withFailure[profile.Environment]("--env and --input cannot be used together")
+		}
+		return functional.MapResultTo(profile.ValidateName(name), profile.Resolve)
+	}
+
+	inputFileName := c.String("input")
+	if c.IsSet("input") {
+		if inputFileName == "" {
+			return withFailure[profile.Environment]("input file path required (-i or --input)")
+		}
+		return withSuccess(profile.FromInputFile(inputFileName))
+	}
+
+	activeResult := profile.ReadActive()
+	if activeResult.IsFailure() {
+		return functional.Failure[profile.Environment](activeResult.GetError())
+	}
+	if active := activeResult.Unwrap(); active.IsSome() {
+		return withSuccess(profile.Resolve(active.Unwrap()))
+	}
+
+	if inputFileName == "" {
+		return withFailure[profile.Environment]("input file path required (-i or --input)")
+	}
+	return withSuccess(profile.FromInputFile(inputFileName))
+}
+
+// confirmProduction asks for explicit confirmation before operating on a production environment
+func confirmProduction(environment profile.Environment, action string, assumeYes bool) functional.Result[bool] {
+	if !environment.Production || assumeYes {
+		return withSuccess(true)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("'%s' is a production environment. %s", environment.Name, action),
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		return withFailure[bool](fmt.Sprintf(
+			"aborted: production environment '%s' requires confirmation (use --yes to skip the prompt)",
+			environment.Name))
+	}
+	return withSuccess(true)
+}
+
+// resolveExpandOptions builds the global JSON expansion options from --expand and --no-expand-json
+func resolveExpandOptions(c *cli.Context) functional.Result[expand.Options] {
+	optionsResult := expand.ParseResult(c.String("expand"), expand.DefaultOptions())
+	if optionsResult.IsFailure() || !c.Bool("no-expand-json") {
+		return optionsResult
+	}
+	return withSuccess(optionsResult.Unwrap().Disabled())
+}
+
+// Endpoints chooses the API endpoint and the credential source of each secret
+type Endpoints struct {
+	Resolver endpoint.Resolver
+}
+
+// For returns the endpoint settings of a secret
+func (e Endpoints) For(secretURI uri.SecretURI) endpoint.Settings {
+	return secretURI.ResolveEndpoint(e.Resolver)
+}
+
+// ForAccount returns the endpoint settings of the secrets of an account, used when listing them
+func (e Endpoints) ForAccount(platform, account, region string) endpoint.Settings {
+	return e.Resolver.Resolve(platform, account, region, endpoint.Settings{})
+}
+
+// resolveEndpoints reads the endpoint rules of the configuration file (--endpoints-file, or
+// .sem-endpoints.yaml when present) and the per-platform defaults of the CLI flags
+func resolveEndpoints(c *cli.Context) functional.Result[Endpoints] {
+	configFile, required := c.String("endpoints-file"), true
+	if configFile == "" {
+		configFile, required = endpoint.ConfigFile, false
+	}
+	rulesResult := endpoint.LoadConfigResult(configFile, required)
+	if rulesResult.IsFailure() {
+		return functional.Failure[Endpoints](rulesResult.GetError())
+	}
+
+	awsCredentials := c.String("aws-credentials")
+	if err := endpoint.ValidateCredentialsResult(uri.AwsPlatform, awsCredentials).GetError(); err != nil {
+		return functional.Failure[Endpoints](fmt.Errorf("--aws-credentials: %w", err))
+	}
+
+	return withSuccess(Endpoints{Resolver: endpoint.NewResolver(rulesResult.Unwrap(), map[string]endpoint.Settings{
+		uri.AwsPlatform:         {Endpoint: c.String("endpoint-url"), Credentials: awsCredentials},
+		uri.GoogleCloudPlatform: {Endpoint: c.String("gcp-endpoint")},
+	})})
+}
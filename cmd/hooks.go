@@ -0,0 +1,122 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/hooks"
+	"github.com/urfave/cli/v2"
+)
+
+// hookName is the git hook installed by 'sem hooks install'
+const hookName = "pre-commit"
+
+// HooksInstall installs the pre-commit hook, or prints the pre-commit framework
+// configuration when the repository uses the framework
+func HooksInstall(c *cli.Context) error {
+	rootResult := fileio.RepositoryRootResult()
+	if rootResult.IsFailure() {
+		return rootResult.GetError()
+	}
+
+	frameworkConfig := filepath.Join(rootResult.Unwrap(), hooks.FrameworkConfigFile)
+	if _, err := os.Stat(frameworkConfig); err == nil || c.Bool("framework") {
+		displayFrameworkConfig()
+		return nil
+	}
+
+	pathResult := fileio.HookPathResult(hookName)
+	if pathResult.IsFailure() {
+		return pathResult.GetError()
+	}
+	hookPath := pathResult.Unwrap()
+
+	installResult := installHook(hookPath, c.Bool("force"))
+	if installResult.IsFailure() {
+		return installResult.GetError()
+	}
+
+	logSuccessInfo(fmt.Sprintf("Installed the pre-commit hook at %s", hookPath))
+	return nil
+}
+
+// installHook writes the hook script, refusing to replace a hook not written by sem unless forced
+func installHook(hookPath string, force bool) functional.Result[bool] {
+	existing, err := os.ReadFile(hookPath)
+	if err == nil && !force && !hooks.IsManaged(string(existing)) {
+		return withFailure[bool](fmt.Sprintf(
+			"a pre-commit hook already exists at %s; use --force to replace it, or run 'sem hooks pre-commit' from it", hookPath))
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return withFailure[bool](fmt.Sprintf("failed to read %s: %v", hookPath, err))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		return withFailure[bool](fmt.Sprintf("failed to create the hooks directory: %v", err))
+	}
+	if err := os.WriteFile(hookPath, []byte(hooks.Script), 0755); err != nil {
+		return withFailure[bool](fmt.Sprintf("failed to write %s: %v", hookPath, err))
+	}
+	// WriteFile keeps the mode of an existing file, so make sure the hook is executable
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return withFailure[bool](fmt.Sprintf("failed to make %s executable: %v", hookPath, err))
+	}
+	return withSuccess(true)
+}
+
+// displayFrameworkConfig prints the entry to add to the pre-commit framework configuration
+func displayFrameworkConfig() {
+	fmt.Println(formatting.Hint("This repository uses the pre-commit framework, which manages .git/hooks/pre-commit."))
+	fmt.Println(formatting.Hint("Add the following to %s and run 'pre-commit install':", hooks.FrameworkConfigFile))
+	fmt.Println()
+	fmt.Print(hooks.FrameworkConfig)
+	fmt.Println()
+}
+
+// HooksPreCommit checks the staged files. It is run by the installed hook.
+func HooksPreCommit(c *cli.Context) error {
+	filesResult := readStagedFiles()
+	if filesResult.IsFailure() {
+		return filesResult.GetError()
+	}
+
+	valuesResult := readCachedValues()
+	if valuesResult.IsFailure() {
+		return valuesResult.GetError()
+	}
+
+	report := hooks.CheckStagedFiles(filesResult.Unwrap(), valuesResult.Unwrap())
+	for _, warning := range report.Warnings {
+		logWarning(warning)
+	}
+	if !report.Passed() {
+		for _, problem := range report.Rejected {
+			logErrorMsg(problem)
+		}
+		return fmt.Errorf("commit rejected: %d staged secret problem(s) (bypass with 'git commit --no-verify' only if they are false positives)", len(report.Rejected))
+	}
+	return nil
+}
+
+// readStagedFiles reads the staged content of every added or modified file
+func readStagedFiles() functional.Result[[]hooks.StagedFile] {
+	pathsResult := fileio.ListStagedFilesResult()
+	if pathsResult.IsFailure() {
+		return functional.Failure[[]hooks.StagedFile](pathsResult.GetError())
+	}
+
+	files := []hooks.StagedFile{}
+	for _, path := range pathsResult.Unwrap() {
+		contentResult := fileio.ReadStagedFileResult(path)
+		if contentResult.IsFailure() {
+			return functional.Failure[[]hooks.StagedFile](contentResult.GetError())
+		}
+		files = append(files, hooks.StagedFile{Path: path, Content: contentResult.Unwrap()})
+	}
+	return functional.Success(files)
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
 	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
 	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
@@ -30,9 +32,9 @@ const (
 // EnvParams holds the validated environment parameters
 type EnvParams struct {
 	// AWS parameters
-	AwsProfile  string
-	AwsRegion   string
-	EndpointURL string
+	AwsProfile string
+	AwsRegion  string
+	Endpoints  Endpoints
 
 	// Google Cloud parameters
 	GoogleCloudProjectID string
@@ -42,12 +44,12 @@ type EnvParams struct {
 }
 
 // WithEnvParams creates a new EnvParams with provided values
-func WithEnvParams(provider Provider, awsProfile, awsRegion, endpointURL, googleCloudProjectID string) *EnvParams {
+func WithEnvParams(provider Provider, awsProfile, awsRegion string, endpoints Endpoints, googleCloudProjectID string) *EnvParams {
 	return &EnvParams{
 		Provider:             provider,
 		AwsProfile:           awsProfile,
 		AwsRegion:            awsRegion,
-		EndpointURL:          endpointURL,
+		Endpoints:            endpoints,
 		GoogleCloudProjectID: googleCloudProjectID,
 	}
 }
@@ -63,8 +65,12 @@ type InitResult struct {
 
 // Init initializes the environment by listing secrets from cloud providers and allows interactive selection
 func Init(c *cli.Context) error {
-	// Get endpoint URL from flag
-	endpointURL := c.String("endpoint-url")
+	// Get endpoints from the endpoint configuration and flags
+	endpointsResult := resolveEndpoints(c)
+	if endpointsResult.IsFailure() {
+		return endpointsResult.GetError()
+	}
+	endpoints := endpointsResult.Unwrap()
 
 	// Select provider
 	providerResult := selectProvider()
@@ -74,7 +80,7 @@ func Init(c *cli.Context) error {
 	provider := providerResult.Unwrap()
 
 	// Validate environment based on selected provider
-	envResult := validateEnvironment(provider, endpointURL)
+	envResult := validateEnvironment(provider, endpoints)
 	if envResult.IsFailure() {
 		return envResult.GetError()
 	}
@@ -88,20 +94,25 @@ func Init(c *cli.Context) error {
 			params.AwsProfile, params.AwsRegion)
 
 		// Add endpoint URL information if specified
-		if params.EndpointURL != "" {
-			logCtxMsg += fmt.Sprintf(" using endpoint URL '%s'", params.EndpointURL)
+		if settings := params.awsSettings(); settings.Endpoint != "" {
+			logCtxMsg += fmt.Sprintf(" using endpoint URL '%s'", settings.Endpoint)
 		}
 	} else {
 		logCtxMsg = fmt.Sprintf("Listing secrets for Google Cloud project '%s'", params.GoogleCloudProjectID)
+
+		// Add endpoint information if specified
+		if settings := params.googleCloudSettings(); settings.Endpoint != "" {
+			logCtxMsg += fmt.Sprintf(" using endpoint '%s'", settings.Endpoint)
+		}
 	}
 	logInfoMsg(logCtxMsg + "...")
 
 	// List secrets from the selected provider
 	var secretsResult functional.Result[[]string]
 	if params.Provider == AWSProvider {
-		secretsResult = listAwsSecrets(params.AwsProfile, params.AwsRegion, params.EndpointURL)
+		secretsResult = listAwsSecrets(params.AwsProfile, params.AwsRegion, params.awsSettings())
 	} else {
-		secretsResult = listGoogleCloudSecrets(params.GoogleCloudProjectID)
+		secretsResult = listGoogleCloudSecrets(params.GoogleCloudProjectID, params.googleCloudSettings())
 	}
 
 	if secretsResult.IsFailure() {
@@ -122,8 +133,18 @@ func Init(c *cli.Context) error {
 
 	selectedSecrets := selectResult.Unwrap()
 
+	// Let the user pick a version or stage for each selected secret
+	secretURIs := make([]uri.SecretURI, 0, len(selectedSecrets))
+	for _, secretName := range selectedSecrets {
+		versionResult := selectVersionResult(buildSecretURI(secretName, params), params.Endpoints)
+		if versionResult.IsFailure() {
+			return versionResult.GetError()
+		}
+		secretURIs = append(secretURIs, versionResult.Unwrap())
+	}
+
 	// Display selected secrets with nice formatting
-	outputSelectedSecrets(selectedSecrets, params)
+	outputSelectedSecrets(secretURIs)
 
 	// Display help for next steps
 	displayNextSteps()
@@ -149,8 +170,18 @@ func selectProvider() functional.Result[Provider] {
 	return functional.Success(GoogleCloudProvider)
 }
 
+// awsSettings returns the endpoint settings of the AWS profile and region being listed
+func (p *EnvParams) awsSettings() endpoint.Settings {
+	return p.Endpoints.ForAccount(uri.AwsPlatform, p.AwsProfile, p.AwsRegion)
+}
+
+// googleCloudSettings returns the endpoint settings of the Google Cloud project being listed
+func (p *EnvParams) googleCloudSettings() endpoint.Settings {
+	return p.Endpoints.ForAccount(uri.GoogleCloudPlatform, p.GoogleCloudProjectID, "")
+}
+
 // validateEnvironment validates required environment variables based on selected provider
-func validateEnvironment(provider Provider, endpointURL string) functional.Result[*EnvParams] {
+func validateEnvironment(provider Provider, endpoints Endpoints) functional.Result[*EnvParams] {
 	if provider == AWSProvider {
 		// Check AWS_PROFILE with Option monad
 		profileOption := getEnvOption("AWS_PROFILE")
@@ -168,7 +199,7 @@ func validateEnvironment(provider Provider, endpointURL string) functional.Resul
 			provider,
 			profileOption.Unwrap(),
 			regionOption.Unwrap(),
-			endpointURL,
+			endpoints,
 			"", // No Google Cloud project ID needed
 		))
 	} else {
@@ -182,7 +213,7 @@ func validateEnvironment(provider Provider, endpointURL string) functional.Resul
 			provider,
 			"", // No AWS profile needed
 			"", // No AWS region needed
-			"", // No endpoint URL needed
+			endpoints,
 			projectOption.Unwrap(),
 		))
 	}
@@ -198,15 +229,15 @@ func getEnvOption(name string) functional.Option[string] {
 }
 
 // listAwsSecrets retrieves secrets from AWS Secrets Manager
-func listAwsSecrets(awsProfile, awsRegion, endpointURL string) functional.Result[[]string] {
+func listAwsSecrets(awsProfile, awsRegion string, settings endpoint.Settings) functional.Result[[]string] {
 	ctx := context.Background()
 
 	// Create a custom AWS Provider
 	provider := aws.NewAwsProvider()
 
-	// Handle custom endpoint if specified
-	if endpointURL != "" {
-		return listAwsSecretsWithEndpoint(ctx, provider, awsProfile, awsRegion, endpointURL)
+	// Handle custom endpoint or credentials if specified
+	if settings != (endpoint.Settings{}) {
+		return listAwsSecretsWithEndpoint(ctx, provider, awsProfile, awsRegion, settings)
 	} else {
 		// Standard case (no endpoint URL)
 		return provider.ListSecretsResult(ctx, awsProfile, awsRegion)
@@ -214,29 +245,29 @@ func listAwsSecrets(awsProfile, awsRegion, endpointURL string) functional.Result
 }
 
 // listGoogleCloudSecrets retrieves secrets from Google Cloud Secret Manager
-func listGoogleCloudSecrets(projectID string) functional.Result[[]string] {
+func listGoogleCloudSecrets(projectID string, settings endpoint.Settings) functional.Result[[]string] {
 	ctx := context.Background()
 
 	// Create a Google Cloud Provider
 	provider := googlecloud.NewGoogleCloudProvider()
 
 	// Call Google Cloud API
-	return provider.ListSecretsResult(ctx, projectID, "")
+	return provider.ListSecretsWithEndpointResult(ctx, projectID, "", settings)
 }
 
 // listAwsSecretsWithEndpoint retrieves secrets using a custom endpoint
 func listAwsSecretsWithEndpoint(ctx context.Context, provider *aws.AwsProvider,
-	awsProfile, awsRegion, endpointURL string) functional.Result[[]string] {
+	awsProfile, awsRegion string, settings endpoint.Settings) functional.Result[[]string] {
 
 	// Get client with custom endpoint
 	clientResult := functional.TryCatch(func() (*secretsmanager.Client, error) {
-		return provider.GetClient(ctx, awsProfile, awsRegion, endpointURL)
+		return provider.GetClient(ctx, awsProfile, awsRegion, settings)
 	})
 
 	if clientResult.IsFailure() {
 		return withFailure[[]string](
 			fmt.Sprintf("failed to get AWS client for profile '%s' in region '%s' with endpoint '%s': %v",
-				awsProfile, awsRegion, endpointURL, clientResult.GetError()))
+				awsProfile, awsRegion, settings.Endpoint, clientResult.GetError()))
 	}
 
 	client := clientResult.Unwrap()
@@ -254,7 +285,7 @@ func listAwsSecretsWithEndpoint(ctx context.Context, provider *aws.AwsProvider,
 	if secretsResult.IsFailure() {
 		return withFailure[[]string](
 			fmt.Sprintf("AWS ListSecrets API error for profile '%s' in region '%s' with endpoint '%s': %v",
-				awsProfile, awsRegion, endpointURL, secretsResult.GetError()))
+				awsProfile, awsRegion, settings.Endpoint, secretsResult.GetError()))
 	}
 
 	// Extract secret names
@@ -313,9 +344,69 @@ func selectSecrets(secretNames []string) ([]string, error) {
 	return []string{secretNames[selectedIdx]}, nil
 }
 
+// versionChoice is an item of the version selection prompt
+type versionChoice struct {
+	Version string // Version written to the URI (a stage or a version ID)
+	Label   string
+}
+
+// selectVersionResult prompts the user to pick a stage or a specific version of the secret.
+// If the versions cannot be listed, the default version of the URI is kept.
+func selectVersionResult(secretURI uri.SecretURI, endpoints Endpoints) functional.Result[uri.SecretURI] {
+	versionsResult := listSecretVersions(secretURI, endpoints)
+	if versionsResult.IsFailure() {
+		logWarning(fmt.Sprintf("Unable to list versions, using %s: %v", secretURI.Version, versionsResult.GetError()))
+		return withSuccess(secretURI)
+	}
+
+	choices := versionChoices(secretURI.Version, versionsResult.Unwrap())
+	labels := functional.Map(choices, func(choice versionChoice) string {
+		return choice.Label
+	})
+
+	prompt := promptui.Select{
+		Label: "Select a version or stage (use arrow keys, press Enter to select)",
+		Items: labels,
+		Size:  20,
+	}
+
+	selectedIdx, _, err := prompt.Run()
+	if err != nil {
+		return withFailure[uri.SecretURI](fmt.Sprintf("version selection failed: %v", err))
+	}
+	return withSuccess(secretURI.WithVersion(choices[selectedIdx].Version))
+}
+
+// versionChoices lists the default version, then every stage or alias, then every version that can be accessed
+func versionChoices(defaultVersion string, versions []secret.Version) []versionChoice {
+	choices := []versionChoice{{Version: defaultVersion, Label: defaultVersion + " (default)"}}
+
+	// Disabled and destroyed versions cannot be accessed, so neither they nor their aliases are offered
+	accessible := functional.Filter(versions, secret.Version.IsAccessible)
+
+	seen := map[string]bool{defaultVersion: true}
+	for _, version := range accessible {
+		for _, stage := range version.Stages {
+			if seen[stage] {
+				continue
+			}
+			seen[stage] = true
+			choices = append(choices, versionChoice{Version: stage, Label: fmt.Sprintf("%s (currently %s)", stage, version.ID)})
+		}
+	}
+
+	for _, version := range accessible {
+		choices = append(choices, versionChoice{
+			Version: version.ID,
+			Label:   fmt.Sprintf("%s  %s  %s", version.ID, formatStages(version), formatCreatedAt(version.CreatedAt)),
+		})
+	}
+	return choices
+}
+
 // outputSelectedSecrets prints the selected secret URIs to standard output
-func outputSelectedSecrets(secretNames []string, params *EnvParams) {
-	if len(secretNames) == 0 {
+func outputSelectedSecrets(secretURIs []uri.SecretURI) {
+	if len(secretURIs) == 0 {
 		return
 	}
 
@@ -323,20 +414,20 @@ func outputSelectedSecrets(secretNames []string, params *EnvParams) {
 	fmt.Println(formatting.Hint("Copy the following URI to your environment file:"))
 	fmt.Println()
 
-	for _, secretName := range secretNames {
-		var secretUri uri.SecretURI
-
-		if params.Provider == AWSProvider {
-			secretUri = buildAwsSecretURI(secretName, params.AwsProfile, params.AwsRegion)
-		} else {
-			secretUri = buildGoogleCloudSecretURI(secretName, params.GoogleCloudProjectID)
-		}
-
+	for _, secretUri := range secretURIs {
 		fmt.Println(formatting.ColorizeValue(secretUri.GetUri()))
 	}
 	fmt.Println()
 }
 
+// buildSecretURI creates a SecretURI with the default version for a secret of the selected provider
+func buildSecretURI(secretName string, params *EnvParams) uri.SecretURI {
+	if params.Provider == AWSProvider {
+		return buildAwsSecretURI(secretName, params.AwsProfile, params.AwsRegion)
+	}
+	return buildGoogleCloudSecretURI(secretName, params.GoogleCloudProjectID)
+}
+
 // displayNextSteps shows the user what to do next
 func displayNextSteps() {
 	fmt.Println(formatting.FormatHeader("\nNext Steps"))
@@ -0,0 +1,117 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/lint"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+// LintParams contains parameters for the Lint command
+type LintParams struct {
+	InputFileName string
+	Format        lint.Format
+	ToolVersion   string
+}
+
+// WithLintParams creates a new LintParams with provided values
+func WithLintParams(inputFileName string, format lint.Format, toolVersion string) LintParams {
+	return LintParams{
+		InputFileName: inputFileName,
+		Format:        format,
+		ToolVersion:   toolVersion,
+	}
+}
+
+// Lint checks an input env file for literal secrets, malformed secret URIs,
+// duplicate keys and invalid variable names without contacting any provider.
+// It fails when an error-level finding is reported, so that it can be used in CI.
+func Lint(c *cli.Context) error {
+	paramsResult := validateLintParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	findingsResult := performLint(params)
+	if findingsResult.IsFailure() {
+		return findingsResult.GetError()
+	}
+	findings := findingsResult.Unwrap()
+
+	displayResult := displayLintFindings(findings, params)
+	if displayResult.IsFailure() {
+		return displayResult.GetError()
+	}
+
+	if lint.HasErrors(findings) {
+		return fmt.Errorf("%d problem(s) found in %s", len(findings), params.InputFileName)
+	}
+	if params.Format == lint.TextFormat && len(findings) == 0 {
+		logSuccessInfo(fmt.Sprintf("No problems found in %s", params.InputFileName))
+	}
+	return nil
+}
+
+// validateLintParams validates CLI parameters and returns a Result monad
+func validateLintParams(c *cli.Context) functional.Result[LintParams] {
+	formatResult := lint.ParseFormatResult(c.String("format"))
+	if formatResult.IsFailure() {
+		return functional.Failure[LintParams](formatResult.GetError())
+	}
+
+	return functional.MapResultTo(resolveEnvironment(c), func(environment profile.Environment) LintParams {
+		return WithLintParams(environment.InputFile, formatResult.Unwrap(), c.App.Version)
+	})
+}
+
+// performLint reads the input file and returns its findings
+func performLint(params LintParams) functional.Result[[]lint.Finding] {
+	contentResult := fileio.ReadFile(params.InputFileName)
+	if contentResult.IsFailure() {
+		return functional.Failure[[]lint.Finding](contentResult.GetError())
+	}
+	content := contentResult.Unwrap()
+	if content.Type != fileio.EnvFile {
+		return withFailure[[]lint.Finding](fmt.Sprintf("%s is not an env file; sem lint checks .env files", params.InputFileName))
+	}
+
+	findingsResult := lint.LintResult(params.InputFileName, content.Data)
+	if findingsResult.IsFailure() {
+		return withFailure[[]lint.Finding](fmt.Sprintf("failed to parse env file '%s': %v", params.InputFileName, findingsResult.GetError()))
+	}
+	return findingsResult
+}
+
+// displayLintFindings prints the findings to stdout in the requested format
+func displayLintFindings(findings []lint.Finding, params LintParams) functional.Result[bool] {
+	switch params.Format {
+	case lint.JSONFormat:
+		return printReport(lint.FormatJSON(findings))
+	case lint.SARIFFormat:
+		return printReport(lint.FormatSARIF(findings, params.ToolVersion))
+	}
+
+	for _, finding := range findings {
+		if finding.Rule.Severity == lint.SeverityError {
+			fmt.Println(formatting.Error("%s", finding.String()))
+		} else {
+			fmt.Println(formatting.Warning("%s", finding.String()))
+		}
+	}
+	return withSuccess(true)
+}
+
+// printReport prints an encoded report
+func printReport(report functional.Result[string]) functional.Result[bool] {
+	if report.IsFailure() {
+		return functional.Failure[bool](report.GetError())
+	}
+	fmt.Println(report.Unwrap())
+	return withSuccess(true)
+}
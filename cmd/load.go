@@ -77,10 +77,11 @@ func Load(c *cli.Context) error {
 
 // validateLoadParams validates CLI parameters with Result monad
 func validateLoadParams(c *cli.Context) functional.Result[LoadParams] {
-	inputFileName := c.String("input")
-	if inputFileName == "" {
-		return withFailure[LoadParams]("input file path required (-i or --input)")
+	environmentResult := resolveEnvironment(c)
+	if environmentResult.IsFailure() {
+		return functional.Failure[LoadParams](environmentResult.GetError())
 	}
+	inputFileName := environmentResult.Unwrap().InputFile
 
 	// Handle output file parameter
 	outputFileName := c.String("output")
@@ -0,0 +1,98 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/lock"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+// LockParams contains parameters for the Lock command
+type LockParams struct {
+	InputFileName string
+	Endpoints     Endpoints
+}
+
+// WithLockParams creates a new LockParams with provided values
+func WithLockParams(inputFileName string, endpoints Endpoints) LockParams {
+	return LockParams{
+		InputFileName: inputFileName,
+		Endpoints:     endpoints,
+	}
+}
+
+// LockResult represents the result of writing a lockfile
+type LockResult struct {
+	LockFileName string
+	PinnedCount  int
+	Changes      []lock.Change
+}
+
+// Lock resolves every secret of the input file and pins it to its concrete version in a lockfile
+func Lock(c *cli.Context) error {
+	paramsResult := functional.Chain(resolveEnvironment(c), func(environment profile.Environment) functional.Result[LockParams] {
+		return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) LockParams {
+			return WithLockParams(environment.InputFile, endpoints)
+		})
+	})
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+
+	result := performLock(paramsResult.Unwrap())
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	displayLockResult(result.Unwrap())
+	return nil
+}
+
+// performLock resolves the secrets of the input file and writes their versions to the lockfile
+func performLock(params LockParams) functional.Result[LockResult] {
+	logInfoMsg(fmt.Sprintf("Reading input file: %s", params.InputFileName))
+
+	entriesResult := readInputFile(params.InputFileName)
+	if entriesResult.IsFailure() {
+		return withFailure[LockResult](entriesResult.GetError().Error())
+	}
+
+	// Only the versions matter here, so the default expansion is used
+	resolveResult := resolveSecrets(entriesResult.Unwrap(), params.Endpoints, expand.DefaultOptions())
+	if resolveResult.IsFailure() {
+		return withFailure[LockResult](resolveResult.GetError().Error())
+	}
+
+	lockFileName := lock.FileName(params.InputFileName)
+	previousResult := lock.ReadResult(lockFileName)
+	if previousResult.IsFailure() {
+		return withFailure[LockResult](previousResult.GetError().Error())
+	}
+
+	lockfile := lock.New(params.InputFileName, resolveResult.Unwrap().Versions)
+	writeResult := lock.WriteResult(lockFileName, lockfile)
+	if writeResult.IsFailure() {
+		return withFailure[LockResult](writeResult.GetError().Error())
+	}
+
+	return withSuccess(LockResult{
+		LockFileName: lockFileName,
+		PinnedCount:  len(lockfile.Secrets),
+		Changes:      lock.Diff(previousResult.Unwrap().UnwrapOr(lock.Lockfile{}), lockfile),
+	})
+}
+
+// displayLockResult prints the pinned versions that changed since the previous lockfile
+func displayLockResult(result LockResult) {
+	for _, change := range result.Changes {
+		logInfoMsg(change.String())
+	}
+	if len(result.Changes) == 0 {
+		logInfoMsg("No version changes since the previous lock")
+	}
+	logSuccessInfo(fmt.Sprintf("Pinned %d secrets in %s", result.PinnedCount, result.LockFileName))
+}
@@ -0,0 +1,234 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/migrate"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v2"
+)
+
+// MigrateParams contains parameters for the Migrate command
+type MigrateParams struct {
+	InputFileName string
+	Destination   migrate.Destination
+	DryRun        bool
+	Endpoints     Endpoints
+	AssumeYes     bool
+}
+
+// WithMigrateParams creates a new MigrateParams with provided values
+func WithMigrateParams(inputFileName string, destination migrate.Destination, dryRun bool, endpoints Endpoints, assumeYes bool) MigrateParams {
+	return MigrateParams{
+		InputFileName: inputFileName,
+		Destination:   destination,
+		DryRun:        dryRun,
+		Endpoints:     endpoints,
+		AssumeYes:     assumeYes,
+	}
+}
+
+// Copy statuses reported by migrate
+const (
+	copyPending = "copy"     // The destination does not exist yet
+	copyDone    = "copied"   // The destination already holds the same value
+	copyBlocked = "conflict" // The value cannot be written to the destination
+)
+
+// MigrationCopy is a secret copy with the value read from the source and its status
+type MigrationCopy struct {
+	migrate.Copy
+	Value    string
+	Status   string
+	Problems []string
+}
+
+// Migrate copies every secret referenced by an env file to another platform or account and rewrites their URIs
+func Migrate(c *cli.Context) error {
+	paramsResult := validateMigrateParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	content, err := os.ReadFile(params.InputFileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", params.InputFileName, err)
+	}
+
+	planResult := migrate.NewPlanResult(content, params.Destination)
+	if planResult.IsFailure() {
+		return fmt.Errorf("failed to parse env file '%s': %w", params.InputFileName, planResult.GetError())
+	}
+	plan := planResult.Unwrap()
+	if len(plan.Copies) == 0 {
+		logInfoMsg(fmt.Sprintf("No secrets to migrate to %s in %s", params.Destination, params.InputFileName))
+		return nil
+	}
+
+	copies := inspectCopies(plan.Copies, params.Endpoints)
+	problems := append(plan.Problems, copyProblems(copies)...)
+	displayMigrationPlan(params.InputFileName, plan, copies, problems)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("cannot migrate %s: resolve the %d problems above first", params.InputFileName, len(problems))
+	}
+	if params.DryRun {
+		return nil
+	}
+
+	confirmResult := confirmMigrate(params, len(copies))
+	if confirmResult.IsFailure() {
+		return confirmResult.GetError()
+	}
+
+	result := performMigrate(params, plan, copies, content)
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	logSuccessInfo(fmt.Sprintf("Migrated %d secrets to %s and rewrote %s", len(copies), params.Destination, params.InputFileName))
+	return nil
+}
+
+// validateMigrateParams validates CLI parameters and returns a Result monad
+func validateMigrateParams(c *cli.Context) functional.Result[MigrateParams] {
+	if c.String("to") == "" {
+		return withFailure[MigrateParams]("--to is required (e.g. --to aws:secretsmanager/<profile>)")
+	}
+
+	environmentResult := resolveEnvironment(c)
+	if environmentResult.IsFailure() {
+		return functional.Failure[MigrateParams](environmentResult.GetError())
+	}
+	inputFileName := environmentResult.Unwrap().InputFile
+
+	// Structured files cannot be rewritten line by line
+	if fileType := fileio.DetermineFileType(inputFileName); fileType == fileio.YamlFile || fileType == fileio.JsonFile {
+		return withFailure[MigrateParams](fmt.Sprintf("'%s' is not a plain env file", inputFileName))
+	}
+
+	destinationResult := migrate.ParseDestinationResult(c.String("to"))
+	if destinationResult.IsFailure() {
+		return functional.Failure[MigrateParams](destinationResult.GetError())
+	}
+
+	return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) MigrateParams {
+		return WithMigrateParams(inputFileName, destinationResult.Unwrap(), c.Bool("dry-run"), endpoints, c.Bool("yes"))
+	})
+}
+
+// inspectCopies reads each source value and checks that it fits the destination without overwriting another value
+func inspectCopies(copies []migrate.Copy, endpoints Endpoints) []MigrationCopy {
+	return functional.Map(copies, func(c migrate.Copy) MigrationCopy {
+		inspected := MigrationCopy{Copy: c, Status: copyBlocked}
+
+		valueResult := readSecretValue(c.Source, endpoints)
+		if valueResult.IsFailure() {
+			inspected.Problems = []string{valueResult.GetError().Error()}
+			return inspected
+		}
+		inspected.Value = valueResult.Unwrap()
+
+		inspected.Problems = migrate.CheckValue(c.Destination.Platform, inspected.Value)
+		if len(inspected.Problems) > 0 {
+			return inspected
+		}
+
+		currentResult := readCurrentSecret(c.Destination, endpoints)
+		switch {
+		case currentResult.IsFailure():
+			inspected.Problems = []string{currentResult.GetError().Error()}
+		case currentResult.Unwrap().IsNone():
+			inspected.Status = copyPending
+		case currentResult.Unwrap().Unwrap() == inspected.Value:
+			inspected.Status = copyDone
+		default:
+			inspected.Problems = []string{"the destination already exists with a different value"}
+		}
+		return inspected
+	})
+}
+
+// copyProblems lists the problems of each copy, prefixed with its source
+func copyProblems(copies []MigrationCopy) []string {
+	problems := []string{}
+	for _, c := range copies {
+		for _, problem := range c.Problems {
+			problems = append(problems, fmt.Sprintf("%s: %s", c.Source.SecretName, problem))
+		}
+	}
+	return problems
+}
+
+// displayMigrationPlan prints each copy with its status, the problems found and the rewritten lines
+func displayMigrationPlan(inputFileName string, plan migrate.Plan, copies []MigrationCopy, problems []string) {
+	fmt.Println(formatting.FormatHeader("Secrets to migrate"))
+	for _, c := range copies {
+		status := formatting.Success("[%s]", c.Status)
+		if c.Status == copyBlocked {
+			status = formatting.Error("[%s]", c.Status)
+		}
+		fmt.Printf("  %s -> %s %s\n", c.Source.Reference(), c.Destination.Reference(), status)
+	}
+
+	if len(problems) > 0 {
+		fmt.Println(formatting.FormatHeader("\nProblems"))
+		for _, problem := range problems {
+			fmt.Println(formatting.Error("  %s", problem))
+		}
+	}
+
+	fmt.Println(formatting.FormatHeader("\nChanges to %s", inputFileName))
+	for _, edit := range plan.Edits {
+		fmt.Println(formatting.Error("-%d: %s", edit.Number, edit.Before))
+		fmt.Println(formatting.Success("+%d: %s", edit.Number, edit.After))
+	}
+	fmt.Println()
+}
+
+// confirmMigrate asks for confirmation before writing the secrets and the env file
+func confirmMigrate(params MigrateParams, count int) functional.Result[bool] {
+	if params.AssumeYes {
+		return withSuccess(true)
+	}
+
+	label := fmt.Sprintf("Copy %d secrets to %s and rewrite %s", count, params.Destination, params.InputFileName)
+	if profile.IsProduction(params.Destination.Account) {
+		label = fmt.Sprintf("'%s' is a production account. %s", params.Destination.Account, label)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+	if _, err := prompt.Run(); err != nil {
+		return withFailure[bool]("aborted: nothing was written (use --yes to skip the prompt)")
+	}
+	return withSuccess(true)
+}
+
+// performMigrate writes the secrets that are not copied yet, then rewrites the env file
+func performMigrate(params MigrateParams, plan migrate.Plan, copies []MigrationCopy, content []byte) functional.Result[bool] {
+	for i, c := range copies {
+		if c.Status == copyDone {
+			continue
+		}
+		writeResult := writeSecret(c.Destination, c.Value, params.Endpoints)
+		if writeResult.IsFailure() {
+			return withFailure[bool](fmt.Sprintf("%v (%d of %d secrets were copied, %s was not changed; run migrate again to resume)",
+				writeResult.GetError(), i, len(copies), params.InputFileName))
+		}
+		displayWritten(c.Destination, writeResult.Unwrap())
+	}
+
+	logInfoMsg(fmt.Sprintf("Rewriting %d lines of %s", len(plan.Edits), params.InputFileName))
+	return rewriteFile(params.InputFileName, parser.ApplyLineEdits(content, plan.Edits))
+}
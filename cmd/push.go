@@ -0,0 +1,196 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/push"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v2"
+)
+
+// maskedValue replaces plaintext values in diffs so they do not end up in terminal scrollback or CI logs
+const maskedValue = "********"
+
+// PushParams contains parameters for the Push command
+type PushParams struct {
+	InputFileName string
+	Target        uri.SecretURI
+	PerKey        bool // One secret per variable instead of one JSON secret
+	DryRun        bool
+	Endpoints     Endpoints
+	AssumeYes     bool
+}
+
+// WithPushParams creates a new PushParams with provided values
+func WithPushParams(inputFileName string, target uri.SecretURI, perKey, dryRun bool, endpoints Endpoints, assumeYes bool) PushParams {
+	return PushParams{
+		InputFileName: inputFileName,
+		Target:        target,
+		PerKey:        perKey,
+		DryRun:        dryRun,
+		Endpoints:     endpoints,
+		AssumeYes:     assumeYes,
+	}
+}
+
+// Push moves the plaintext values of an env file into a cloud secret and replaces them with secret URIs
+func Push(c *cli.Context) error {
+	paramsResult := validatePushParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	content, err := os.ReadFile(params.InputFileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", params.InputFileName, err)
+	}
+
+	planResult := push.NewPlanResult(content, params.Target, params.PerKey)
+	if planResult.IsFailure() {
+		return fmt.Errorf("failed to parse env file '%s': %w", params.InputFileName, planResult.GetError())
+	}
+	plan := planResult.Unwrap()
+
+	displayPushPlan(params.InputFileName, plan)
+	if len(plan.Variables) == 0 || params.DryRun {
+		return nil
+	}
+
+	confirmResult := confirmPush(params, plan)
+	if confirmResult.IsFailure() {
+		return confirmResult.GetError()
+	}
+
+	result := performPush(params, plan, content)
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	logSuccessInfo(fmt.Sprintf("Moved %d variables to secrets and rewrote %s", len(plan.Variables), params.InputFileName))
+	logInfoMsg(fmt.Sprintf("Run 'sem update -i %s' to fetch them; the old values remain in the git history if the file was committed",
+		params.InputFileName))
+	return nil
+}
+
+// validatePushParams validates CLI parameters and returns a Result monad
+func validatePushParams(c *cli.Context) functional.Result[PushParams] {
+	if c.String("to") == "" {
+		return withFailure[PushParams]("--to is required (e.g. --to sem://aws:secretsmanager/<profile>/<secret>)")
+	}
+
+	environmentResult := resolveEnvironment(c)
+	if environmentResult.IsFailure() {
+		return functional.Failure[PushParams](environmentResult.GetError())
+	}
+	inputFileName := environmentResult.Unwrap().InputFile
+
+	// Structured files cannot be rewritten line by line
+	if fileType := fileio.DetermineFileType(inputFileName); fileType == fileio.YamlFile || fileType == fileio.JsonFile {
+		return withFailure[PushParams](fmt.Sprintf("'%s' is not a plain env file", inputFileName))
+	}
+
+	targetResult := writableURIResult(c.String("to"))
+	if targetResult.IsFailure() {
+		return functional.Failure[PushParams](targetResult.GetError())
+	}
+
+	return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) PushParams {
+		return WithPushParams(inputFileName, targetResult.Unwrap(), c.Bool("per-key"), c.Bool("dry-run"), endpoints, c.Bool("yes"))
+	})
+}
+
+// displayPushPlan prints the lines left as they are, the secrets to write and the rewritten lines with masked values
+func displayPushPlan(inputFileName string, plan push.Plan) {
+	for _, skipped := range plan.Skipped {
+		logWarning(fmt.Sprintf("Skipping %s", skipped))
+	}
+	if len(plan.Variables) == 0 {
+		logInfoMsg(fmt.Sprintf("No plaintext values to push in %s", inputFileName))
+		return
+	}
+
+	fmt.Println(formatting.FormatHeader("Secrets to write"))
+	if plan.PerKey {
+		for _, variable := range plan.Variables {
+			fmt.Printf("  %s\n", plan.SecretURI(variable).SecretName)
+		}
+	} else {
+		fmt.Printf("  %s (%d keys, merged into its current value if it exists)\n", plan.Target.SecretName, len(plan.Variables))
+	}
+
+	fmt.Println(formatting.FormatHeader("\nChanges to %s", inputFileName))
+	for _, edit := range plan.Edits() {
+		fmt.Println(formatting.Error("-%d: %s", edit.Number, parser.ReplaceLineValue(edit.Before, maskedValue)))
+		fmt.Println(formatting.Success("+%d: %s", edit.Number, edit.After))
+	}
+	fmt.Println()
+}
+
+// confirmPush asks for confirmation before writing the secrets and the env file
+func confirmPush(params PushParams, plan push.Plan) functional.Result[bool] {
+	if params.AssumeYes {
+		return withSuccess(true)
+	}
+
+	label := fmt.Sprintf("Write %d variables to secrets and rewrite %s", len(plan.Variables), params.InputFileName)
+	if profile.IsProduction(params.Target.Account) {
+		label = fmt.Sprintf("'%s' is a production account. %s", params.Target.Account, label)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+	if _, err := prompt.Run(); err != nil {
+		return withFailure[bool]("aborted: nothing was written (use --yes to skip the prompt)")
+	}
+	return withSuccess(true)
+}
+
+// performPush writes the secrets, then rewrites the env file so that it is only changed once every secret is stored
+func performPush(params PushParams, plan push.Plan, content []byte) functional.Result[bool] {
+	current := functional.Success(functional.None[string]())
+	if !plan.PerKey {
+		current = readCurrentSecret(params.Target, params.Endpoints)
+		if current.IsFailure() {
+			return functional.Failure[bool](current.GetError())
+		}
+	}
+
+	writesResult := plan.WritesResult(current.Unwrap())
+	if writesResult.IsFailure() {
+		return functional.Failure[bool](writesResult.GetError())
+	}
+
+	for i, write := range writesResult.Unwrap() {
+		writeResult := writeSecret(write.URI, write.Value, params.Endpoints)
+		if writeResult.IsFailure() {
+			return withFailure[bool](fmt.Sprintf("%v (%d of %d secrets were written, %s was not changed)",
+				writeResult.GetError(), i, len(writesResult.Unwrap()), params.InputFileName))
+		}
+		displayWritten(write.URI, writeResult.Unwrap())
+	}
+
+	return rewriteFile(params.InputFileName, parser.ApplyLineEdits(content, plan.Edits()))
+}
+
+// rewriteFile replaces the content of a file, keeping its permissions
+func rewriteFile(fileName string, content []byte) functional.Result[bool] {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return withFailure[bool](fmt.Sprintf("failed to stat file '%s': %v", fileName, err))
+	}
+	if err := os.WriteFile(fileName, content, info.Mode().Perm()); err != nil {
+		return withFailure[bool](fmt.Sprintf("failed to write file '%s': %v", fileName, err))
+	}
+	return withSuccess(true)
+}
@@ -0,0 +1,203 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
+	secretvalue "github.com/gumi-tsd/secret-env-manager/internal/secret"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v2"
+)
+
+// PutParams contains parameters for the Put command
+type PutParams struct {
+	SecretURI     uri.SecretURI
+	ValueFileName string // File to read the value from (stdin when empty)
+	Endpoints     Endpoints
+	AssumeYes     bool
+}
+
+// WithPutParams creates a new PutParams with provided values
+func WithPutParams(secretURI uri.SecretURI, valueFileName string, endpoints Endpoints, assumeYes bool) PutParams {
+	return PutParams{
+		SecretURI:     secretURI,
+		ValueFileName: valueFileName,
+		Endpoints:     endpoints,
+		AssumeYes:     assumeYes,
+	}
+}
+
+// Put creates or updates the secret referenced by a URI with a value read from stdin or a file.
+// With ?key=, only that key of the JSON secret is set and its other keys are kept.
+func Put(c *cli.Context) error {
+	paramsResult := validatePutParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	valueResult := readPutValue(params.ValueFileName)
+	if valueResult.IsFailure() {
+		return valueResult.GetError()
+	}
+
+	confirmResult := confirmWrite(params.SecretURI, params.AssumeYes)
+	if confirmResult.IsFailure() {
+		return confirmResult.GetError()
+	}
+
+	writeResult := putSecret(params.SecretURI, valueResult.Unwrap(), params.Endpoints)
+	if writeResult.IsFailure() {
+		return writeResult.GetError()
+	}
+
+	displayWritten(params.SecretURI, writeResult.Unwrap())
+	return nil
+}
+
+// validatePutParams validates CLI parameters and returns a Result monad
+func validatePutParams(c *cli.Context) functional.Result[PutParams] {
+	if c.NArg() != 1 {
+		return withFailure[PutParams]("usage: sem put <uri>")
+	}
+
+	uriResult := writableURIResult(c.Args().First())
+	if uriResult.IsFailure() {
+		return functional.Failure[PutParams](uriResult.GetError())
+	}
+
+	return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) PutParams {
+		return WithPutParams(uriResult.Unwrap(), c.String("file"), endpoints, c.Bool("yes"))
+	})
+}
+
+// writableURIResult parses a URI that secrets can be written to.
+// New versions always become the current one, so a URI selecting another version is rejected.
+// The URI is fully validated, so credentials of another platform are not used for the write.
+func writableURIResult(rawURI string) functional.Result[uri.SecretURI] {
+	uriResult := uri.ValidateResult(rawURI)
+	if uriResult.IsFailure() {
+		return uriResult
+	}
+	secretURI := uriResult.Unwrap()
+
+	switch {
+	case secretURI.Platform == uri.AwsPlatform && secretURI.Version != uri.AwsDefaultVersion,
+		secretURI.Platform == uri.GoogleCloudPlatform && secretURI.Version != uri.GoogleCloudDefaultVersion:
+		return withFailure[uri.SecretURI](fmt.Sprintf(
+			"cannot write to version '%s': a new version always becomes the current one (remove ?version=)", secretURI.Version))
+	case secretURI.Platform != uri.AwsPlatform && secretURI.Platform != uri.GoogleCloudPlatform:
+		return withFailure[uri.SecretURI](fmt.Sprintf("unsupported platform '%s'", secretURI.Platform))
+	}
+	return withSuccess(secretURI)
+}
+
+// readPutValue reads the value from a file, or from stdin with a single trailing newline removed.
+// When stdin is a terminal, the value is prompted for without echoing it.
+func readPutValue(fileName string) functional.Result[string] {
+	if fileName != "" {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return withFailure[string](fmt.Sprintf("failed to read value file '%s': %v", fileName, err))
+		}
+		return nonEmptyValue(string(data))
+	}
+
+	if formatting.IsTerminal(os.Stdin) {
+		prompt := promptui.Prompt{
+			Label: "Secret value",
+			Mask:  '*',
+		}
+		value, err := prompt.Run()
+		if err != nil {
+			return withFailure[string](fmt.Sprintf("value input failed: %v", err))
+		}
+		return nonEmptyValue(value)
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return withFailure[string](fmt.Sprintf("failed to read value from stdin: %v", err))
+	}
+	value := strings.TrimSuffix(string(data), "\n")
+	return nonEmptyValue(strings.TrimSuffix(value, "\r"))
+}
+
+// nonEmptyValue rejects empty values, which usually mean the input was not provided
+func nonEmptyValue(value string) functional.Result[string] {
+	if value == "" {
+		return withFailure[string]("refusing to store an empty value")
+	}
+	return withSuccess(value)
+}
+
+// confirmWrite asks for confirmation before writing to a production account (AWS profile or Google Cloud project)
+func confirmWrite(secretURI uri.SecretURI, assumeYes bool) functional.Result[bool] {
+	account := profile.Environment{
+		Name:       secretURI.Account,
+		Production: profile.IsProduction(secretURI.Account),
+	}
+	return confirmProduction(account, fmt.Sprintf("Write secret '%s'", secretURI.SecretName), assumeYes)
+}
+
+// putSecret writes a value to the secret referenced by a URI, merging it into the JSON secret when the URI has a key
+func putSecret(secretURI uri.SecretURI, value string, endpoints Endpoints) functional.Result[secret.Written] {
+	if secretURI.Key != "" {
+		currentResult := readCurrentSecret(secretURI, endpoints)
+		if currentResult.IsFailure() {
+			return functional.Failure[secret.Written](currentResult.GetError())
+		}
+
+		mergedResult := secretvalue.SetKeyResult(currentResult.Unwrap(), secretURI.Key, value)
+		if mergedResult.IsFailure() {
+			return withFailure[secret.Written](fmt.Sprintf("cannot set key '%s' of secret '%s': %v",
+				secretURI.Key, secretURI.SecretName, mergedResult.GetError()))
+		}
+		value = mergedResult.Unwrap()
+	}
+
+	return writeSecret(secretURI, value, endpoints)
+}
+
+// readCurrentSecret fetches the current value of the secret referenced by a URI. A missing secret is reported as None.
+func readCurrentSecret(secretURI uri.SecretURI, endpoints Endpoints) functional.Result[functional.Option[string]] {
+	ctx := context.Background()
+
+	if secretURI.Platform == uri.AwsPlatform {
+		return aws.NewAwsProvider().ReadCurrentValueResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+	}
+	return googlecloud.NewGoogleCloudProvider().ReadCurrentValueResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+}
+
+// writeSecret stores a value as the new current version of the secret referenced by a URI, creating the secret if needed
+func writeSecret(secretURI uri.SecretURI, value string, endpoints Endpoints) functional.Result[secret.Written] {
+	ctx := context.Background()
+
+	if secretURI.Platform == uri.AwsPlatform {
+		return aws.NewAwsProvider().PutSecretValueResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, value, endpoints.For(secretURI))
+	}
+	return googlecloud.NewGoogleCloudProvider().AddSecretVersionResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, value, endpoints.For(secretURI))
+}
+
+// displayWritten prints the version created by a write
+func displayWritten(secretURI uri.SecretURI, written secret.Written) {
+	if written.Created {
+		logSuccessInfo(fmt.Sprintf("Created secret %s (version %s)", secretURI.SecretName, written.VersionID))
+	} else {
+		logSuccessInfo(fmt.Sprintf("Stored a new version of %s (version %s)", secretURI.SecretName, written.VersionID))
+	}
+	if secretURI.Key != "" {
+		logInfoMsg(fmt.Sprintf("Set key '%s'", secretURI.Key))
+	}
+}
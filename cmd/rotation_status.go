@@ -0,0 +1,182 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
+	"github.com/urfave/cli/v2"
+)
+
+// RotationStatusParams contains parameters for the RotationStatus command
+type RotationStatusParams struct {
+	InputFileName string
+	Endpoints     Endpoints
+}
+
+// WithRotationStatusParams creates a new RotationStatusParams with provided values
+func WithRotationStatusParams(inputFileName string, endpoints Endpoints) RotationStatusParams {
+	return RotationStatusParams{
+		InputFileName: inputFileName,
+		Endpoints:     endpoints,
+	}
+}
+
+// SecretRotation is the rotation status of a secret referenced by the input file
+type SecretRotation struct {
+	Reference string
+	Rotation  functional.Result[secret.Rotation]
+}
+
+// RotationStatus reports the rotation configuration and last-rotated dates of every secret referenced by the input file
+func RotationStatus(c *cli.Context) error {
+	paramsResult := functional.Chain(resolveEnvironment(c), func(environment profile.Environment) functional.Result[RotationStatusParams] {
+		return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) RotationStatusParams {
+			return WithRotationStatusParams(environment.InputFile, endpoints)
+		})
+	})
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	entriesResult := readInputFile(params.InputFileName)
+	if entriesResult.IsFailure() {
+		return entriesResult.GetError()
+	}
+
+	rotations := describeRotations(referencedSecrets(entriesResult.Unwrap()), params.Endpoints)
+
+	// Secrets that could not be described or need attention fail the command, so that it can gate CI
+	if attention := displayRotations(rotations, time.Now()); attention > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// referencedSecrets returns the AWS and Google Cloud secrets referenced by the entries, once per secret in input order
+func referencedSecrets(entries []modelenv.Entry) []uri.SecretURI {
+	seen := map[string]bool{}
+	secrets := []uri.SecretURI{}
+	for _, entry := range entries {
+		uriResult := provider.ParseEntryAsSecretURI(entry)
+		if uriResult.IsFailure() {
+			continue
+		}
+		secretURI := uriResult.Unwrap()
+		if secretURI.Platform != uri.AwsPlatform && secretURI.Platform != uri.GoogleCloudPlatform {
+			continue
+		}
+
+		// Rotation is configured per secret, regardless of the requested version or key
+		reference := rotationReference(secretURI)
+		if seen[reference] {
+			continue
+		}
+		seen[reference] = true
+		secrets = append(secrets, secretURI)
+	}
+	return secrets
+}
+
+// describeRotations looks up the rotation status of each secret from its provider
+func describeRotations(secrets []uri.SecretURI, endpoints Endpoints) []SecretRotation {
+	ctx := context.Background()
+	awsProvider := aws.NewAwsProvider()
+	googleCloudProvider := googlecloud.NewGoogleCloudProvider()
+
+	return functional.Map(secrets, func(secretURI uri.SecretURI) SecretRotation {
+		var rotation functional.Result[secret.Rotation]
+		if secretURI.Platform == uri.AwsPlatform {
+			rotation = awsProvider.DescribeRotationResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+		} else {
+			rotation = googleCloudProvider.DescribeRotationResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+		}
+		return SecretRotation{Reference: rotationReference(secretURI), Rotation: rotation}
+	})
+}
+
+// rotationReference formats the secret a rotation status belongs to
+func rotationReference(secretURI uri.SecretURI) string {
+	reference := uri.NewSecretURI(secretURI.Platform, secretURI.Service, secretURI.Account, secretURI.SecretName).GetUri()
+	if secretURI.Region != "" {
+		return fmt.Sprintf("%s?region=%s", reference, secretURI.Region)
+	}
+	return reference
+}
+
+// displayRotations prints the rotation status of each secret and a summary of the secrets needing attention,
+// and returns the number of secrets that could not be described or need attention
+func displayRotations(rotations []SecretRotation, now time.Time) int {
+	if len(rotations) == 0 {
+		logInfoMsg("No AWS or Google Cloud secrets are referenced by the input file")
+		return 0
+	}
+
+	attention := 0
+	for _, entry := range rotations {
+		if entry.Rotation.IsFailure() {
+			fmt.Println(formatting.ColorizeKey(entry.Reference))
+			fmt.Println(formatting.Error("  Unable to describe rotation: %v", entry.Rotation.GetError()))
+			attention++
+			continue
+		}
+
+		rotation := entry.Rotation.Unwrap()
+		status := rotation.Status(now)
+		fmt.Printf("%s %s\n", formatting.ColorizeKey(entry.Reference), formatRotationStatus(status))
+		if rotation.Enabled {
+			fmt.Printf("  Rotator:       %s\n", valueOrDash(rotation.Rotator))
+			fmt.Printf("  Schedule:      %s\n", valueOrDash(rotation.Schedule))
+		}
+		fmt.Printf("  Last rotated:  %s\n", formatCreatedAt(rotation.LastRotatedAt))
+		if rotation.Enabled {
+			fmt.Printf("  Next rotation: %s\n", formatCreatedAt(rotation.NextRotationAt))
+		}
+		if rotation.PendingVersion != "" {
+			fmt.Printf("  Pending:       %s (test it with 'sem update --stage %s')\n", rotation.PendingVersion, aws.PendingStage)
+		}
+
+		if status == secret.RotationOverdue || status == secret.RotationPending {
+			attention++
+		}
+	}
+
+	fmt.Println()
+	if attention > 0 {
+		logWarning(fmt.Sprintf("%d of %d secrets need attention", attention, len(rotations)))
+		return attention
+	}
+	logSuccessInfo(fmt.Sprintf("Checked the rotation of %d secrets", len(rotations)))
+	return 0
+}
+
+// formatRotationStatus colors a rotation status for display
+func formatRotationStatus(status string) string {
+	switch status {
+	case secret.RotationOK:
+		return formatting.Success("[%s]", status)
+	case secret.RotationDisabled:
+		return formatting.Hint("[%s]", status)
+	default:
+		return formatting.Warning("[%s]", status)
+	}
+}
+
+// valueOrDash returns the value, or "-" when it is empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
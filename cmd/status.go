@@ -0,0 +1,111 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+// StatusResult represents the result of a status inspection
+type StatusResult struct {
+	Active   functional.Option[string]
+	Current  profile.Status
+	Statuses []profile.Status
+}
+
+// Status shows the active environment and the freshness of each environment's cache
+func Status(c *cli.Context) error {
+	result := collectStatus(c)
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	displayStatus(result.Unwrap(), time.Now())
+	return nil
+}
+
+// collectStatus gathers the status of the selected environment and all discovered environments
+func collectStatus(c *cli.Context) functional.Result[StatusResult] {
+	activeResult := profile.ReadActive()
+	if activeResult.IsFailure() {
+		return functional.Failure[StatusResult](activeResult.GetError())
+	}
+
+	environmentResult := resolveEnvironment(c)
+	if environmentResult.IsFailure() {
+		return functional.Failure[StatusResult](environmentResult.GetError())
+	}
+
+	discoverResult := profile.Discover(".")
+	if discoverResult.IsFailure() {
+		return functional.Failure[StatusResult](discoverResult.GetError())
+	}
+
+	return withSuccess(StatusResult{
+		Active:   activeResult.Unwrap(),
+		Current:  profile.GetStatus(environmentResult.Unwrap()),
+		Statuses: functional.Map(discoverResult.Unwrap(), profile.GetStatus),
+	})
+}
+
+// displayStatus prints the status report to standard output
+func displayStatus(result StatusResult, now time.Time) {
+	if result.Active.IsSome() {
+		fmt.Printf("Active environment: %s\n", formatting.ColorizeKey(result.Active.Unwrap()))
+	} else {
+		fmt.Println(formatting.Hint("No active environment (select one with 'sem use <env>')"))
+	}
+
+	fmt.Println(formatting.FormatHeader("\nSelected Environment"))
+	displayEnvironmentStatus(result.Current, now)
+
+	if len(result.Statuses) == 0 {
+		return
+	}
+
+	fmt.Println(formatting.FormatHeader("\nEnvironments"))
+	for _, status := range result.Statuses {
+		marker := "  "
+		if status.Environment.Name == result.Current.Environment.Name {
+			marker = "* "
+		}
+		fmt.Printf("%s%-20s %s\n", marker, status.Environment.Name, formatCacheSummary(status, now))
+	}
+}
+
+// displayEnvironmentStatus prints the details of a single environment
+func displayEnvironmentStatus(status profile.Status, now time.Time) {
+	environment := status.Environment
+	name := environment.Name
+	if environment.Production {
+		name += " " + formatting.Warning("(production)")
+	}
+
+	fmt.Printf("  Name:       %s\n", name)
+	fmt.Printf("  Input file: %s", environment.InputFile)
+	if !status.InputExists {
+		fmt.Print(formatting.Warning(" (not found)"))
+	}
+	fmt.Println()
+	fmt.Printf("  Cache file: %s\n", environment.CacheFile)
+	fmt.Printf("  Cache:      %s\n", formatCacheSummary(status, now))
+}
+
+// formatCacheSummary formats the cache state and age of an environment
+func formatCacheSummary(status profile.Status, now time.Time) string {
+	switch status.CacheState {
+	case profile.CacheMissing:
+		return formatting.Warning("%s", status.CacheState)
+	case profile.CacheStale:
+		return formatting.Warning("%s, updated %s", status.CacheState, profile.FormatAge(status.Age(now)))
+	default:
+		return formatting.Success("%s", status.CacheState) +
+			fmt.Sprintf(", updated %s (%s)",
+				profile.FormatAge(status.Age(now)), status.CacheUpdated.Format("2006-01-02 15:04:05"))
+	}
+}
@@ -3,30 +3,47 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gumi-tsd/secret-env-manager/internal/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
 	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/lock"
 	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
 	"github.com/gumi-tsd/secret-env-manager/internal/provider"
 	"github.com/urfave/cli/v2"
 )
 
 // UpdateParams contains parameters for the Update command
 type UpdateParams struct {
-	InputFileName string
-	EndpointURL   string
-	NoQuotes      bool
-	NoExpandJson  bool
+	InputFileName  string
+	SchemaFileName string
+	Endpoints      Endpoints
+	NoQuotes       bool
+	Strict         bool
+	LenientURIs    bool   // Only warn about invalid secret URIs instead of failing
+	Locked         bool   // Fetch the versions pinned in the lockfile
+	Stage          string // Version stage fetched for every AWS secret (e.g. AWSPENDING)
+	Expand         expand.Options
 }
 
 // WithUpdateParams creates a new UpdateParams with provided values
-func WithUpdateParams(inputFileName, endpointURL string, noQuotes, noExpandJson bool) UpdateParams {
+func WithUpdateParams(inputFileName, schemaFileName string, endpoints Endpoints, noQuotes, strict, lenientURIs, locked bool, stage string, expandOptions expand.Options) UpdateParams {
 	return UpdateParams{
-		InputFileName: inputFileName,
-		EndpointURL:   endpointURL,
-		NoQuotes:      noQuotes,
-		NoExpandJson:  noExpandJson,
+		InputFileName:  inputFileName,
+		SchemaFileName: schemaFileName,
+		Endpoints:      endpoints,
+		NoQuotes:       noQuotes,
+		Strict:         strict,
+		LenientURIs:    lenientURIs,
+		Locked:         locked,
+		Stage:          stage,
+		Expand:         expandOptions,
 	}
 }
 
@@ -46,8 +63,10 @@ func WithUpdateResult(outputFileName string, entryCount int) UpdateResult {
 
 // AcquiredSecrets holds the results of secret acquisition
 type AcquiredSecrets struct {
-	Values map[string]string
-	Keys   []string
+	Values     map[string]string
+	Keys       []string
+	Origins    map[string]provider.Origin // Provenance of each key
+	Collisions []provider.Collision
 }
 
 // WithAcquiredSecrets creates a new AcquiredSecrets
@@ -82,20 +101,45 @@ func Update(c *cli.Context) error {
 
 // validateUpdateParams validates CLI parameters and returns a Result monad
 func validateUpdateParams(c *cli.Context) functional.Result[UpdateParams] {
-	inputFileName := c.String("input")
-	if inputFileName == "" {
-		return withFailure[UpdateParams]("input file path required (-i or --input)")
+	environmentResult := resolveEnvironment(c)
+	if environmentResult.IsFailure() {
+		return functional.Failure[UpdateParams](environmentResult.GetError())
 	}
+	environment := environmentResult.Unwrap()
 
-	endpointURL := c.String("endpoint-url")
+	// Production environments must be confirmed explicitly
+	confirmResult := confirmProduction(environment, "Update its secret cache", c.Bool("yes"))
+	if confirmResult.IsFailure() {
+		return functional.Failure[UpdateParams](confirmResult.GetError())
+	}
+
+	expandResult := resolveExpandOptions(c)
+	if expandResult.IsFailure() {
+		return functional.Failure[UpdateParams](expandResult.GetError())
+	}
+
+	// Pinned versions and a stage override would both replace the versions of the input file
+	if c.Bool("locked") && c.String("stage") != "" {
+		return withFailure[UpdateParams]("--locked and --stage cannot be used together")
+	}
+
+	endpointsResult := resolveEndpoints(c)
+	if endpointsResult.IsFailure() {
+		return functional.Failure[UpdateParams](endpointsResult.GetError())
+	}
+	endpoints := endpointsResult.Unwrap()
 	noQuotes := c.Bool("no-quotes")
-	noExpandJson := c.Bool("no-expand-json")
 
 	return withSuccess(WithUpdateParams(
-		inputFileName,
-		endpointURL,
+		environment.InputFile,
+		c.String("schema"),
+		endpoints,
 		noQuotes,
-		noExpandJson,
+		c.Bool("strict"),
+		c.Bool("lenient-uris"),
+		c.Bool("locked"),
+		c.String("stage"),
+		expandResult.Unwrap(),
 	))
 }
 
@@ -130,15 +174,42 @@ func performUpdate(params UpdateParams) functional.Result[UpdateResult] {
 	// Log entries for debugging
 	logDebugInfo(fmt.Sprintf("Found %d entries in input file", len(entries)))
 
+	// Replace version labels with the versions pinned in the lockfile
+	if params.Locked {
+		pinnedResult := applyLockfile(params.InputFileName, entries)
+		if pinnedResult.IsFailure() {
+			return withFailure[UpdateResult](pinnedResult.GetError().Error())
+		}
+		entries = pinnedResult.Unwrap()
+	}
+
+	// Fetch another stage of every AWS secret without editing the input file, e.g. to test a pending rotation
+	if params.Stage != "" {
+		logInfoMsg(fmt.Sprintf("Fetching stage %s of every AWS secret", params.Stage))
+		entries = provider.OverrideVersion(entries, uri.AwsPlatform, params.Stage)
+	}
+
+	// Load the schema before fetching secrets so that schema errors fail fast
+	schemaResult := loadSchema(entries, params.SchemaFileName)
+	if schemaResult.IsFailure() {
+		return withFailure[UpdateResult](schemaResult.GetError().Error())
+	}
+
 	// Acquire secrets
-	secretsResult := acquireSecrets(entries, params.EndpointURL, params.NoExpandJson)
+	secretsResult := acquireSecrets(entries, params.Endpoints, params.Expand, params.Strict, params.LenientURIs)
 	if secretsResult.IsFailure() {
 		return withFailure[UpdateResult](secretsResult.GetError().Error())
 	}
 	secrets := secretsResult.Unwrap()
 
+	// Validate resolved values before writing, so a rotated secret with a missing field fails the update
+	checkResult := checkSchema(schemaResult.Unwrap(), secrets.Values)
+	if checkResult.IsFailure() {
+		return withFailure[UpdateResult](checkResult.GetError().Error())
+	}
+
 	// Write to output file
-	writeResult := writeOutputFile(outputFileName, secrets.Values, secrets.Keys, params.NoQuotes, params.NoExpandJson)
+	writeResult := writeOutputFile(outputFileName, secrets.Values, secrets.Keys, params.NoQuotes)
 	if writeResult.IsFailure() {
 		return withFailure[UpdateResult](writeResult.GetError().Error())
 	}
@@ -149,6 +220,9 @@ func performUpdate(params UpdateParams) functional.Result[UpdateResult] {
 		return withFailure[UpdateResult](secureResult.GetError().Error())
 	}
 
+	// Record where each variable came from next to the cache file
+	writeMetadataWithWarning(params.InputFileName, outputFileName, secrets)
+
 	// Create and return result
 	return withSuccess(WithUpdateResult(
 		outputFileName,
@@ -156,6 +230,21 @@ func performUpdate(params UpdateParams) functional.Result[UpdateResult] {
 	))
 }
 
+// applyLockfile pins the secret URIs of the entries to the versions recorded in the lockfile of the input file
+func applyLockfile(inputFileName string, entries []modelenv.Entry) functional.Result[[]modelenv.Entry] {
+	lockFileName := lock.FileName(inputFileName)
+	lockResult := lock.ReadResult(lockFileName)
+	if lockResult.IsFailure() {
+		return functional.Failure[[]modelenv.Entry](lockResult.GetError())
+	}
+	if lockResult.Unwrap().IsNone() {
+		return withFailure[[]modelenv.Entry](fmt.Sprintf("lockfile '%s' not found (run 'sem lock' to create it)", lockFileName))
+	}
+
+	logInfoMsg(fmt.Sprintf("Using versions pinned in %s", lockFileName))
+	return lockResult.Unwrap().Unwrap().Apply(entries)
+}
+
 // readInputFile reads and parses the input file, returning env entries
 func readInputFile(fileName string) functional.Result[[]modelenv.Entry] {
 	return functional.Chain(
@@ -164,32 +253,112 @@ func readInputFile(fileName string) functional.Result[[]modelenv.Entry] {
 	)
 }
 
-// acquireSecrets fetches secrets from providers and organizes them by key
-func acquireSecrets(entries []modelenv.Entry, endpointURL string, noExpandJson bool) functional.Result[AcquiredSecrets] {
-	// Create provider configuration with endpoint URL and JSON expansion setting
-	config := provider.NewProviderConfig(endpointURL)
-	config.NoExpandJson = noExpandJson
+// resolveSecrets fetches secrets from providers, recording which entry produced each key
+func resolveSecrets(entries []modelenv.Entry, endpoints Endpoints, expandOptions expand.Options) functional.Result[provider.SecretResult] {
+	// Create provider configuration with endpoints and JSON expansion options
+	config := provider.NewProviderConfig("")
+	config.Endpoints = endpoints.Resolver
+	config.Expand = expandOptions
 
 	providers := provider.CreateProviderMap(config)
 
 	// Use provider's ProcessEntriesResult for secret processing
 	processResult := provider.ProcessEntriesResult(entries, providers)
 	if !processResult.IsSuccess() {
-		return withFailure[AcquiredSecrets](processResult.Error.Error())
+		return withFailure[provider.SecretResult](processResult.Error.Error())
+	}
+	return withSuccess(processResult)
+}
+
+// acquireSecrets fetches secrets from providers and organizes them by key
+func acquireSecrets(entries []modelenv.Entry, endpoints Endpoints, expandOptions expand.Options, strict, lenientURIs bool) functional.Result[AcquiredSecrets] {
+	// A mistyped secret URI would otherwise be written to the cache as a literal value
+	uriResult := checkEntryURIs(entries, lenientURIs)
+	if uriResult.IsFailure() {
+		return functional.Failure[AcquiredSecrets](uriResult.GetError())
+	}
+
+	resolveResult := resolveSecrets(entries, endpoints, expandOptions)
+	if resolveResult.IsFailure() {
+		return functional.Failure[AcquiredSecrets](resolveResult.GetError())
+	}
+	processResult := resolveResult.Unwrap()
+
+	// Keys produced by more than one entry would otherwise be silently overwritten
+	collisionResult := reportCollisions(processResult.Collisions, strict)
+	if collisionResult.IsFailure() {
+		return functional.Failure[AcquiredSecrets](collisionResult.GetError())
 	}
 
 	// Extract values and organize keys in order using the utility function
 	values := processResult.Values
+
+	// Required entries must resolve to a value or be set in the current environment
+	missing := env.MissingRequired(entries, values, provider.ProducedKeys(processResult.Origins), os.LookupEnv)
+	if len(missing) > 0 {
+		return withFailure[AcquiredSecrets](fmt.Sprintf("required variables have no value (set them in the environment or provide a value):\n%s",
+			env.FormatMissingRequired(missing)))
+	}
+
+	// Required variables provided by the environment are not written to the cache as empty values
+	values = env.OmitEmptyRequired(entries, values)
+
 	orderedKeys := env.OrganizeKeyOrder(entries, values)
 
-	return withSuccess(WithAcquiredSecrets(
-		values,
-		orderedKeys,
-	))
+	secrets := WithAcquiredSecrets(values, orderedKeys)
+	secrets.Origins = processResult.Origins
+	secrets.Collisions = processResult.Collisions
+	return withSuccess(secrets)
+}
+
+// checkEntryURIs fails when an entry has a sem:// value that is not a valid secret URI.
+// With lenientURIs the problems are only logged as warnings, as in earlier versions.
+func checkEntryURIs(entries []modelenv.Entry, lenientURIs bool) functional.Result[bool] {
+	problems := provider.ValidateEntryURIs(entries)
+	if lenientURIs {
+		for _, problem := range problems {
+			logWarning(problem.Error())
+		}
+		return withSuccess(true)
+	}
+
+	if len(problems) > 0 {
+		lines := make([]string, len(problems))
+		for i, problem := range problems {
+			lines[i] = "  " + problem.Error()
+		}
+		return withFailure[bool](fmt.Sprintf("invalid secret URIs (use --lenient-uris to only warn):\n%s",
+			strings.Join(lines, "\n")))
+	}
+	return withSuccess(true)
+}
+
+// reportCollisions logs redefined variables as warnings and fails when a collision drops a value from a secret.
+// In strict mode every collision fails.
+func reportCollisions(collisions []provider.Collision, strict bool) functional.Result[bool] {
+	errors, warnings := provider.SplitCollisions(collisions)
+	if strict {
+		errors = collisions
+	} else {
+		for _, collision := range warnings {
+			logWarning(fmt.Sprintf("Variable %s", collision))
+		}
+	}
+
+	if len(errors) > 0 {
+		lines := make([]string, len(errors))
+		for i, collision := range errors {
+			lines[i] = "  " + collision.String()
+		}
+		return withFailure[bool](fmt.Sprintf("variable name collisions between entries (use ?prefix= or ?map= to rename keys):\n%s",
+			strings.Join(lines, "\n")))
+	}
+
+	return withSuccess(true)
 }
 
 // writeOutputFile writes environment variables to a file
-func writeOutputFile(fileName string, values map[string]string, orderedKeys []string, noQuotes bool, noExpandJson bool) functional.Result[bool] {
+func writeOutputFile(fileName string, values map[string]string, orderedKeys []string, noQuotes bool) functional.Result[bool] {
 	// Create output file with custom options
 	output := fileio.NewEnvFileOutputWithOptions(
 		fileName,
@@ -198,12 +367,25 @@ func writeOutputFile(fileName string, values map[string]string, orderedKeys []st
 		!noQuotes, // If noQuotes is true, UseQuotes is false
 	)
 
-	// Set JSON expansion flag
-	output.NoExpandJson = noExpandJson
+	// Values are already expanded by the provider; JSON left in a value was kept on purpose
+	output.NoExpandJson = true
 
 	return fileio.WriteOutputFile(output)
 }
 
+// writeMetadataWithWarning writes the provenance metadata file, converting errors to warnings
+func writeMetadataWithWarning(inputFileName, cacheFileName string, secrets AcquiredSecrets) {
+	metadata := provenance.FromOrigins(inputFileName, secrets.Keys, secrets.Origins, secrets.Collisions, time.Now())
+	metadataFileName := provenance.FileName(cacheFileName)
+
+	result := provenance.WriteResult(metadataFileName, metadata)
+	if result.IsFailure() {
+		logWarning(fmt.Sprintf("Unable to write metadata file: %v", result.GetError()))
+		return
+	}
+	logDebugInfo(fmt.Sprintf("Recorded the provenance of %d variables in %s", len(metadata.Variables), metadataFileName))
+}
+
 // secureOutputFileWithWarning sets appropriate permissions, converting errors to warnings
 func secureOutputFileWithWarning(fileName string) functional.Result[bool] {
 	result := fileio.SecureOutputFile(fileName)
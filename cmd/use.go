@@ -0,0 +1,81 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+// UseParams contains parameters for the Use command
+type UseParams struct {
+	Environment profile.Environment
+	AssumeYes   bool
+}
+
+// WithUseParams creates a new UseParams with provided values
+func WithUseParams(environment profile.Environment, assumeYes bool) UseParams {
+	return UseParams{
+		Environment: environment,
+		AssumeYes:   assumeYes,
+	}
+}
+
+// Use records the active environment so that update/load (and direnv) pick it up by default
+func Use(c *cli.Context) error {
+	paramsResult := validateUseParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	confirmResult := confirmProduction(params.Environment, "Make it the active environment", params.AssumeYes)
+	if confirmResult.IsFailure() {
+		return confirmResult.GetError()
+	}
+
+	writeResult := profile.WriteActive(params.Environment.Name)
+	if writeResult.IsFailure() {
+		return writeResult.GetError()
+	}
+
+	logSuccessInfo(fmt.Sprintf("Active environment set to '%s' (input: %s, cache: %s)",
+		params.Environment.Name, params.Environment.InputFile, params.Environment.CacheFile))
+	displayUseNextSteps()
+
+	return nil
+}
+
+// validateUseParams validates CLI parameters with Result monad
+func validateUseParams(c *cli.Context) functional.Result[UseParams] {
+	if c.NArg() != 1 {
+		return withFailure[UseParams]("exactly one environment name is required (e.g. 'sem use staging')")
+	}
+
+	nameResult := profile.ValidateName(c.Args().First())
+	if nameResult.IsFailure() {
+		return functional.Failure[UseParams](nameResult.GetError())
+	}
+
+	environment := profile.Resolve(nameResult.Unwrap())
+	if _, err := os.Stat(environment.InputFile); err != nil {
+		return withFailure[UseParams](fmt.Sprintf(
+			"input file '%s' for environment '%s' not found", environment.InputFile, environment.Name))
+	}
+
+	return withSuccess(WithUseParams(environment, c.Bool("yes")))
+}
+
+// displayUseNextSteps shows how to use the active environment with direnv
+func displayUseNextSteps() {
+	fmt.Println(formatting.Hint("update and load now default to this environment. For direnv, add the following to .envrc:"))
+	fmt.Println()
+	fmt.Println(formatting.ColorizeValue("   watch_file " + profile.ActiveEnvFile))
+	fmt.Println(formatting.ColorizeValue("   sem update"))
+	fmt.Println(formatting.ColorizeValue("   eval \"$(sem load -e)\""))
+	fmt.Println()
+}
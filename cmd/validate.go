@@ -0,0 +1,145 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/schema"
+	"github.com/urfave/cli/v2"
+)
+
+// ValidateParams contains parameters for the Validate command
+type ValidateParams struct {
+	InputFileName  string
+	SchemaFileName string
+	Endpoints      Endpoints
+	Strict         bool
+	LenientURIs    bool // Only warn about invalid secret URIs instead of failing
+	Expand         expand.Options
+}
+
+// WithValidateParams creates a new ValidateParams with provided values
+func WithValidateParams(inputFileName, schemaFileName string, endpoints Endpoints, strict, lenientURIs bool, expandOptions expand.Options) ValidateParams {
+	return ValidateParams{
+		InputFileName:  inputFileName,
+		SchemaFileName: schemaFileName,
+		Endpoints:      endpoints,
+		Strict:         strict,
+		LenientURIs:    lenientURIs,
+		Expand:         expandOptions,
+	}
+}
+
+// ValidateResult represents the result of a validation
+type ValidateResult struct {
+	VariableCount int
+	RuleCount     int
+}
+
+// Validate resolves secrets and checks them against the schema without writing the cache file
+func Validate(c *cli.Context) error {
+	paramsResult := validateValidateParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+
+	result := performValidate(paramsResult.Unwrap())
+	if result.IsFailure() {
+		return result.GetError()
+	}
+
+	validateResult := result.Unwrap()
+	logSuccessInfo(fmt.Sprintf("All %d environment variables satisfy the schema (%d rules)",
+		validateResult.VariableCount, validateResult.RuleCount))
+	return nil
+}
+
+// validateValidateParams validates CLI parameters and returns a Result monad
+func validateValidateParams(c *cli.Context) functional.Result[ValidateParams] {
+	expandResult := resolveExpandOptions(c)
+	if expandResult.IsFailure() {
+		return functional.Failure[ValidateParams](expandResult.GetError())
+	}
+
+	return functional.Chain(resolveEnvironment(c), func(environment profile.Environment) functional.Result[ValidateParams] {
+		return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) ValidateParams {
+			return WithValidateParams(
+				environment.InputFile,
+				c.String("schema"),
+				endpoints,
+				c.Bool("strict"),
+				c.Bool("lenient-uris"),
+				expandResult.Unwrap(),
+			)
+		})
+	})
+}
+
+// performValidate reads the input file, resolves its secrets and validates them
+func performValidate(params ValidateParams) functional.Result[ValidateResult] {
+	logInfoMsg(fmt.Sprintf("Reading input file: %s", params.InputFileName))
+
+	entriesResult := readInputFile(params.InputFileName)
+	if entriesResult.IsFailure() {
+		return withFailure[ValidateResult](entriesResult.GetError().Error())
+	}
+	entries := entriesResult.Unwrap()
+
+	schemaResult := loadSchema(entries, params.SchemaFileName)
+	if schemaResult.IsFailure() {
+		return withFailure[ValidateResult](schemaResult.GetError().Error())
+	}
+
+	secretsResult := acquireSecrets(entries, params.Endpoints, params.Expand, params.Strict, params.LenientURIs)
+	if secretsResult.IsFailure() {
+		return withFailure[ValidateResult](secretsResult.GetError().Error())
+	}
+	secrets := secretsResult.Unwrap()
+
+	checkResult := checkSchema(schemaResult.Unwrap(), secrets.Values)
+	if checkResult.IsFailure() {
+		return withFailure[ValidateResult](checkResult.GetError().Error())
+	}
+
+	return withSuccess(ValidateResult{
+		VariableCount: len(secrets.Keys),
+		RuleCount:     len(schemaResult.Unwrap().Rules),
+	})
+}
+
+// loadSchema builds the schema from the options of the input file entries and an optional schema file
+func loadSchema(entries []modelenv.Entry, schemaFileName string) functional.Result[schema.Schema] {
+	entriesSchema := schema.FromEntriesResult(entries)
+	if entriesSchema.IsFailure() || schemaFileName == "" {
+		return entriesSchema
+	}
+
+	contentResult := fileio.ReadFile(schemaFileName)
+	if contentResult.IsFailure() {
+		return functional.Failure[schema.Schema](contentResult.GetError())
+	}
+	content := contentResult.Unwrap()
+
+	return functional.MapResultTo(schema.ParseResult(content.FilePath, content.Data), entriesSchema.Unwrap().Merge)
+}
+
+// checkSchema validates resolved values against the schema, failing with every violation found
+func checkSchema(s schema.Schema, values map[string]string) functional.Result[bool] {
+	if s.IsEmpty() {
+		return withSuccess(true)
+	}
+
+	violations := schema.Validate(s, values)
+	if len(violations) > 0 {
+		return withFailure[bool](fmt.Sprintf("%d schema violations found:\n%s",
+			len(violations), schema.FormatViolations(violations)))
+	}
+
+	logDebugInfo(fmt.Sprintf("Resolved values satisfy %d schema rules", len(s.Rules)))
+	return withSuccess(true)
+}
@@ -0,0 +1,113 @@
+// Package cmd implements command-line commands for the secret-env-manager
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
+	"github.com/urfave/cli/v2"
+)
+
+// VersionsParams contains parameters for the Versions command
+type VersionsParams struct {
+	SecretURI uri.SecretURI
+	Endpoints Endpoints
+}
+
+// WithVersionsParams creates a new VersionsParams with provided values
+func WithVersionsParams(secretURI uri.SecretURI, endpoints Endpoints) VersionsParams {
+	return VersionsParams{
+		SecretURI: secretURI,
+		Endpoints: endpoints,
+	}
+}
+
+// Versions lists the versions and stages of the secret referenced by a URI
+func Versions(c *cli.Context) error {
+	paramsResult := validateVersionsParams(c)
+	if paramsResult.IsFailure() {
+		return paramsResult.GetError()
+	}
+	params := paramsResult.Unwrap()
+
+	versionsResult := listSecretVersions(params.SecretURI, params.Endpoints)
+	if versionsResult.IsFailure() {
+		return versionsResult.GetError()
+	}
+
+	displayVersions(params.SecretURI, versionsResult.Unwrap())
+	return nil
+}
+
+// validateVersionsParams validates CLI parameters and returns a Result monad
+func validateVersionsParams(c *cli.Context) functional.Result[VersionsParams] {
+	if c.NArg() != 1 {
+		return withFailure[VersionsParams]("usage: sem versions <uri>")
+	}
+
+	return functional.Chain(uri.ParseResult(c.Args().First()), func(secretURI uri.SecretURI) functional.Result[VersionsParams] {
+		return functional.MapResultTo(resolveEndpoints(c), func(endpoints Endpoints) VersionsParams {
+			return WithVersionsParams(secretURI, endpoints)
+		})
+	})
+}
+
+// listSecretVersions lists the versions of a secret from its provider
+func listSecretVersions(secretURI uri.SecretURI, endpoints Endpoints) functional.Result[[]secret.Version] {
+	ctx := context.Background()
+
+	switch secretURI.Platform {
+	case uri.AwsPlatform:
+		return aws.NewAwsProvider().ListSecretVersionsResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+	case uri.GoogleCloudPlatform:
+		return googlecloud.NewGoogleCloudProvider().ListSecretVersionsResult(ctx, secretURI.Account, secretURI.Region, secretURI.SecretName, endpoints.For(secretURI))
+	default:
+		return withFailure[[]secret.Version](fmt.Sprintf("unsupported platform '%s'", secretURI.Platform))
+	}
+}
+
+// displayVersions prints the versions of a secret, marking the one the URI refers to
+func displayVersions(secretURI uri.SecretURI, versions []secret.Version) {
+	fmt.Println(formatting.FormatHeader("Versions of %s", secretURI.Reference()))
+	if len(versions) == 0 {
+		fmt.Println(formatting.Hint("No versions found"))
+		return
+	}
+
+	for _, version := range versions {
+		marker := "  "
+		if version.Matches(secretURI.Version) {
+			marker = "* "
+		}
+		fmt.Printf("%s%-38s %-30s %s\n", marker, version.ID, formatStages(version), formatCreatedAt(version.CreatedAt))
+	}
+	fmt.Println(formatting.Hint("\n* marks the version selected by ?version=%s", secretURI.Version))
+}
+
+// formatStages formats the stages of a version, followed by its state for Google Cloud versions
+func formatStages(version secret.Version) string {
+	stages := strings.Join(version.Stages, ",")
+	if version.State != "" && version.State != "ENABLED" {
+		stages = strings.TrimPrefix(stages+" ("+strings.ToLower(version.State)+")", " ")
+	}
+	if stages == "" {
+		return "-"
+	}
+	return stages
+}
+
+// formatCreatedAt formats the creation time of a version in local time
+func formatCreatedAt(createdAt time.Time) string {
+	if createdAt.IsZero() {
+		return "-"
+	}
+	return createdAt.Local().Format("2006-01-02 15:04:05")
+}
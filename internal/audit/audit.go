@@ -0,0 +1,189 @@
+// Package audit records who accessed which secret and when in an opt-in audit log.
+// Records are appended to a local JSONL file or sent to syslog and never contain secret values.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/logging"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+)
+
+// SyslogTarget is the audit log target that sends records to syslog instead of a file
+const SyslogTarget = "syslog"
+
+// Cache states of an access
+const (
+	CacheHit  = "hit"  // The secret was already fetched by this command
+	CacheMiss = "miss" // The secret was fetched from the provider
+)
+
+// Outcomes of an access
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Record is a single secret access
+type Record struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Host    string    `json:"host"`
+	Command string    `json:"command"`
+	URI     string    `json:"uri"`               // Secret URI as referenced (never the value)
+	Version string    `json:"version,omitempty"` // Version the URI resolved to
+	Cache   string    `json:"cache"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Sink stores audit records
+type Sink interface {
+	Write(record Record) error
+}
+
+// fileSink appends records to a JSONL file
+type fileSink struct {
+	path string
+}
+
+// Write appends the record as a single JSON line
+func (s fileSink) Write(record Record) error {
+	data, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
+
+// marshalRecord encodes a record as a JSON line, keeping characters such as '&' in URIs readable
+func marshalRecord(record Record) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// OpenSinkResult returns the sink for a target: "syslog" or the path of a JSONL file
+func OpenSinkResult(target string) functional.Result[Sink] {
+	if target == SyslogTarget {
+		return openSyslogSinkResult()
+	}
+	return functional.Success[Sink](fileSink{path: target})
+}
+
+// auditor holds the process-wide audit settings
+type auditor struct {
+	mu      sync.Mutex
+	sink    Sink
+	command string
+	user    string
+	host    string
+}
+
+var current = &auditor{}
+
+// Configure enables auditing for the running command. A nil sink disables it.
+func Configure(sink Sink, command string) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.sink = sink
+	current.command = command
+	current.user = currentUser()
+	current.host = currentHost()
+}
+
+// Enabled reports whether audit records are written
+func Enabled() bool {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	return current.sink != nil
+}
+
+// RecordAccess writes a record of a secret access when auditing is enabled.
+// A record that cannot be written is reported as a warning and does not fail the access.
+func RecordAccess(secretURI uri.SecretURI, version string, cache string, accessErr error) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	if current.sink == nil {
+		return
+	}
+
+	record := Record{
+		Time:    time.Now().UTC(),
+		User:    current.user,
+		Host:    current.host,
+		Command: current.command,
+		URI:     secretURI.GetUri(),
+		Version: version,
+		Cache:   cache,
+		Outcome: OutcomeSuccess,
+	}
+	if accessErr != nil {
+		record.Outcome = OutcomeFailure
+		record.Error = accessErr.Error()
+	}
+
+	if err := current.sink.Write(record); err != nil {
+		logging.DefaultLogger().Warn("Failed to write audit record: %v", err)
+	}
+}
+
+// currentUser returns the name of the OS user running the command
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// currentHost returns the host name, or an empty string when it is unknown
+func currentHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// ReadRecordsResult reads every record of a JSONL audit log
+func ReadRecordsResult(path string) functional.Result[[]Record] {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return functional.Failure[[]Record](fmt.Errorf("failed to read audit log %s: %w", path, err))
+	}
+
+	records := []Record{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for decoder.More() {
+		var record Record
+		if err := decoder.Decode(&record); err != nil {
+			return functional.Failure[[]Record](fmt.Errorf("invalid audit log %s: %w", path, err))
+		}
+		records = append(records, record)
+	}
+	return functional.Success(records)
+}
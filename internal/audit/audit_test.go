@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+)
+
+func TestRecordAccess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	Configure(fileSink{path: path}, "update")
+	defer Configure(nil, "")
+
+	secretURI := uri.NewSecretURI(uri.AwsPlatform, "secretsmanager", "dev", "app")
+	secretURI.Version = uri.AwsDefaultVersion
+	secretURI.Key = "password"
+
+	RecordAccess(secretURI, "v1", CacheMiss, nil)
+	RecordAccess(secretURI, "v1", CacheHit, nil)
+	RecordAccess(secretURI, "", CacheMiss, errors.New("access denied"))
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(content), `\u0026`) {
+		t.Errorf("URIs should not be HTML-escaped: %s", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("audit log permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	result := ReadRecordsResult(path)
+	if result.IsFailure() {
+		t.Fatalf("ReadRecordsResult failed: %v", result.GetError())
+	}
+	records := result.Unwrap()
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+
+	tests := []struct {
+		name    string
+		record  Record
+		cache   string
+		outcome string
+		errText string
+	}{
+		{name: "Fetched from provider", record: records[0], cache: CacheMiss, outcome: OutcomeSuccess},
+		{name: "Served from cache", record: records[1], cache: CacheHit, outcome: OutcomeSuccess},
+		{name: "Failed access", record: records[2], cache: CacheMiss, outcome: OutcomeFailure, errText: "access denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.record.Command != "update" {
+				t.Errorf("Command = %q, want %q", tt.record.Command, "update")
+			}
+			if tt.record.URI != secretURI.GetUri() {
+				t.Errorf("URI = %q, want %q", tt.record.URI, secretURI.GetUri())
+			}
+			if tt.record.Cache != tt.cache {
+				t.Errorf("Cache = %q, want %q", tt.record.Cache, tt.cache)
+			}
+			if tt.record.Outcome != tt.outcome {
+				t.Errorf("Outcome = %q, want %q", tt.record.Outcome, tt.outcome)
+			}
+			if tt.record.Error != tt.errText {
+				t.Errorf("Error = %q, want %q", tt.record.Error, tt.errText)
+			}
+			if tt.record.Time.IsZero() {
+				t.Error("Time is zero")
+			}
+		})
+	}
+}
+
+func TestRecordAccessDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	Configure(nil, "update")
+
+	RecordAccess(uri.NewSecretURI(uri.AwsPlatform, "secretsmanager", "dev", "app"), "v1", CacheMiss, nil)
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("audit log should not be written when auditing is disabled")
+	}
+	if Enabled() {
+		t.Error("Enabled() = true, want false")
+	}
+}
+
+func TestFilterRecords(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: now.Add(-48 * time.Hour), User: "alice", Command: "update", URI: "sem://aws:secretsmanager/dev/app", Outcome: OutcomeSuccess},
+		{Time: now.Add(-time.Hour), User: "bob", Command: "copy", URI: "sem://aws:secretsmanager/prod/app", Outcome: OutcomeFailure},
+		{Time: now, User: "alice", Command: "update", URI: "sem://googlecloud:secretmanager/proj/db", Outcome: OutcomeSuccess},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int
+	}{
+		{name: "Empty filter", filter: Filter{}, want: []int{0, 1, 2}},
+		{name: "Since", filter: Filter{Since: now.Add(-2 * time.Hour)}, want: []int{1, 2}},
+		{name: "URI substring", filter: Filter{URI: "/prod/"}, want: []int{1}},
+		{name: "User", filter: Filter{User: "alice"}, want: []int{0, 2}},
+		{name: "Command and outcome", filter: Filter{Command: "copy", Outcome: OutcomeFailure}, want: []int{1}},
+		{name: "No match", filter: Filter{User: "alice", Outcome: OutcomeFailure}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterRecords(records, tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tt.want))
+			}
+			for i, index := range tt.want {
+				if got[i] != records[index] {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], records[index])
+				}
+			}
+		})
+	}
+}
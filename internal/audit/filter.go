@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"strings"
+	"time"
+)
+
+// Filter selects audit records. Empty fields match every record.
+type Filter struct {
+	Since   time.Time // Records at or after this time
+	URI     string    // Substring of the secret URI
+	User    string
+	Command string
+	Outcome string
+}
+
+// Matches reports whether the record satisfies every condition of the filter
+func (f Filter) Matches(record Record) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if f.URI != "" && !strings.Contains(record.URI, f.URI) {
+		return false
+	}
+	if f.User != "" && record.User != f.User {
+		return false
+	}
+	if f.Command != "" && record.Command != f.Command {
+		return false
+	}
+	if f.Outcome != "" && record.Outcome != f.Outcome {
+		return false
+	}
+	return true
+}
+
+// FilterRecords returns the records matching the filter, keeping their order
+func FilterRecords(records []Record, filter Filter) []Record {
+	matched := []Record{}
+	for _, record := range records {
+		if filter.Matches(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
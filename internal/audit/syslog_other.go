@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package audit
+
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// openSyslogSinkResult reports that syslog is not available on this platform
+func openSyslogSinkResult() functional.Result[Sink] {
+	return functional.Failure[Sink](fmt.Errorf("syslog is not supported on this platform; use a file path for the audit log"))
+}
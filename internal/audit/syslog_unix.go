@@ -0,0 +1,34 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// syslogSink sends each record as a JSON message to the local syslog daemon
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// Write sends the record with the info severity
+func (s syslogSink) Write(record Record) error {
+	data, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	return s.writer.Info(strings.TrimSuffix(string(data), "\n"))
+}
+
+// openSyslogSinkResult connects to the local syslog daemon
+func openSyslogSinkResult() functional.Result[Sink] {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, "sem")
+	if err != nil {
+		return functional.Failure[Sink](fmt.Errorf("failed to connect to syslog: %w", err))
+	}
+	return functional.Success[Sink](syslogSink{writer: writer})
+}
@@ -0,0 +1,74 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the endpoint configuration read from the current directory when present.
+// It holds no credentials, only which credential source to use, and can be committed.
+const ConfigFile = ".sem-endpoints.yaml"
+
+// Config is the content of an endpoint configuration file:
+//
+//	endpoints:
+//	  - platform: aws
+//	    account: localstack
+//	    endpoint: http://localhost:4566
+//	    credentials: static
+//	  - platform: googlecloud
+//	    account: sem-test-project
+//	    endpoint: localhost:8085
+type Config struct {
+	Endpoints []Rule `yaml:"endpoints"`
+}
+
+// LoadConfigResult reads and validates the rules of a configuration file.
+// A missing file yields no rules unless it is required, i.e. named explicitly by the user.
+func LoadConfigResult(path string, required bool) functional.Result[[]Rule] {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return functional.Success([]Rule{})
+	}
+	if err != nil {
+		return functional.Failure[[]Rule](fmt.Errorf("failed to read endpoint configuration: %w", err))
+	}
+	return ParseConfigResult(path, data)
+}
+
+// ParseConfigResult parses the rules of a configuration file, rejecting unknown fields
+func ParseConfigResult(path string, data []byte) functional.Result[[]Rule] {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return functional.Failure[[]Rule](fmt.Errorf("%s: %w", path, err))
+	}
+
+	for i, rule := range config.Endpoints {
+		if err := validateRule(rule); err != nil {
+			return functional.Failure[[]Rule](fmt.Errorf("%s: endpoints[%d]: %w", path, i, err))
+		}
+	}
+	if config.Endpoints == nil {
+		return functional.Success([]Rule{})
+	}
+	return functional.Success(config.Endpoints)
+}
+
+// validateRule checks the platform and the credential source of a rule
+func validateRule(rule Rule) error {
+	if _, known := platformCredentials[rule.Platform]; !known {
+		return fmt.Errorf("unknown platform '%s'", rule.Platform)
+	}
+	if rule.Endpoint == "" && rule.Credentials == "" {
+		return fmt.Errorf("an endpoint or credentials must be set")
+	}
+	return ValidateCredentialsResult(rule.Platform, rule.Credentials).GetError()
+}
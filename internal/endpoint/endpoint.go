@@ -0,0 +1,191 @@
+// Package endpoint chooses the API endpoint and the credential source used for each secret.
+//
+// Settings come, from the highest priority, from the endpoint and credentials query
+// parameters of a secret URI, from the most specific rule of the endpoint configuration
+// file matching its platform, account and region, and from the command-line flags.
+// Each field is resolved separately, so a URI may set its endpoint and inherit its credentials.
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// Credential sources
+const (
+	DefaultCredentials = "default" // Default credentials of the platform (AWS profile of the account, Google application default credentials)
+	StaticCredentials  = "static"  // Dummy static credentials accepted by AWS emulators such as LocalStack
+	NoCredentials      = "none"    // Unauthenticated requests to Google Cloud emulators
+)
+
+// platformCredentials lists the credential sources supported by each platform identifier
+var platformCredentials = map[string][]string{
+	"aws":         {DefaultCredentials, StaticCredentials},
+	"googlecloud": {DefaultCredentials, NoCredentials},
+}
+
+// Settings is the endpoint and credential source of a secret. Empty fields mean the platform defaults.
+type Settings struct {
+	Endpoint    string
+	Credentials string
+}
+
+// Or returns the settings with their empty fields taken from fallback
+func (s Settings) Or(fallback Settings) Settings {
+	if s.Endpoint == "" {
+		s.Endpoint = fallback.Endpoint
+	}
+	if s.Credentials == "" {
+		s.Credentials = fallback.Credentials
+	}
+	return s
+}
+
+// UsesStaticCredentials reports whether static credentials were requested
+func (s Settings) UsesStaticCredentials() bool {
+	return s.Credentials == StaticCredentials
+}
+
+// UsesNoCredentials reports whether unauthenticated requests were requested
+func (s Settings) UsesNoCredentials() bool {
+	return s.Credentials == NoCredentials
+}
+
+// Rule sets the endpoint settings of the secrets of a platform, optionally restricted to an account and a region
+type Rule struct {
+	Platform    string `yaml:"platform"`
+	Account     string `yaml:"account,omitempty"` // AWS profile or Google Cloud project ID; empty matches every account
+	Region      string `yaml:"region,omitempty"`  // Empty matches every region
+	Endpoint    string `yaml:"endpoint,omitempty"`
+	Credentials string `yaml:"credentials,omitempty"`
+}
+
+// Settings returns the endpoint settings of the rule
+func (r Rule) Settings() Settings {
+	return Settings{Endpoint: r.Endpoint, Credentials: r.Credentials}
+}
+
+// Matches reports whether the rule applies to a secret of the platform, account and region
+func (r Rule) Matches(platform, account, region string) bool {
+	return r.Platform == platform &&
+		(r.Account == "" || r.Account == account) &&
+		(r.Region == "" || r.Region == region)
+}
+
+// specificity counts the restrictions of the rule, so that account and region rules win over platform rules
+func (r Rule) specificity() int {
+	count := 0
+	if r.Account != "" {
+		count++
+	}
+	if r.Region != "" {
+		count++
+	}
+	return count
+}
+
+// Resolver chooses the endpoint settings of secrets from the configured rules and the per-platform defaults
+type Resolver struct {
+	Rules    []Rule
+	Defaults map[string]Settings // Keyed by platform, set from the command-line flags
+}
+
+// NewResolver creates a Resolver with the provided rules and defaults
+func NewResolver(rules []Rule, defaults map[string]Settings) Resolver {
+	return Resolver{
+		Rules:    rules,
+		Defaults: defaults,
+	}
+}
+
+// WithDefault returns a copy of the Resolver with the default settings of a platform replaced
+func (r Resolver) WithDefault(platform string, settings Settings) Resolver {
+	defaults := make(map[string]Settings, len(r.Defaults)+1)
+	for key, value := range r.Defaults {
+		defaults[key] = value
+	}
+	defaults[platform] = settings
+	return NewResolver(r.Rules, defaults)
+}
+
+// Resolve returns the settings of a secret. The fields set by the secret URI itself
+// take priority over the most specific matching rule, which takes priority over the defaults.
+// Among equally specific rules, the first one wins.
+func (r Resolver) Resolve(platform, account, region string, fromURI Settings) Settings {
+	settings := fromURI
+	best := -1
+	for _, rule := range r.Rules {
+		if rule.Matches(platform, account, region) && rule.specificity() > best {
+			best = rule.specificity()
+			settings = fromURI.Or(rule.Settings())
+		}
+	}
+	return settings.Or(r.Defaults[platform])
+}
+
+// SupportedCredentials returns the credential sources supported by a platform
+func SupportedCredentials(platform string) []string {
+	return platformCredentials[platform]
+}
+
+// ValidateCredentialsResult checks that a credential source is supported by a platform
+func ValidateCredentialsResult(platform, credentials string) functional.Result[string] {
+	supported := SupportedCredentials(platform)
+	if credentials != "" && !functional.Contains(supported, credentials) {
+		return functional.Failure[string](fmt.Errorf("unsupported credentials '%s' for platform %s (expected one of: %s)",
+			credentials, platform, strings.Join(supported, ", ")))
+	}
+	return functional.Success(credentials)
+}
+
+// uriEndpointDomains lists the domain whose hosts a secret URI may name as its endpoint, and the flag
+// that sets other endpoints, for each platform identifier
+var uriEndpointDomains = map[string]struct{ domain, flag string }{
+	"aws":         {"amazonaws.com", "--endpoint-url"},
+	"googlecloud": {"googleapis.com", "--gcp-endpoint"},
+}
+
+// ValidateURIEndpointResult checks an endpoint set by the endpoint query parameter of a secret URI.
+// Requests carry the real credentials of the developer (a signature with the AWS profile or the
+// access token of the Google application default credentials), so an env file may only send them
+// to a local emulator or to a host of the provider. Other endpoints must come from the configuration
+// file or the command-line flags.
+func ValidateURIEndpointResult(platform, address string) functional.Result[string] {
+	allowed, known := uriEndpointDomains[platform]
+	if address == "" || !known || IsLocal(address) {
+		return functional.Success(address)
+	}
+	if host := endpointHost(address); host == allowed.domain || strings.HasSuffix(host, "."+allowed.domain) {
+		return functional.Success(address)
+	}
+	return functional.Failure[string](fmt.Errorf(
+		"endpoint '%s' is not allowed in a %s URI (only local or *.%s endpoints; set others in %s or with %s)",
+		address, platform, allowed.domain, ConfigFile, allowed.flag))
+}
+
+// IsLocal reports whether an endpoint is on the local machine, such as an emulator or a fake server.
+// Local Google Cloud endpoints are reached with plaintext gRPC and without credentials.
+func IsLocal(address string) bool {
+	host := endpointHost(address)
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// endpointHost returns the lower-case host name of an endpoint URL or host:port address
+func endpointHost(address string) string {
+	host := address
+	for _, prefix := range []string{"http://", "https://", "dns:///"} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	host, _, _ = strings.Cut(host, "/")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
@@ -0,0 +1,169 @@
+package endpoint
+
+import (
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	resolver := NewResolver([]Rule{
+		{Platform: "aws", Endpoint: "http://aws.internal:4566"},
+		{Platform: "aws", Account: "localstack", Endpoint: "http://localhost:4566", Credentials: StaticCredentials},
+		{Platform: "aws", Account: "localstack", Region: "us-east-1", Endpoint: "http://localhost:4567"},
+		{Platform: "aws", Account: "localstack", Endpoint: "http://shadowed:4566"},
+		{Platform: "googlecloud", Region: "europe-west1", Endpoint: "localhost:8085"},
+	}, map[string]Settings{
+		"aws":         {Endpoint: "http://flag:4566", Credentials: DefaultCredentials},
+		"googlecloud": {Endpoint: "flag:443"},
+	})
+
+	tests := []struct {
+		name     string
+		platform string
+		account  string
+		region   string
+		fromURI  Settings
+		want     Settings
+	}{
+		{
+			name:     "Platform rule",
+			platform: "aws", account: "production", region: "ap-northeast-1",
+			want: Settings{Endpoint: "http://aws.internal:4566", Credentials: DefaultCredentials},
+		},
+		{
+			name:     "Account rule wins over platform rule",
+			platform: "aws", account: "localstack", region: "ap-northeast-1",
+			want: Settings{Endpoint: "http://localhost:4566", Credentials: StaticCredentials},
+		},
+		{
+			name:     "Account and region rule wins, inheriting no field from less specific rules",
+			platform: "aws", account: "localstack", region: "us-east-1",
+			want: Settings{Endpoint: "http://localhost:4567", Credentials: DefaultCredentials},
+		},
+		{
+			name:     "URI fields win field by field",
+			platform: "aws", account: "localstack", region: "ap-northeast-1",
+			fromURI: Settings{Endpoint: "http://uri:4566"},
+			want:    Settings{Endpoint: "http://uri:4566", Credentials: StaticCredentials},
+		},
+		{
+			name:     "Region rule",
+			platform: "googlecloud", account: "my-project", region: "europe-west1",
+			want: Settings{Endpoint: "localhost:8085"},
+		},
+		{
+			name:     "Flag defaults when no rule matches",
+			platform: "googlecloud", account: "my-project",
+			want: Settings{Endpoint: "flag:443"},
+		},
+		{
+			name:     "URI credentials with flag endpoint",
+			platform: "googlecloud", account: "my-project",
+			fromURI: Settings{Credentials: NoCredentials},
+			want:    Settings{Endpoint: "flag:443", Credentials: NoCredentials},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolver.Resolve(tt.platform, tt.account, tt.region, tt.fromURI)
+			if got != tt.want {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantRules int
+		wantErr   string
+	}{
+		{
+			name:      "Empty file",
+			data:      "",
+			wantRules: 0,
+		},
+		{
+			name: "Valid rules",
+			data: `endpoints:
+  - platform: aws
+    account: localstack
+    endpoint: http://localhost:4566
+    credentials: static
+  - platform: googlecloud
+    credentials: none
+`,
+			wantRules: 2,
+		},
+		{
+			name:    "Unknown field",
+			data:    "endpoints:\n  - platform: aws\n    url: http://localhost:4566\n",
+			wantErr: ".sem-endpoints.yaml: yaml: unmarshal errors:\n  line 3: field url not found in type endpoint.Rule",
+		},
+		{
+			name:    "Unknown platform",
+			data:    "endpoints:\n  - platform: azure\n    endpoint: http://localhost\n",
+			wantErr: ".sem-endpoints.yaml: endpoints[0]: unknown platform 'azure'",
+		},
+		{
+			name:    "Rule without settings",
+			data:    "endpoints:\n  - platform: aws\n    account: production\n",
+			wantErr: ".sem-endpoints.yaml: endpoints[0]: an endpoint or credentials must be set",
+		},
+		{
+			name:    "Credentials of another platform",
+			data:    "endpoints:\n  - platform: aws\n    credentials: none\n",
+			wantErr: ".sem-endpoints.yaml: endpoints[0]: unsupported credentials 'none' for platform aws (expected one of: default, static)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseConfigResult(ConfigFile, []byte(tt.data))
+			if tt.wantErr != "" {
+				if result.IsSuccess() || result.GetError().Error() != tt.wantErr {
+					t.Fatalf("ParseConfigResult() error = %v, want %q", result.GetError(), tt.wantErr)
+				}
+				return
+			}
+			if result.IsFailure() {
+				t.Fatalf("ParseConfigResult() error = %v", result.GetError())
+			}
+			if got := len(result.Unwrap()); got != tt.wantRules {
+				t.Errorf("ParseConfigResult() returned %d rules, want %d", got, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestValidateURIEndpointResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		address  string
+		wantErr  bool
+	}{
+		{name: "No endpoint", platform: "googlecloud", address: ""},
+		{name: "Local host and port", platform: "googlecloud", address: "localhost:8085"},
+		{name: "Loopback address", platform: "googlecloud", address: "http://127.0.0.1:8085"},
+		{name: "Regional googleapis.com endpoint", platform: "googlecloud", address: "secretmanager.europe-west1.rep.googleapis.com:443"},
+		{name: "Remote host", platform: "googlecloud", address: "secrets.example.com:443", wantErr: true},
+		{name: "Look-alike googleapis.com host", platform: "googlecloud", address: "evilgoogleapis.com:443", wantErr: true},
+		{name: "googleapis.com in the path only", platform: "googlecloud", address: "https://example.com/.googleapis.com", wantErr: true},
+		{name: "Local AWS endpoint", platform: "aws", address: "http://localhost:4566"},
+		{name: "AWS regional endpoint", platform: "aws", address: "https://secretsmanager.us-east-1.amazonaws.com"},
+		{name: "Remote AWS endpoint", platform: "aws", address: "https://secretsmanager.example.com", wantErr: true},
+		{name: "Look-alike amazonaws.com host", platform: "aws", address: "https://amazonaws.com.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateURIEndpointResult(tt.platform, tt.address)
+			if result.IsFailure() != tt.wantErr {
+				t.Errorf("ValidateURIEndpointResult(%q, %q) error = %v, wantErr %v", tt.platform, tt.address, result.GetError(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@ package env
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
 	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
@@ -96,110 +96,13 @@ func FormatEnvVarContent(values EnvVarMap, options EnvVarOptions) functional.Res
 }
 
 // ProcessJSONValue processes a JSON value and returns formatted key-value pairs
-// Pure function that uses the Result monad for composition
+// using the default options of the expansion engine
 func ProcessJSONValue(parentKey, jsonString string, useQuotes bool) functional.Result[string] {
-	// Parse the JSON string into a map
-	parseResult := text.ParseJSONResult(jsonString)
-	if parseResult.IsFailure() {
-		// Not a valid JSON object, treat as regular string
-		return functional.Success(formatting.FormatKeyValuePair(parentKey, jsonString, useQuotes))
-	}
-
-	// Process the parsed JSON data appropriately
-	return processAnyJSONValue(parentKey, parseResult.Unwrap(), useQuotes)
-}
-
-// processAnyJSONValue processes JSON data appropriately based on its type
-func processAnyJSONValue(parentKey string, jsonData interface{}, useQuotes bool) functional.Result[string] {
-	switch data := jsonData.(type) {
-	case map[string]interface{}:
-		// Process each key-value pair in the object
-		return processJSONObject(parentKey, data, useQuotes)
-
-	case []interface{}:
-		// Expand array elements with index-based keys
-		return processJSONArray(parentKey, data, useQuotes)
-
-	default:
-		// Process as a single value if neither an object nor array
-		valueStr, err := formatting.FormatValueForEnv(jsonData)
-		if err != nil {
-			return functional.Failure[string](err)
-		}
-		return functional.Success(formatting.FormatKeyValuePair(parentKey, valueStr, useQuotes))
-	}
-}
-
-// processJSONObject expands JSON object keys and values
-func processJSONObject(parentKey string, jsonData map[string]interface{}, useQuotes bool) functional.Result[string] {
-	// Use functional.Result to accumulate formatted key-value pairs
-	formattedPairs := make([]string, 0, len(jsonData))
-
-	// Sort keys alphabetically to maintain consistent processing order
-	keys := make([]string, 0, len(jsonData))
-	for jsonKey := range jsonData {
-		keys = append(keys, jsonKey)
-	}
-	sort.Strings(keys)
-
-	// Process in sorted key order
-	for _, jsonKey := range keys {
-		jsonVal := jsonData[jsonKey]
-		// Process child elements recursively if they are objects or arrays
-		switch childVal := jsonVal.(type) {
-		case map[string]interface{}, []interface{}:
-			// Generate key for child element
-			childKey := fmt.Sprintf("%s_%s", parentKey, jsonKey)
-			// Process child element recursively
-			childResult := processAnyJSONValue(childKey, childVal, useQuotes)
-			if childResult.IsFailure() {
-				return childResult
-			}
-			formattedPairs = append(formattedPairs, childResult.Unwrap())
+	variables := expand.Value(parentKey, jsonString, expand.DefaultOptions())
 
-		default:
-			// Format simple values directly
-			kvResult := formatting.FormatJSONKeyValueResult(parentKey, jsonKey, jsonVal, useQuotes)
-			if kvResult.IsFailure() {
-				return kvResult // Propagate the failure
-			}
-			formattedPairs = append(formattedPairs, kvResult.Unwrap())
-		}
-	}
-
-	// Join the formatted pairs with newlines
-	return functional.Success(strings.Join(formattedPairs, "\n"))
-}
-
-// processJSONArray expands JSON array elements with indexed keys
-func processJSONArray(parentKey string, jsonArray []interface{}, useQuotes bool) functional.Result[string] {
-	formattedPairs := make([]string, 0, len(jsonArray))
-
-	// Process each array element with its index
-	for i, item := range jsonArray {
-		// Generate key with index
-		indexKey := fmt.Sprintf("%s_%d", parentKey, i)
-
-		// Process based on element type
-		switch val := item.(type) {
-		case map[string]interface{}, []interface{}:
-			// Process objects or arrays recursively
-			childResult := processAnyJSONValue(indexKey, val, useQuotes)
-			if childResult.IsFailure() {
-				return childResult
-			}
-			formattedPairs = append(formattedPairs, childResult.Unwrap())
-
-		default:
-			// Expand simple values directly
-			valueResult := formatting.FormatValueForEnvResult(item)
-			if valueResult.IsFailure() {
-				return functional.Failure[string](
-					fmt.Errorf("failed to format array item at index %d: %w", i, valueResult.GetError()))
-			}
-			valueStr := formatting.UnwrapQuotes(valueResult.Unwrap())
-			formattedPairs = append(formattedPairs, formatting.FormatKeyValuePair(indexKey, valueStr, useQuotes))
-		}
+	formattedPairs := make([]string, len(variables))
+	for i, variable := range variables {
+		formattedPairs[i] = formatting.FormatKeyValuePair(variable.Key, variable.Value, useQuotes)
 	}
 
 	// Join the formatted pairs with newlines
@@ -0,0 +1,83 @@
+// Package env provides utilities for working with environment variables.
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+// LookupFunc looks up a variable in the current environment (e.g. os.LookupEnv)
+type LookupFunc func(key string) (string, bool)
+
+// ProducedKeys maps the location of an input entry (see Entry.Location) to the keys it produced
+type ProducedKeys map[string][]string
+
+// MissingRequired returns the required entries that have no value in the resolved
+// values, no default value and are not set in the environment.
+// An entry whose JSON secret was expanded into other keys counts as resolved when one of
+// the keys it produced has a value.
+func MissingRequired(entries []modelenv.Entry, values EnvVarMap, produced ProducedKeys, lookup LookupFunc) []modelenv.Entry {
+	missing := []modelenv.Entry{}
+	for _, entry := range entries {
+		if !entry.IsRequired() || entry.Key == "" {
+			continue
+		}
+		if hasResolvedValue(entry, values, produced) || entry.Options.Default != "" {
+			continue
+		}
+		if value, exists := lookup(entry.Key); exists && value != "" {
+			continue
+		}
+		missing = append(missing, entry)
+	}
+	return missing
+}
+
+// OmitEmptyRequired returns a copy of the values without required variables that resolved to
+// an empty value, so that the value from the environment is used instead of an empty one
+func OmitEmptyRequired(entries []modelenv.Entry, values EnvVarMap) EnvVarMap {
+	omitted := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsRequired() {
+			if value, exists := values[entry.Key]; exists && value == "" {
+				omitted[entry.Key] = true
+			}
+		}
+	}
+
+	result := make(EnvVarMap, len(values))
+	for key, value := range values {
+		if !omitted[key] {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// hasResolvedValue checks if the key of an entry, or the keys the entry produced, resolved to a non-empty value
+func hasResolvedValue(entry modelenv.Entry, values EnvVarMap, produced ProducedKeys) bool {
+	if value, exists := values[entry.Key]; exists {
+		return value != ""
+	}
+	for _, key := range produced[entry.Location()] {
+		if values[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatMissingRequired describes missing required entries, one per line
+func FormatMissingRequired(missing []modelenv.Entry) string {
+	lines := make([]string, 0, len(missing))
+	for _, entry := range missing {
+		line := fmt.Sprintf("  %s (%s)", entry.Key, entry.Location())
+		if entry.Options.Description != "" {
+			line += ": " + entry.Options.Description
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+
+	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+func TestMissingRequired(t *testing.T) {
+	required := modelenv.EntryOptions{Required: true}
+	db := modelenv.NewEntry(1, "DB", "").WithOptions(required).WithSource(".env")
+	config := modelenv.NewEntry(2, "CONFIG", "sem://aws:secretsmanager/default/config").WithOptions(required).WithSource(".env")
+	environment := map[string]string{"FROM_ENV": "set", "EMPTY_ENV": ""}
+	lookup := func(key string) (string, bool) {
+		value, exists := environment[key]
+		return value, exists
+	}
+
+	tests := []struct {
+		name     string
+		entry    modelenv.Entry
+		values   EnvVarMap
+		produced ProducedKeys
+		missing  bool
+	}{
+		{
+			name:   "Resolved value",
+			entry:  db,
+			values: EnvVarMap{"DB": "postgres://localhost"},
+		},
+		{
+			name:    "Empty value",
+			entry:   db,
+			values:  EnvVarMap{"DB": ""},
+			missing: true,
+		},
+		{
+			name:    "Unrelated prefixed key does not satisfy a bare key",
+			entry:   db,
+			values:  EnvVarMap{"DB_HOST": "localhost"},
+			missing: true,
+		},
+		{
+			name:     "Keys expanded from the entry",
+			entry:    config,
+			values:   EnvVarMap{"CONFIG__HOST": "localhost"},
+			produced: ProducedKeys{".env:2": {"CONFIG__HOST"}},
+		},
+		{
+			name:     "Renamed keys expanded from the entry",
+			entry:    config,
+			values:   EnvVarMap{"APP_HOST": "localhost"},
+			produced: ProducedKeys{".env:2": {"APP_HOST"}},
+		},
+		{
+			name:     "Keys expanded from the entry are all empty",
+			entry:    config,
+			values:   EnvVarMap{"CONFIG_HOST": ""},
+			produced: ProducedKeys{".env:2": {"CONFIG_HOST"}},
+			missing:  true,
+		},
+		{
+			name:   "Default value",
+			entry:  db.WithOptions(modelenv.EntryOptions{Required: true, Default: "sqlite"}),
+			values: EnvVarMap{},
+		},
+		{
+			name:   "Set in the environment",
+			entry:  modelenv.NewEntry(3, "FROM_ENV", "").WithOptions(required),
+			values: EnvVarMap{},
+		},
+		{
+			name:    "Empty in the environment",
+			entry:   modelenv.NewEntry(4, "EMPTY_ENV", "").WithOptions(required),
+			values:  EnvVarMap{},
+			missing: true,
+		},
+		{
+			name:   "Not required",
+			entry:  modelenv.NewEntry(5, "OPTIONAL", ""),
+			values: EnvVarMap{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := MissingRequired([]modelenv.Entry{tt.entry}, tt.values, tt.produced, lookup)
+			if got := len(missing) > 0; got != tt.missing {
+				t.Errorf("MissingRequired() = %v, want missing %v", missing, tt.missing)
+			}
+		})
+	}
+}
+
+func TestOmitEmptyRequired(t *testing.T) {
+	required := modelenv.EntryOptions{Required: true}
+	entries := []modelenv.Entry{
+		modelenv.NewEntry(1, "TOKEN", "").WithOptions(required),
+		modelenv.NewEntry(2, "HOST", "").WithOptions(required),
+		modelenv.NewEntry(3, "EMPTY", ""),
+	}
+
+	tests := []struct {
+		name     string
+		values   EnvVarMap
+		expected EnvVarMap
+	}{
+		{
+			name:     "Empty required values are omitted",
+			values:   EnvVarMap{"TOKEN": "", "HOST": "localhost", "EMPTY": ""},
+			expected: EnvVarMap{"HOST": "localhost", "EMPTY": ""},
+		},
+		{
+			name:     "Resolved values are kept",
+			values:   EnvVarMap{"TOKEN": "abc", "HOST": "localhost"},
+			expected: EnvVarMap{"TOKEN": "abc", "HOST": "localhost"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := OmitEmptyRequired(entries, tt.values); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("OmitEmptyRequired() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package expand
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/text"
+)
+
+// Variable is a single variable produced by expansion
+type Variable struct {
+	Key   string
+	Value string
+}
+
+// invalidIdentifierChars matches characters that are not allowed in variable names
+var invalidIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Value expands a raw value into variables under the given prefix.
+// Values that are not JSON objects or arrays, and all values when expansion is disabled,
+// are returned as a single variable (JSON is compacted).
+func Value(prefix, raw string, opts Options) []Variable {
+	return expandValue(prefix, raw, opts).variables
+}
+
+// Paths returns the JSON path (e.g. "db.hosts[0]") that each variable produced by Value
+// was taken from. Variables holding the whole value have an empty path.
+func Paths(prefix, raw string, opts Options) map[string]string {
+	return expandValue(prefix, raw, opts).paths
+}
+
+// Data expands decoded JSON data into variables under the given prefix.
+// With an empty prefix, the keys of a top-level object become variable names.
+func Data(prefix string, data interface{}, opts Options) []Variable {
+	e := newExpander(opts)
+	e.walk(prefix, "", "", data, 0)
+	return e.variables
+}
+
+// expandValue runs the expander over a raw value
+func expandValue(prefix, raw string, opts Options) *expander {
+	e := newExpander(opts)
+
+	data, ok := decode(raw)
+	switch {
+	case !ok:
+		e.emit(prefix, "", "", raw)
+	case !opts.Enabled:
+		e.emit(prefix, "", "", compact(data))
+	default:
+		e.walk(prefix, "", "", data, 0)
+	}
+
+	return e
+}
+
+// JoinPath appends a JSON path to a parent path (the key of a ?key= lookup)
+func JoinPath(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "" || strings.HasPrefix(child, "["):
+		return parent + child
+	default:
+		return parent + "." + child
+	}
+}
+
+// ToMap converts variables into a map of keys to values
+func ToMap(variables []Variable) map[string]string {
+	result := make(map[string]string, len(variables))
+	for _, v := range variables {
+		result[v.Key] = v.Value
+	}
+	return result
+}
+
+// expander walks decoded JSON data and collects variables
+type expander struct {
+	opts      Options
+	variables []Variable
+	paths     map[string]string // Variable name -> JSON path it was taken from
+}
+
+// newExpander creates an expander with the given options
+func newExpander(opts Options) *expander {
+	return &expander{opts: opts, variables: []Variable{}, paths: map[string]string{}}
+}
+
+// walk expands a JSON node. key is the full variable name, relative is the name
+// without the prefix (used for include/exclude matching), jsonPath is the location of the
+// node in the original JSON and depth is the nesting level.
+func (e *expander) walk(key, relative, jsonPath string, data interface{}, depth int) {
+	depthReached := e.opts.MaxDepth > 0 && depth >= e.opts.MaxDepth
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if depthReached {
+			e.emit(key, relative, jsonPath, compact(v))
+			return
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			segment := e.convertCase(name)
+			e.walk(e.join(key, segment), e.join(relative, segment), JoinPath(jsonPath, name), v[name], depth+1)
+		}
+
+	case []interface{}:
+		switch {
+		case e.opts.Arrays == ArrayJSON || (e.opts.Arrays == ArrayIndex && depthReached):
+			e.emit(key, relative, jsonPath, compact(v))
+		case e.opts.Arrays == ArrayJoin:
+			elements := make([]string, len(v))
+			for i, element := range v {
+				elements[i] = scalarString(element)
+			}
+			e.emit(key, relative, jsonPath, strings.Join(elements, JoinSeparator))
+		default:
+			for i, element := range v {
+				segment := strconv.Itoa(i)
+				e.walk(e.join(key, segment), e.join(relative, segment), jsonPath+"["+segment+"]", element, depth+1)
+			}
+		}
+
+	default:
+		e.emit(key, relative, jsonPath, scalarString(v))
+	}
+}
+
+// emit adds a variable if its relative key passes the include/exclude filters,
+// applying the rename map or the prefix to its name
+func (e *expander) emit(key, relative, jsonPath, value string) {
+	if relative != "" && !e.matches(relative) {
+		return
+	}
+	key = e.rename(key, relative)
+	if e.opts.Sanitize {
+		key = SanitizeKey(key)
+	}
+	e.variables = append(e.variables, Variable{Key: key, Value: value})
+	e.paths[key] = jsonPath
+}
+
+// rename returns the mapped name for a key, or the key with the prefix added.
+// Keys are looked up by their relative name, or by the full name for non-expanded values.
+func (e *expander) rename(key, relative string) string {
+	lookup := relative
+	if lookup == "" {
+		lookup = key
+	}
+	if renamed, exists := e.opts.Rename[lookup]; exists {
+		return renamed
+	}
+	return e.opts.Prefix + key
+}
+
+// matches checks a relative key against the include and exclude globs
+func (e *expander) matches(relative string) bool {
+	if len(e.opts.Include) > 0 && !matchAny(e.opts.Include, relative) {
+		return false
+	}
+	return !matchAny(e.opts.Exclude, relative)
+}
+
+// join joins two key parts with the configured separator
+func (e *expander) join(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + e.opts.Separator + child
+}
+
+// convertCase converts a JSON key according to the configured case
+func (e *expander) convertCase(name string) string {
+	if e.opts.Case == CaseUpper {
+		return ToUpperSnake(name)
+	}
+	return name
+}
+
+// ToUpperSnake converts a key such as "apiKey", "api-key" or "HTTPServer" to UPPER_SNAKE_CASE
+func ToUpperSnake(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if r == '-' || r == ' ' || r == '.' {
+			builder.WriteRune('_')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+	return builder.String()
+}
+
+// SanitizeKey replaces characters that are invalid in variable names with "_"
+// and prefixes names that start with a digit
+func SanitizeKey(key string) string {
+	sanitized := invalidIdentifierChars.ReplaceAllString(key, "_")
+	if sanitized != "" && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+// matchAny checks if a name matches any of the globs
+func matchAny(globs []string, name string) bool {
+	for _, glob := range globs {
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// decode parses JSON objects and arrays while keeping numbers exactly as written
+func decode(raw string) (interface{}, bool) {
+	if !text.IsJSONData(raw) {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil || decoder.More() {
+		return nil, false
+	}
+	return data, true
+}
+
+// compact serializes data as compact JSON
+func compact(data interface{}) string {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
+
+// scalarString converts a JSON value to its variable value (null becomes empty,
+// nested values inside joined arrays become compact JSON)
+func scalarString(data interface{}) string {
+	switch v := data.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return compact(v)
+	}
+}
@@ -0,0 +1,313 @@
+package expand
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValue(t *testing.T) {
+	secret := `{"db": {"host": "localhost", "port": 5432}, "apiKey": "k", "tags": ["a", "b"], "empty": null}`
+
+	tests := []struct {
+		name     string
+		prefix   string
+		raw      string
+		spec     string
+		expected []Variable
+	}{
+		{
+			name:   "Default expands recursively with indexed arrays",
+			prefix: "APP",
+			raw:    secret,
+			expected: []Variable{
+				{"APP_apiKey", "k"},
+				{"APP_db_host", "localhost"},
+				{"APP_db_port", "5432"},
+				{"APP_empty", ""},
+				{"APP_tags_0", "a"},
+				{"APP_tags_1", "b"},
+			},
+		},
+		{
+			name:   "Empty prefix uses JSON keys as names",
+			prefix: "",
+			raw:    `{"user": "admin", "password": "secret"}`,
+			expected: []Variable{
+				{"password", "secret"},
+				{"user", "admin"},
+			},
+		},
+		{
+			name:   "Depth limit keeps nested values as JSON",
+			prefix: "APP",
+			raw:    secret,
+			spec:   "depth:1",
+			expected: []Variable{
+				{"APP_apiKey", "k"},
+				{"APP_db", `{"host":"localhost","port":5432}`},
+				{"APP_empty", ""},
+				{"APP_tags", `["a","b"]`},
+			},
+		},
+		{
+			name:   "Separator and upper case",
+			prefix: "APP",
+			raw:    secret,
+			spec:   "sep:__,case:upper,arrays:join",
+			expected: []Variable{
+				{"APP__API_KEY", "k"},
+				{"APP__DB__HOST", "localhost"},
+				{"APP__DB__PORT", "5432"},
+				{"APP__EMPTY", ""},
+				{"APP__TAGS", "a,b"},
+			},
+		},
+		{
+			name:   "Include and exclude globs match relative keys",
+			prefix: "APP",
+			raw:    secret,
+			spec:   "include:db_*|apiKey,exclude:*_port",
+			expected: []Variable{
+				{"APP_apiKey", "k"},
+				{"APP_db_host", "localhost"},
+			},
+		},
+		{
+			name:   "Arrays as JSON",
+			prefix: "LIST",
+			raw:    `[1, {"a": true}]`,
+			spec:   "arrays:json",
+			expected: []Variable{
+				{"LIST", `[1,{"a":true}]`},
+			},
+		},
+		{
+			name:   "Sanitize invalid identifiers",
+			prefix: "",
+			raw:    `{"my-key": "1", "9lives": "2", "a": {"b.c": "3"}}`,
+			spec:   "sep:.,sanitize",
+			expected: []Variable{
+				{"_9lives", "2"},
+				{"a_b_c", "3"},
+				{"my_key", "1"},
+			},
+		},
+		{
+			name:   "Disabled compacts JSON into one variable",
+			prefix: "APP",
+			raw:    "{\n  \"a\": 1\n}",
+			spec:   "off",
+			expected: []Variable{
+				{"APP", `{"a":1}`},
+			},
+		},
+		{
+			name:     "Plain text is kept as is",
+			prefix:   "TOKEN",
+			raw:      "plain-value",
+			expected: []Variable{{"TOKEN", "plain-value"}},
+		},
+		{
+			name:     "Large numbers keep their precision",
+			prefix:   "N",
+			raw:      `{"id": 12345678901234567890}`,
+			expected: []Variable{{"N_id", "12345678901234567890"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optionsResult := ParseResult(tt.spec, DefaultOptions())
+			if optionsResult.IsFailure() {
+				t.Fatalf("ParseResult(%q) failed: %v", tt.spec, optionsResult.GetError())
+			}
+
+			result := Value(tt.prefix, tt.raw, optionsResult.Unwrap())
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Value() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValueWithPrefixAndRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		raw      string
+		options  Options
+		expected []Variable
+	}{
+		{
+			name:    "Prefix applies to every key",
+			raw:     `{"username": "admin", "password": "secret"}`,
+			options: Options{Enabled: true, Separator: "_", Prefix: "DB_"},
+			expected: []Variable{
+				{"DB_password", "secret"},
+				{"DB_username", "admin"},
+			},
+		},
+		{
+			name:   "Mapped keys use the exact name",
+			raw:    `{"username": "admin", "password": "secret", "host": "db"}`,
+			prefix: "",
+			options: Options{
+				Enabled: true, Separator: "_", Prefix: "DB_",
+				Rename: map[string]string{"username": "DB_USER", "password": "DB_PASS"},
+			},
+			expected: []Variable{
+				{"DB_host", "db"},
+				{"DB_PASS", "secret"},
+				{"DB_USER", "admin"},
+			},
+		},
+		{
+			name:     "Rename matches the full key of a plain value",
+			prefix:   "token",
+			raw:      "abc",
+			options:  Options{Enabled: true, Rename: map[string]string{"token": "API_TOKEN"}},
+			expected: []Variable{{"API_TOKEN", "abc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Value(tt.prefix, tt.raw, tt.options)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Value() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRenameResult(t *testing.T) {
+	tests := []struct {
+		spec      string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{spec: "", expected: nil},
+		{spec: "username:DB_USER, password:DB_PASS", expected: map[string]string{"username": "DB_USER", "password": "DB_PASS"}},
+		{spec: "username", expectErr: true},
+		{spec: "username:", expectErr: true},
+		{spec: "a:X,a:Y", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			result := ParseRenameResult(tt.spec)
+			if result.IsFailure() != tt.expectErr {
+				t.Fatalf("ParseRenameResult(%q) failure = %v, want %v", tt.spec, result.IsFailure(), tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("ParseRenameResult(%q) = %v, want %v", tt.spec, result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		spec      string
+		base      Options
+		expected  Options
+		expectErr bool
+	}{
+		{spec: "", base: DefaultOptions(), expected: DefaultOptions()},
+		{
+			spec: "depth:2,sep:.,case:upper,include:a*|b*,exclude:c,arrays:join,sanitize",
+			base: DefaultOptions(),
+			expected: Options{
+				Enabled: true, MaxDepth: 2, Separator: ".", Case: CaseUpper,
+				Include: []string{"a*", "b*"}, Exclude: []string{"c"}, Arrays: ArrayJoin, Sanitize: true,
+			},
+		},
+		{spec: "off", base: DefaultOptions(), expected: DefaultOptions().Disabled()},
+		{spec: "depth:1", base: DefaultOptions().Disabled(), expected: Options{
+			Enabled: true, MaxDepth: 1, Separator: "_", Case: CasePreserve, Arrays: ArrayIndex,
+		}},
+		{spec: "sep:-", base: DefaultOptions(), expectErr: true},
+		{spec: "depth:x", base: DefaultOptions(), expectErr: true},
+		{spec: "arrays:split", base: DefaultOptions(), expectErr: true},
+		{spec: "include:[", base: DefaultOptions(), expectErr: true},
+		{spec: "color:red", base: DefaultOptions(), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			result := ParseResult(tt.spec, tt.base)
+			if result.IsFailure() != tt.expectErr {
+				t.Fatalf("ParseResult(%q) failure = %v, want %v (%v)", tt.spec, result.IsFailure(), tt.expectErr, result.GetError())
+			}
+			if !tt.expectErr && !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("ParseResult(%q) = %+v, want %+v", tt.spec, result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestToUpperSnake(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"apiKey", "API_KEY"},
+		{"api-key", "API_KEY"},
+		{"HTTPServer", "HTTP_SERVER"},
+		{"user_id", "USER_ID"},
+		{"oauth2Token", "OAUTH2_TOKEN"},
+		{"DB", "DB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := ToUpperSnake(tt.input); result != tt.expected {
+				t.Errorf("ToUpperSnake(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		raw      string
+		spec     string
+		expected map[string]string
+	}{
+		{
+			name:   "Nested keys and array elements",
+			prefix: "APP",
+			raw:    `{"db": {"hosts": ["a", "b"]}, "apiKey": "k"}`,
+			spec:   "case:upper",
+			expected: map[string]string{
+				"APP_API_KEY":    "apiKey",
+				"APP_DB_HOSTS_0": "db.hosts[0]",
+				"APP_DB_HOSTS_1": "db.hosts[1]",
+			},
+		},
+		{
+			name:     "Depth limit points to the kept object",
+			prefix:   "APP",
+			raw:      `{"db": {"host": "h"}}`,
+			spec:     "depth:1",
+			expected: map[string]string{"APP_db": "db"},
+		},
+		{
+			name:     "Whole value has an empty path",
+			prefix:   "TOKEN",
+			raw:      "plain",
+			expected: map[string]string{"TOKEN": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := ParseResult(tt.spec, DefaultOptions()).Unwrap()
+			if result := Paths(tt.prefix, tt.raw, options); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Paths() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
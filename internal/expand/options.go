@@ -0,0 +1,199 @@
+// Package expand flattens JSON secret values into environment variables
+package expand
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// KeyCase controls how JSON keys are converted into variable names
+type KeyCase string
+
+// Supported key cases
+const (
+	CasePreserve KeyCase = "preserve" // Keep JSON keys as they are
+	CaseUpper    KeyCase = "upper"    // Convert JSON keys to UPPER_SNAKE_CASE
+)
+
+// ArrayMode controls how JSON arrays are expanded
+type ArrayMode string
+
+// Supported array modes
+const (
+	ArrayIndex ArrayMode = "index" // One variable per element (KEY_0, KEY_1, ...)
+	ArrayJoin  ArrayMode = "join"  // A single comma-separated variable
+	ArrayJSON  ArrayMode = "json"  // A single variable holding the compact JSON array
+)
+
+// Spec syntax used in --expand and ?expand=
+const (
+	OptionSeparator = "," // Separates options (depth:1,sep:__)
+	ValueSeparator  = ":" // Separates an option name from its value
+	GlobSeparator   = "|" // Separates multiple include/exclude globs
+	JoinSeparator   = "," // Separates array elements in join mode
+)
+
+// supportedSeparators lists the separators allowed between nested key names
+var supportedSeparators = []string{"_", "__", "."}
+
+// Options configures how JSON values are expanded into variables
+type Options struct {
+	Enabled   bool      // Expand JSON values at all
+	MaxDepth  int       // Maximum nesting levels to expand (0 means unlimited)
+	Separator string    // Separator between nested key names
+	Case      KeyCase   // Key case conversion
+	Include   []string  // Globs of relative keys to keep (empty keeps all)
+	Exclude   []string  // Globs of relative keys to drop
+	Arrays    ArrayMode // Array handling
+	Sanitize  bool      // Replace characters that are invalid in variable names
+
+	Prefix string            // Prefix added to every variable name (set per URI with ?prefix=)
+	Rename map[string]string // Relative key -> exact variable name (set per URI with ?map=)
+}
+
+// DefaultOptions returns the options matching the historical expansion behavior:
+// unlimited depth, "_" separator, keys as-is and indexed arrays
+func DefaultOptions() Options {
+	return Options{
+		Enabled:   true,
+		MaxDepth:  0,
+		Separator: "_",
+		Case:      CasePreserve,
+		Arrays:    ArrayIndex,
+	}
+}
+
+// Disabled returns a copy of the options with expansion turned off
+func (o Options) Disabled() Options {
+	result := o
+	result.Enabled = false
+	return result
+}
+
+// ParseResult applies an expansion spec on top of base options.
+//
+// The spec is a comma-separated list of options, for example
+// "depth:1,sep:__,case:upper,include:db_*|api_*,exclude:*_internal,arrays:join,sanitize".
+// "on"/"true" and "off"/"false"/"none" enable or disable expansion.
+func ParseResult(spec string, base Options) functional.Result[Options] {
+	result := base
+	if strings.TrimSpace(spec) == "" {
+		return functional.Success(result)
+	}
+
+	for _, part := range strings.Split(spec, OptionSeparator) {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(part), ValueSeparator)
+		if err := applyOption(&result, strings.ToLower(name), value, hasValue); err != nil {
+			return functional.Failure[Options](fmt.Errorf("invalid expand option '%s': %w", part, err))
+		}
+	}
+
+	return functional.Success(result)
+}
+
+// applyOption applies a single option to the options being built
+func applyOption(o *Options, name, value string, hasValue bool) error {
+	requireValue := func() error {
+		if !hasValue || value == "" {
+			return fmt.Errorf("a value is required (%s%s<value>)", name, ValueSeparator)
+		}
+		return nil
+	}
+
+	switch name {
+	case "on", "true":
+		o.Enabled = true
+	case "off", "false", "none":
+		o.Enabled = false
+		return nil
+	case "depth":
+		if err := requireValue(); err != nil {
+			return err
+		}
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return fmt.Errorf("depth must be a non-negative integer")
+		}
+		o.MaxDepth = depth
+	case "sep", "separator":
+		if err := requireValue(); err != nil {
+			return err
+		}
+		if !contains(supportedSeparators, value) {
+			return fmt.Errorf("separator must be one of %s", strings.Join(supportedSeparators, " "))
+		}
+		o.Separator = value
+	case "case":
+		switch KeyCase(value) {
+		case CasePreserve, CaseUpper:
+			o.Case = KeyCase(value)
+		default:
+			return fmt.Errorf("case must be %s or %s", CasePreserve, CaseUpper)
+		}
+	case "include", "exclude":
+		if err := requireValue(); err != nil {
+			return err
+		}
+		globs := strings.Split(value, GlobSeparator)
+		for _, glob := range globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("invalid glob '%s'", glob)
+			}
+		}
+		if name == "include" {
+			o.Include = globs
+		} else {
+			o.Exclude = globs
+		}
+	case "arrays":
+		switch ArrayMode(value) {
+		case ArrayIndex, ArrayJoin, ArrayJSON:
+			o.Arrays = ArrayMode(value)
+		default:
+			return fmt.Errorf("arrays must be %s, %s or %s", ArrayIndex, ArrayJoin, ArrayJSON)
+		}
+	case "sanitize":
+		o.Sanitize = !hasValue || value == "true"
+	default:
+		return fmt.Errorf("unknown option")
+	}
+
+	// Any option other than off implies expansion
+	o.Enabled = true
+	return nil
+}
+
+// ParseRenameResult parses a rename spec such as "username:DB_USER,password:DB_PASS"
+func ParseRenameResult(spec string) functional.Result[map[string]string] {
+	if strings.TrimSpace(spec) == "" {
+		return functional.Success[map[string]string](nil)
+	}
+
+	rename := make(map[string]string)
+	for _, part := range strings.Split(spec, OptionSeparator) {
+		from, to, ok := strings.Cut(strings.TrimSpace(part), ValueSeparator)
+		if !ok || from == "" || to == "" {
+			return functional.Failure[map[string]string](
+				fmt.Errorf("invalid map entry '%s': expected <key>%s<NAME>", part, ValueSeparator))
+		}
+		if _, exists := rename[from]; exists {
+			return functional.Failure[map[string]string](fmt.Errorf("key '%s' is mapped more than once", from))
+		}
+		rename[from] = to
+	}
+	return functional.Success(rename)
+}
+
+// contains checks if a slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
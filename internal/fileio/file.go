@@ -9,6 +9,7 @@ import (
 
 	"github.com/gumi-tsd/secret-env-manager/internal/env"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/logging"
 	modelenv "github.com/gumi-tsd/secret-env-manager/internal/model/env"
 	"github.com/gumi-tsd/secret-env-manager/internal/parser"
 )
@@ -20,6 +21,8 @@ type FileType int
 
 const (
 	EnvFile FileType = iota
+	YamlFile
+	JsonFile
 	UnknownFile
 )
 
@@ -85,6 +88,11 @@ func GenerateCacheFileName(inputFileName string) string {
 	normalizedPath := filepath.ToSlash(inputFileName)
 	safeFileName := strings.ReplaceAll(normalizedPath, "/", "_")
 
+	// Cache files are always in env format, even for YAML/JSON input files
+	if fileType := DetermineFileType(inputFileName); fileType == YamlFile || fileType == JsonFile {
+		safeFileName += ".env"
+	}
+
 	// Handle filenames that start with a dot (like .env)
 	if strings.HasPrefix(safeFileName, ".") {
 		// Remove the leading dot and add it back in the correct format
@@ -119,22 +127,31 @@ func DetermineFileType(fileName string) FileType {
 	switch ext {
 	case ".env", "":
 		return EnvFile
+	case ".yaml", ".yml":
+		return YamlFile
+	case ".json":
+		return JsonFile
 	default:
 		return UnknownFile
 	}
 }
 
 // ParseFileContent parses the content based on its type
+// #include and @import directives in env files are resolved relative to the file
 // Returns a Result monad containing parsed entries
 func ParseFileContent(content FileContent) functional.Result[[]modelenv.Entry] {
 	switch content.Type {
 	case EnvFile:
-		entries, err := parser.ParsePlainFileContent(content.Data)
-		if err != nil {
+		result := parser.ParsePlainFileWithIncludesResult(content.FilePath, content.Data, os.ReadFile)
+		if result.IsFailure() {
 			return functional.Failure[[]modelenv.Entry](
-				fmt.Errorf("failed to parse env file '%s': %w", content.FilePath, err))
+				fmt.Errorf("failed to parse env file '%s': %w", content.FilePath, result.GetError()))
 		}
-		return functional.Success(entries)
+		return result
+	case YamlFile:
+		return wrapParseError(content, parser.ParseYAMLContentResult(content.FilePath, content.Data))
+	case JsonFile:
+		return wrapParseError(content, parser.ParseJSONContentResult(content.FilePath, content.Data))
 	default:
 		return functional.Failure[[]modelenv.Entry](
 			fmt.Errorf("unsupported file format: %s", filepath.Ext(content.FilePath)))
@@ -182,7 +199,7 @@ func WriteOutputFile(output EnvFileOutput) functional.Result[bool] {
 	// Handle warnings if any
 	result := formatResult.Unwrap()
 	for _, warning := range result.Warnings {
-		fmt.Printf("Warning: %s\n", warning)
+		logging.DefaultLogger().Warn("%s", warning)
 	}
 
 	// Write formatted content to file
@@ -198,11 +215,31 @@ func ReadAndParseInputFile(fileName string) functional.Result[[]modelenv.Entry]
 	)
 }
 
-// ReadEnvVarsAsMap reads environment variables from a file and converts to a map
+// wrapParseError adds the file name to a parse failure
+func wrapParseError(content FileContent, result functional.Result[[]modelenv.Entry]) functional.Result[[]modelenv.Entry] {
+	if result.IsFailure() {
+		return functional.Failure[[]modelenv.Entry](
+			fmt.Errorf("failed to parse input file '%s': %w", content.FilePath, result.GetError()))
+	}
+	return result
+}
+
+// ParseCacheContent parses the content of a generated cache file.
+// Cache files contain resolved values only, so include directives are not evaluated.
+func ParseCacheContent(content FileContent) functional.Result[[]modelenv.Entry] {
+	result := parser.ParsePlainFileContentResult(content.Data)
+	if result.IsFailure() {
+		return functional.Failure[[]modelenv.Entry](
+			fmt.Errorf("failed to parse env file '%s': %w", content.FilePath, result.GetError()))
+	}
+	return result
+}
+
+// ReadEnvVarsAsMap reads environment variables from a cache file and converts to a map
 // Uses function composition for a more functional approach
 func ReadEnvVarsAsMap(fileName string) functional.Result[map[string]string] {
 	return functional.MapResultTo(
-		ReadAndParseInputFile(fileName),
+		functional.Chain(ReadFile(fileName), ParseCacheContent),
 		env.EnvsToMap,
 	)
 }
@@ -2,8 +2,12 @@
 package fileio
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
@@ -58,7 +62,7 @@ func CheckFileIgnoredByGit(outputFileName string) error {
 	if !result.IsIgnored {
 		// Generate the warning message using a pure function
 		warning := FormatSecurityWarning(outputFileName)
-		fmt.Println(warning)
+		fmt.Fprintln(os.Stderr, warning)
 		return fmt.Errorf("output file '%s' is not ignored by git", outputFileName)
 	}
 
@@ -76,40 +80,182 @@ func CheckGitIgnoreStatus(fileName string) GitIgnoreStatus {
 	return ExecuteGitCheckIgnore(fileName)
 }
 
-// ExecuteGitCheckIgnore runs the git check-ignore command
+// ExecuteGitCheckIgnore runs the git check-ignore command.
+// It fails closed: a git error other than "not a git repository" is reported as an error
+// instead of assuming that the file is ignored.
 func ExecuteGitCheckIgnore(fileName string) GitIgnoreStatus {
-    cmd := exec.Command("git", "check-ignore", fileName)
-    output, err := cmd.CombinedOutput()
-
-    // Git command not found - consider the file as ignored to allow operation
-    if execErr, ok := err.(*exec.Error); ok && execErr.Err == exec.ErrNotFound {
-        return NewGitIgnoreStatus(fileName, true, nil)
-    }
-
-    if exitError, ok := err.(*exec.ExitError); ok {
-        // Exit code 1 means the file is NOT ignored
-        if exitError.ExitCode() == 1 {
-            return NewGitIgnoreStatus(fileName, false, nil)
-        }
-        
-        // Exit code 128 typically means "not in a git repository" or similar git error
-        // In this case, assume the file is ignored to allow operation
-        if exitError.ExitCode() == 128 {
-            return NewGitIgnoreStatus(fileName, true, nil)
-        }
-    }
-
-    // Other unexpected errors - log but don't block operation
-    if err != nil {
-        // Generate the error message using a pure function
-        errorMsg := formatting.Warning("Error checking git ignore status: %s", err)
-        fmt.Println(errorMsg)
-        // When in doubt, assume the file is ignored to allow operation
-        return NewGitIgnoreStatus(fileName, true, nil)
-    }
-
-    // If output has content, the file is ignored
-    return NewGitIgnoreStatus(fileName, len(output) > 0, nil)
+	_, err := runGit("check-ignore", "--quiet", "--", fileName)
+
+	// Git command not found - there is no repository the file could be committed to
+	if errors.Is(err, exec.ErrNotFound) {
+		return NewGitIgnoreStatus(fileName, true, nil)
+	}
+
+	var gitErr *GitError
+	if errors.As(err, &gitErr) {
+		// Exit code 1 means the file is NOT ignored (tracked files are never reported as ignored)
+		if gitErr.ExitCode == 1 {
+			return NewGitIgnoreStatus(fileName, false, nil)
+		}
+
+		// Outside a repository the file cannot be committed
+		if gitErr.NotARepository() {
+			return NewGitIgnoreStatus(fileName, true, nil)
+		}
+	}
+
+	if err != nil {
+		return NewGitIgnoreStatus(fileName, false, fmt.Errorf("failed to check whether '%s' is ignored by git: %w", fileName, err))
+	}
+
+	return NewGitIgnoreStatus(fileName, true, nil)
+}
+
+// GitError is a git command that exited with a non-zero code
+type GitError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+}
+
+// Error describes the failed command with the message git printed
+func (e *GitError) Error() string {
+	return fmt.Sprintf("git %s exited with code %d: %s", strings.Join(e.Args, " "), e.ExitCode, strings.TrimSpace(e.Stderr))
+}
+
+// NotARepository reports whether git failed because the directory is not in a repository
+func (e *GitError) NotARepository() bool {
+	return strings.Contains(e.Stderr, "not a git repository")
+}
+
+// runGit runs a git command with untranslated messages and returns its standard output
+func runGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", &GitError{Args: args, ExitCode: exitErr.ExitCode(), Stderr: stderr.String()}
+	}
+	if err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// IsInsideGitRepositoryResult reports whether the current directory is inside a git work tree
+func IsInsideGitRepositoryResult() functional.Result[bool] {
+	output, err := runGit("rev-parse", "--is-inside-work-tree")
+	var gitErr *GitError
+	if errors.As(err, &gitErr) && gitErr.NotARepository() {
+		return functional.Success(false)
+	}
+	if err != nil {
+		return functional.Failure[bool](err)
+	}
+	return functional.Success(strings.TrimSpace(output) == "true")
+}
+
+// ListTrackedFilesResult lists the files of the git index under the current directory
+func ListTrackedFilesResult() functional.Result[[]string] {
+	output, err := runGit("ls-files", "-z")
+	if err != nil {
+		return functional.Failure[[]string](err)
+	}
+	return functional.Success(splitNul(output))
+}
+
+// ListStagedFilesResult lists the files added, copied, modified or renamed in the git index,
+// relative to the repository root
+func ListStagedFilesResult() functional.Result[[]string] {
+	output, err := runGit("diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return functional.Failure[[]string](err)
+	}
+	return functional.Success(splitNul(output))
+}
+
+// ReadStagedFileResult reads the staged content of a file (relative to the repository root)
+func ReadStagedFileResult(path string) functional.Result[[]byte] {
+	output, err := runGit("show", ":"+path)
+	if err != nil {
+		return functional.Failure[[]byte](err)
+	}
+	return functional.Success([]byte(output))
+}
+
+// RepositoryRootResult returns the top-level directory of the current git work tree
+func RepositoryRootResult() functional.Result[string] {
+	output, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return functional.Failure[string](err)
+	}
+	return functional.Success(strings.TrimSpace(output))
+}
+
+// HookPathResult returns the path of a git hook, honoring core.hooksPath
+func HookPathResult(name string) functional.Result[string] {
+	output, err := runGit("rev-parse", "--git-path", "hooks/"+name)
+	if err != nil {
+		return functional.Failure[string](err)
+	}
+	return functional.Success(strings.TrimSpace(output))
+}
+
+// CommittedPath is a path added by a commit
+type CommittedPath struct {
+	Commit string
+	Path   string // Relative to the repository root
+}
+
+// ListCommittedPathsResult lists every path added by any commit reachable from a ref
+func ListCommittedPathsResult() functional.Result[[]CommittedPath] {
+	output, err := runGit("log", "--all", "--diff-filter=A", "--name-only", "-z", "--format=%x00%h")
+	var gitErr *GitError
+	if errors.As(err, &gitErr) && strings.Contains(gitErr.Stderr, "does not have any commits") {
+		return functional.Success([]CommittedPath{})
+	}
+	if err != nil {
+		return functional.Failure[[]CommittedPath](err)
+	}
+	return functional.Success(parseCommittedPaths(output))
+}
+
+// parseCommittedPaths parses the output of git log -z --name-only --format=%x00%h,
+// where each commit starts with an empty field followed by its abbreviated hash
+func parseCommittedPaths(output string) []CommittedPath {
+	paths := []CommittedPath{}
+	commit := ""
+	expectCommit := false
+	for _, field := range strings.Split(output, "\x00") {
+		field = strings.TrimPrefix(field, "\n")
+		switch {
+		case field == "":
+			expectCommit = true
+		case expectCommit:
+			commit = field
+			expectCommit = false
+		default:
+			paths = append(paths, CommittedPath{Commit: commit, Path: field})
+		}
+	}
+	return paths
+}
+
+// splitNul splits NUL-terminated output into its non-empty fields
+func splitNul(output string) []string {
+	fields := []string{}
+	for _, field := range strings.Split(output, "\x00") {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
 }
 
 // IsFileIgnored checks if the specified file is ignored by git
@@ -124,7 +270,7 @@ func IsFileIgnored(fileName string) functional.Result[bool] {
 func DisplaySecurityWarning(fileName string) {
 	// Generate the warning message using a pure function
 	warning := FormatSecurityWarning(fileName)
-	fmt.Println(warning)
+	fmt.Fprintln(os.Stderr, warning)
 }
 
 // FormatSecurityWarning creates a security warning message for the given file
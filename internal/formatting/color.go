@@ -5,6 +5,7 @@ package formatting
 
 import (
 	"fmt"
+	"os"
 )
 
 // ANSI Color escape codes
@@ -143,3 +144,27 @@ func EnableColors() {
 func IsColorEnabled() bool {
 	return !noColor
 }
+
+// IsTerminal reports whether the file is attached to a terminal
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorsWanted reports whether colors should be used for the given outputs.
+// Colors are turned off when NO_COLOR is set to any non-empty value (https://no-color.org)
+// or when any of the outputs is not a terminal.
+func ColorsWanted(noColorEnv string, outputs ...*os.File) bool {
+	if noColorEnv != "" {
+		return false
+	}
+	for _, output := range outputs {
+		if !IsTerminal(output) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+// Package hooks provides the git pre-commit hook that keeps resolved secrets out of commits.
+package hooks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/scan"
+)
+
+// marker identifies hooks written by 'sem hooks install', which may be overwritten
+const marker = "# Installed by 'sem hooks install'"
+
+// FrameworkConfigFile is the configuration file of the pre-commit framework (https://pre-commit.com)
+const FrameworkConfigFile = ".pre-commit-config.yaml"
+
+// Script is the pre-commit hook installed in the repository.
+// It fails closed when sem is not available.
+const Script = `#!/bin/sh
+` + marker + `. Reinstall it with 'sem hooks install --force'.
+# Rejects staged sem cache files and resolved secret values (bypass with 'git commit --no-verify').
+if ! command -v sem >/dev/null 2>&1; then
+	echo "sem pre-commit hook: 'sem' was not found in PATH" >&2
+	exit 1
+fi
+exec sem hooks pre-commit
+`
+
+// FrameworkConfig is the entry to add to .pre-commit-config.yaml when the pre-commit framework manages the hooks
+const FrameworkConfig = `repos:
+  - repo: local
+    hooks:
+      - id: sem-secrets
+        name: sem secret check
+        entry: sem hooks pre-commit
+        language: system
+        pass_filenames: false
+        always_run: true
+`
+
+// IsManaged reports whether an existing hook was written by 'sem hooks install'
+func IsManaged(content string) bool {
+	return strings.Contains(content, marker)
+}
+
+// StagedFile is a file of the git index with its staged content
+type StagedFile struct {
+	Path    string
+	Content []byte
+}
+
+// Report is the result of checking the staged files
+type Report struct {
+	Rejected []string // Problems that block the commit
+	Warnings []string // Problems reported without blocking the commit
+}
+
+// Passed reports whether the commit may proceed
+func (r Report) Passed() bool {
+	return len(r.Rejected) == 0
+}
+
+// IsEnvInput reports whether a path is an env input file (.env or *.env) rather than a generated file
+func IsEnvInput(filePath string) bool {
+	name := path.Base(filePath)
+	return strings.HasSuffix(name, profile.EnvFileExtension) && !scan.IsGeneratedPath(filePath)
+}
+
+// CheckStagedFiles rejects generated files and files containing one of the values,
+// and warns about literal credentials in env input files
+func CheckStagedFiles(files []StagedFile, values map[string]string) Report {
+	report := Report{Rejected: []string{}, Warnings: []string{}}
+
+	for _, file := range files {
+		if scan.IsGeneratedPath(file.Path) {
+			report.Rejected = append(report.Rejected, fmt.Sprintf("%s is generated by sem and must not be committed (unstage it with 'git rm --cached %s')", file.Path, file.Path))
+			continue
+		}
+
+		for _, match := range scan.FindValues(file.Path, file.Content, values) {
+			report.Rejected = append(report.Rejected, match.String())
+		}
+
+		if IsEnvInput(file.Path) {
+			for _, credential := range scan.FindPlaintextCredentials(file.Path, file.Content) {
+				report.Warnings = append(report.Warnings, credential.String())
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckStagedFiles(t *testing.T) {
+	values := map[string]string{"DB_PASSWORD (.cache.env)": "hunter2hunter2"}
+
+	tests := []struct {
+		name     string
+		files    []StagedFile
+		rejected []string
+		warnings []string
+	}{
+		{
+			name:     "Clean files",
+			files:    []StagedFile{{Path: ".env", Content: []byte("DB_PASSWORD=sem://aws:secretsmanager/dev/db?key=password\n")}},
+			rejected: []string{},
+			warnings: []string{},
+		},
+		{
+			name:     "Cache file",
+			files:    []StagedFile{{Path: "app/.cache.env", Content: []byte("A='1'\n")}},
+			rejected: []string{"app/.cache.env is generated by sem and must not be committed (unstage it with 'git rm --cached app/.cache.env')"},
+			warnings: []string{},
+		},
+		{
+			name:     "Resolved value in a staged file",
+			files:    []StagedFile{{Path: "docker-compose.yml", Content: []byte("environment:\n  PASSWORD: hunter2hunter2\n")}},
+			rejected: []string{"docker-compose.yml:2 contains the value of DB_PASSWORD (.cache.env)"},
+			warnings: []string{},
+		},
+		{
+			name:     "Literal credential in an env input",
+			files:    []StagedFile{{Path: "staging.env", Content: []byte("API_TOKEN=abc123\nLOG_LEVEL=debug\n")}},
+			rejected: []string{},
+			warnings: []string{"staging.env:1: API_TOKEN has a literal value (the name suggests a credential); store it with 'sem push' or 'sem put'"},
+		},
+		{
+			name:     "Literal credentials outside env inputs are not reported",
+			files:    []StagedFile{{Path: "README.md", Content: []byte("API_TOKEN=abc123\n")}},
+			rejected: []string{},
+			warnings: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckStagedFiles(tt.files, values)
+			if !reflect.DeepEqual(report.Rejected, tt.rejected) {
+				t.Errorf("Rejected = %q, want %q", report.Rejected, tt.rejected)
+			}
+			if !reflect.DeepEqual(report.Warnings, tt.warnings) {
+				t.Errorf("Warnings = %q, want %q", report.Warnings, tt.warnings)
+			}
+			if report.Passed() != (len(tt.rejected) == 0) {
+				t.Errorf("Passed() = %v", report.Passed())
+			}
+		})
+	}
+}
+
+func TestIsManaged(t *testing.T) {
+	if !IsManaged(Script) {
+		t.Error("IsManaged(Script) = false, want true")
+	}
+	if IsManaged("#!/bin/sh\nnpm test\n") {
+		t.Error("IsManaged() = true for a foreign hook")
+	}
+	if !strings.HasPrefix(Script, "#!/bin/sh\n") {
+		t.Error("Script must start with a shebang")
+	}
+}
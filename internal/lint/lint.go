@@ -0,0 +1,142 @@
+// Package lint checks input env files for literal secrets and entries that do not do what they look like.
+package lint
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+	"github.com/gumi-tsd/secret-env-manager/internal/scan"
+	"github.com/gumi-tsd/secret-env-manager/internal/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/text"
+)
+
+// Severity is the level of a finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The entry is wrong or holds a known credential format
+	SeverityWarning Severity = "warning" // The entry may hold a secret
+)
+
+// Rule describes one check of the linter
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+}
+
+// Rules checked by Lint
+var (
+	TokenFormatRule       = Rule{ID: "token-format", Description: "Literal value with the format of a well-known credential", Severity: SeverityError}
+	HighEntropyRule       = Rule{ID: "high-entropy", Description: "Literal value that looks randomly generated", Severity: SeverityWarning}
+	MalformedURIRule      = Rule{ID: "malformed-uri", Description: "sem:// value that is not a valid secret URI and would be used as a literal", Severity: SeverityError}
+	DuplicateKeyRule      = Rule{ID: "duplicate-key", Description: "Variable defined more than once", Severity: SeverityError}
+	InvalidIdentifierRule = Rule{ID: "invalid-identifier", Description: "Variable name that is not a valid environment variable identifier", Severity: SeverityError}
+)
+
+// AllRules lists the rules in the order they are reported
+var AllRules = []Rule{TokenFormatRule, HighEntropyRule, MalformedURIRule, DuplicateKeyRule, InvalidIdentifierRule}
+
+// Entropy thresholds for literal values; shorter or less random values are ignored
+const (
+	MinEntropyLength = 20  // Minimum length of a value checked for entropy
+	MinEntropy       = 3.5 // Minimum Shannon entropy in bits per character
+)
+
+// identifierPattern matches names that can be used as environment variables
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Finding is a problem found on a line of the input file. It never contains the value.
+type Finding struct {
+	Rule    Rule
+	Path    string
+	Line    int
+	Key     string
+	Message string
+}
+
+// String formats the finding as path:line: message [rule]
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s [%s]", f.Path, f.Line, f.Message, f.Rule.ID)
+}
+
+// HasErrors reports whether any finding has the error severity
+func HasErrors(findings []Finding) bool {
+	return functional.Any(findings, func(finding Finding) bool {
+		return finding.Rule.Severity == SeverityError
+	})
+}
+
+// LintResult parses env file content and returns its findings in line order
+func LintResult(path string, content []byte) functional.Result[[]Finding] {
+	return functional.MapResultTo(parser.ParsePlainFileContentResult(content), func(entries []env.Entry) []Finding {
+		return Lint(path, entries)
+	})
+}
+
+// Lint returns the findings of parsed env file entries in line order
+func Lint(path string, entries []env.Entry) []Finding {
+	findings := []Finding{}
+	firstLines := map[string]int{}
+
+	for _, entry := range entries {
+		finding := func(rule Rule, message string) Finding {
+			return Finding{Rule: rule, Path: path, Line: entry.Index, Key: entry.Key, Message: message}
+		}
+
+		// A bare line holding a secret URI has no name of its own
+		if entry.Value == "" && secret.IsURI(entry.Key) {
+			if err := uri.ValidateResult(entry.Key).GetError(); err != nil {
+				malformed := finding(MalformedURIRule, fmt.Sprintf("malformed secret URI: %v", err))
+				malformed.Key = ""
+				findings = append(findings, malformed)
+			}
+			continue
+		}
+
+		if !identifierPattern.MatchString(entry.Key) {
+			findings = append(findings, finding(InvalidIdentifierRule, fmt.Sprintf("%q is not a valid variable name", entry.Key)))
+		}
+		if first, found := firstLines[entry.Key]; found {
+			findings = append(findings, finding(DuplicateKeyRule, fmt.Sprintf("%s is already defined on line %d", entry.Key, first)))
+		} else {
+			firstLines[entry.Key] = entry.Index
+		}
+
+		value := formatting.UnwrapQuotes(strings.TrimSpace(entry.Value))
+		if secret.IsURI(value) {
+			if err := uri.ValidateResult(value).GetError(); err != nil {
+				findings = append(findings, finding(MalformedURIRule, fmt.Sprintf("%s has a malformed secret URI: %v", entry.Key, err)))
+			}
+			continue
+		}
+		if format := scan.MatchTokenFormat(value); format.IsSome() {
+			findings = append(findings, finding(TokenFormatRule, fmt.Sprintf("%s has a literal %s; store it with 'sem push' or 'sem put'", entry.Key, format.Unwrap())))
+			continue
+		}
+		if IsHighEntropy(value) {
+			findings = append(findings, finding(HighEntropyRule, fmt.Sprintf("%s has a literal value that looks randomly generated; store it with 'sem push' or 'sem put'", entry.Key)))
+		}
+	}
+	return findings
+}
+
+// IsHighEntropy reports whether a literal value looks randomly generated.
+// Only the password of a URL is checked; values with whitespace and ${VAR} references are never reported.
+func IsHighEntropy(value string) bool {
+	if parsed, err := url.Parse(value); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		password, _ := parsed.User.Password()
+		value = password
+	}
+	if len(value) < MinEntropyLength || strings.ContainsAny(value, " \t") || strings.Contains(value, "${") {
+		return false
+	}
+	return text.ShannonEntropy(value) >= MinEntropy
+}
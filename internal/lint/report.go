@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// Format is the output format of the findings
+type Format string
+
+const (
+	TextFormat  Format = "text"
+	JSONFormat  Format = "json"
+	SARIFFormat Format = "sarif"
+)
+
+// ParseFormatResult parses an output format name
+func ParseFormatResult(name string) functional.Result[Format] {
+	switch format := Format(strings.ToLower(name)); format {
+	case TextFormat, JSONFormat, SARIFFormat:
+		return functional.Success(format)
+	}
+	return functional.Failure[Format](
+		fmt.Errorf("unknown output format '%s' (expected text, json or sarif)", name))
+}
+
+// jsonFinding is a finding as written by FormatJSON
+type jsonFinding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Line     int      `json:"line"`
+	Key      string   `json:"key,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// FormatJSON formats the findings as a JSON array
+func FormatJSON(findings []Finding) functional.Result[string] {
+	records := functional.Map(findings, func(finding Finding) jsonFinding {
+		return jsonFinding{
+			Rule:     finding.Rule.ID,
+			Severity: finding.Rule.Severity,
+			Path:     finding.Path,
+			Line:     finding.Line,
+			Key:      finding.Key,
+			Message:  finding.Message,
+		}
+	})
+	return marshalIndent(records)
+}
+
+// SARIF 2.1.0 log (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html), limited to the properties sem writes
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifSchema is the JSON schema of SARIF 2.1.0 logs
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// informationURI is the home page of sem reported as the SARIF tool
+const informationURI = "https://github.com/gumi-tsd/secret-env-manager"
+
+// FormatSARIF formats the findings as a SARIF 2.1.0 log for code scanning tools
+func FormatSARIF(findings []Finding, toolVersion string) functional.Result[string] {
+	rules := functional.Map(AllRules, func(rule Rule) sarifRule {
+		return sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		}
+	})
+	results := functional.Map(findings, func(finding Finding) sarifResult {
+		return sarifResult{
+			RuleID:    finding.Rule.ID,
+			RuleIndex: ruleIndex(finding.Rule),
+			Level:     finding.Rule.Severity,
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: finding.Path},
+				Region:           sarifRegion{StartLine: finding.Line},
+			}}},
+		}
+	})
+
+	return marshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "sem",
+				Version:        toolVersion,
+				InformationURI: informationURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	})
+}
+
+// ruleIndex returns the position of a rule in AllRules
+func ruleIndex(rule Rule) int {
+	for i, candidate := range AllRules {
+		if candidate.ID == rule.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// marshalIndent encodes a value as indented JSON, keeping <, > and & readable in messages
+func marshalIndent(value interface{}) functional.Result[string] {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return functional.Failure[string](fmt.Errorf("failed to encode the findings: %w", err))
+	}
+	return functional.Success(strings.TrimSuffix(buffer.String(), "\n"))
+}
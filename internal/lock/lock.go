@@ -0,0 +1,171 @@
+// Package lock pins the secrets referenced by an input file to concrete versions.
+// The lockfile contains no secret values and is meant to be committed, so that
+// environments are reproducible and secret rotations show up in code review.
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider"
+)
+
+// FileSuffix is appended to the input file name to form the lockfile name
+const FileSuffix = ".lock"
+
+// lockFilePerm allows the lockfile to be read by others, as it contains no secrets
+const lockFilePerm = 0644
+
+// Pin records the concrete version a secret reference resolved to
+type Pin struct {
+	URI     string `json:"uri"`     // Secret reference (URI without key and expansion options)
+	Version string `json:"version"` // Version ID (AWS) or version number (Google Cloud)
+}
+
+// Lockfile is the content of a lockfile
+type Lockfile struct {
+	Input   string `json:"input"`
+	Secrets []Pin  `json:"secrets"`
+}
+
+// Change describes how the pinned version of a secret changed
+type Change struct {
+	URI      string
+	Previous string // Empty for newly pinned secrets
+	Current  string // Empty for secrets that are no longer referenced
+}
+
+// String formats the change for display
+func (c Change) String() string {
+	switch {
+	case c.Previous == "":
+		return fmt.Sprintf("+ %s @ %s", c.URI, c.Current)
+	case c.Current == "":
+		return fmt.Sprintf("- %s @ %s", c.URI, c.Previous)
+	default:
+		return fmt.Sprintf("~ %s @ %s -> %s", c.URI, c.Previous, c.Current)
+	}
+}
+
+// FileName returns the lockfile name for an input file
+func FileName(inputFileName string) string {
+	return inputFileName + FileSuffix
+}
+
+// New creates a lockfile from the versions resolved for each secret reference, sorted by URI
+func New(input string, versions map[string]string) Lockfile {
+	pins := make([]Pin, 0, len(versions))
+	for reference, version := range versions {
+		pins = append(pins, Pin{URI: reference, Version: version})
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].URI < pins[j].URI
+	})
+	return Lockfile{Input: input, Secrets: pins}
+}
+
+// Versions returns the pinned version of each secret reference
+func (l Lockfile) Versions() map[string]string {
+	versions := make(map[string]string, len(l.Secrets))
+	for _, pin := range l.Secrets {
+		versions[pin.URI] = pin.Version
+	}
+	return versions
+}
+
+// Diff lists the secrets whose pinned version differs between two lockfiles
+func Diff(previous, current Lockfile) []Change {
+	before := previous.Versions()
+	after := current.Versions()
+
+	changes := []Change{}
+	for _, pin := range current.Secrets {
+		if before[pin.URI] != pin.Version {
+			changes = append(changes, Change{URI: pin.URI, Previous: before[pin.URI], Current: pin.Version})
+		}
+	}
+	for _, pin := range previous.Secrets {
+		if _, exists := after[pin.URI]; !exists {
+			changes = append(changes, Change{URI: pin.URI, Previous: pin.Version})
+		}
+	}
+	return changes
+}
+
+// Apply rewrites the secret URIs of the entries to fetch their pinned versions.
+// Every secret referenced by the entries must be pinned.
+func (l Lockfile) Apply(entries []env.Entry) functional.Result[[]env.Entry] {
+	versions := l.Versions()
+	pinned := make([]env.Entry, 0, len(entries))
+	missing := []string{}
+
+	for _, entry := range entries {
+		uriResult := provider.ParseEntryAsSecretURI(entry)
+		if uriResult.IsFailure() || !isLockable(uriResult.Unwrap()) {
+			pinned = append(pinned, entry)
+			continue
+		}
+		secretURI := uriResult.Unwrap()
+
+		version, exists := versions[secretURI.Reference()]
+		if !exists {
+			missing = append(missing, fmt.Sprintf("  %s (%s)", secretURI.Reference(), entry.Location()))
+			continue
+		}
+		pinned = append(pinned, provider.WithEntryURI(entry, secretURI.WithVersion(version)))
+	}
+
+	if len(missing) > 0 {
+		return functional.Failure[[]env.Entry](fmt.Errorf(
+			"secrets are not pinned in the lockfile (run 'sem lock' to update it):\n%s", strings.Join(missing, "\n")))
+	}
+	return functional.Success(pinned)
+}
+
+// isLockable checks if the URI refers to a platform whose secrets are versioned
+func isLockable(secretURI uri.SecretURI) bool {
+	return secretURI.Platform == uri.AwsPlatform || secretURI.Platform == uri.GoogleCloudPlatform
+}
+
+// WriteResult writes the lockfile
+func WriteResult(fileName string, lockfile Lockfile) functional.Result[bool] {
+	data, err := json.MarshalIndent(lockfile, "", "  ")
+	if err != nil {
+		return functional.Failure[bool](fmt.Errorf("failed to encode lockfile: %w", err))
+	}
+
+	writeResult := fileio.WriteStringToFile(fileName, string(data)+"\n")
+	if writeResult.IsFailure() {
+		return writeResult
+	}
+	if err := os.Chmod(fileName, lockFilePerm); err != nil {
+		return functional.Failure[bool](fmt.Errorf("failed to set permissions on lockfile '%s': %w", fileName, err))
+	}
+	return functional.Success(true)
+}
+
+// ReadResult reads a lockfile. A missing file is reported as None.
+func ReadResult(fileName string) functional.Result[functional.Option[Lockfile]] {
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return functional.Success(functional.None[Lockfile]())
+	}
+	if err != nil {
+		return functional.Failure[functional.Option[Lockfile]](
+			fmt.Errorf("failed to read lockfile '%s': %w", fileName, err))
+	}
+
+	var lockfile Lockfile
+	if err := json.Unmarshal(data, &lockfile); err != nil {
+		return functional.Failure[functional.Option[Lockfile]](
+			fmt.Errorf("invalid lockfile '%s': %w", fileName, err))
+	}
+	return functional.Success(functional.Some(lockfile))
+}
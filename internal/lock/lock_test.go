@@ -0,0 +1,106 @@
+package lock
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+const (
+	dbReference    = "sem://aws:secretsmanager/default/db?version=AWSCURRENT&region=ap-northeast-1"
+	tokenReference = "sem://googlecloud:secretmanager/project/token?version=latest"
+)
+
+func TestApply(t *testing.T) {
+	lockfile := New(".env", map[string]string{
+		dbReference:    "a1b2c3d4-5678-90ab-cdef-111111111111",
+		tokenReference: "7",
+	})
+
+	tests := []struct {
+		name        string
+		entries     []env.Entry
+		expected    []env.Entry
+		errContains string
+	}{
+		{
+			name: "Secret URIs are pinned and other entries are kept",
+			entries: []env.Entry{
+				env.NewEntry(1, "DB_PASSWORD", "sem://aws:secretsmanager/default/db?key=password"),
+				env.NewEntry(2, "sem://googlecloud:secretmanager/project/token", ""),
+				env.NewEntry(3, "PORT", "8080"),
+			},
+			expected: []env.Entry{
+				env.NewEntry(1, "DB_PASSWORD",
+					"sem://aws:secretsmanager/default/db?version=a1b2c3d4-5678-90ab-cdef-111111111111&key=password&region=ap-northeast-1"),
+				env.NewEntry(2, "sem://googlecloud:secretmanager/project/token?version=7", ""),
+				env.NewEntry(3, "PORT", "8080"),
+			},
+		},
+		{
+			name: "Unpinned secrets are an error",
+			entries: []env.Entry{
+				env.NewEntry(1, "API", "sem://aws:secretsmanager/default/api").WithSource(".env"),
+			},
+			errContains: "sem://aws:secretsmanager/default/api?version=AWSCURRENT&region=ap-northeast-1 (.env:1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lockfile.Apply(tt.entries)
+
+			if tt.errContains != "" {
+				if result.IsSuccess() {
+					t.Fatalf("expected error, got %+v", result.Unwrap())
+				}
+				if !strings.Contains(result.GetError().Error(), tt.errContains) {
+					t.Errorf("error %q does not contain %q", result.GetError().Error(), tt.errContains)
+				}
+				return
+			}
+
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("Apply() = %+v, want %+v", result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := New(".env", map[string]string{dbReference: "v1", tokenReference: "3"})
+	current := New(".env", map[string]string{dbReference: "v2", "sem://aws:secretsmanager/default/new?version=AWSCURRENT&region=ap-northeast-1": "v9"})
+
+	expected := []Change{
+		{URI: dbReference, Previous: "v1", Current: "v2"},
+		{URI: "sem://aws:secretsmanager/default/new?version=AWSCURRENT&region=ap-northeast-1", Current: "v9"},
+		{URI: tokenReference, Previous: "3"},
+	}
+	if changes := Diff(previous, current); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff() = %+v, want %+v", changes, expected)
+	}
+}
+
+func TestWriteAndReadResult(t *testing.T) {
+	fileName := FileName(filepath.Join(t.TempDir(), ".env"))
+
+	if missing := ReadResult(fileName); missing.IsFailure() || missing.Unwrap().IsSome() {
+		t.Fatalf("ReadResult() of a missing file = %+v, want None", missing)
+	}
+
+	lockfile := New(".env", map[string]string{dbReference: "v1"})
+	if result := WriteResult(fileName, lockfile); result.IsFailure() {
+		t.Fatalf("WriteResult() failed: %v", result.GetError())
+	}
+
+	result := ReadResult(fileName)
+	if result.IsFailure() || !reflect.DeepEqual(result.Unwrap().Unwrap(), lockfile) {
+		t.Errorf("ReadResult() = %+v, want %+v", result, lockfile)
+	}
+}
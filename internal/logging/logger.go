@@ -2,12 +2,17 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
 )
 
 // Level represents the severity level of a log message
@@ -26,20 +31,85 @@ const (
 	SuccessLevel
 )
 
+// String returns the lowercase name of the level used in JSON logs
+func (level Level) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case SuccessLevel:
+		return "success"
+	default:
+		return "unknown"
+	}
+}
+
+// severity returns the level used for filtering.
+// Success messages are informational, so quiet mode hides them like info messages.
+func (level Level) severity() Level {
+	if level == SuccessLevel {
+		return InfoLevel
+	}
+	return level
+}
+
+// Format represents how log messages are written
+type Format int
+
+const (
+	// TextFormat writes human-readable lines with a level prefix
+	TextFormat Format = iota
+	// JSONFormat writes one JSON object per line
+	JSONFormat
+)
+
+// ParseFormatResult converts a format name ("text" or "json") into a Format
+func ParseFormatResult(name string) functional.Result[Format] {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "text":
+		return functional.Success(TextFormat)
+	case "json":
+		return functional.Success(JSONFormat)
+	default:
+		return functional.Failure[Format](fmt.Errorf("unsupported log format '%s' (expected 'text' or 'json')", name))
+	}
+}
+
+// ansiPattern matches the color escape sequences added by the formatting package
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
 // Logger provides structured logging functionality
 type Logger struct {
 	writer    io.Writer
 	minLevel  Level
 	colorized bool
+	format    Format
 }
 
-// DefaultLogger creates a new logger with default settings
+// defaultLogger is shared by every package so that Configure applies everywhere.
+// Diagnostics go to stderr to keep stdout free for command output such as 'sem load'.
+var defaultLogger = &Logger{
+	writer:    os.Stderr,
+	minLevel:  InfoLevel, // Default log level is Info
+	colorized: true,
+	format:    TextFormat,
+}
+
+// DefaultLogger returns the shared logger that writes to stderr
 func DefaultLogger() *Logger {
-	return &Logger{
-		writer:    os.Stdout,
-		minLevel:  InfoLevel, // Default log level is Info
-		colorized: true,
-	}
+	return defaultLogger
+}
+
+// Configure changes the level, format and colorization of the shared logger
+func Configure(minLevel Level, format Format, colorized bool) {
+	defaultLogger.minLevel = minLevel
+	defaultLogger.format = format
+	defaultLogger.colorized = colorized
 }
 
 // NewLogger creates a new logger with custom settings
@@ -48,22 +118,35 @@ func NewLogger(writer io.Writer, minLevel Level, colorized bool) *Logger {
 		writer:    writer,
 		minLevel:  minLevel,
 		colorized: colorized,
+		format:    TextFormat,
 	}
 }
 
 // WithLevel returns a copy of the logger with a new minimum log level
 func (l *Logger) WithLevel(level Level) *Logger {
-	return NewLogger(l.writer, level, l.colorized)
+	return l.copyWith(func(c *Logger) { c.minLevel = level })
 }
 
 // WithColorized returns a copy of the logger with colorization setting
 func (l *Logger) WithColorized(colorized bool) *Logger {
-	return NewLogger(l.writer, l.minLevel, colorized)
+	return l.copyWith(func(c *Logger) { c.colorized = colorized })
 }
 
 // WithWriter returns a copy of the logger with a new writer
 func (l *Logger) WithWriter(writer io.Writer) *Logger {
-	return NewLogger(writer, l.minLevel, l.colorized)
+	return l.copyWith(func(c *Logger) { c.writer = writer })
+}
+
+// WithFormat returns a copy of the logger with a new output format
+func (l *Logger) WithFormat(format Format) *Logger {
+	return l.copyWith(func(c *Logger) { c.format = format })
+}
+
+// copyWith returns a copy of the logger with the given change applied
+func (l *Logger) copyWith(change func(*Logger)) *Logger {
+	copied := *l
+	change(&copied)
+	return &copied
 }
 
 // Debug logs a debug message if the level permits
@@ -93,7 +176,13 @@ func (l *Logger) Success(format string, args ...interface{}) {
 
 // log handles the actual message formatting and output
 func (l *Logger) log(level Level, format string, args ...interface{}) {
-	if level < l.minLevel {
+	if level.severity() < l.minLevel {
+		return
+	}
+
+	// Resolved secret values never reach the log, whatever the message echoes
+	if l.format == JSONFormat {
+		l.logJSON(level, redact.Message(fmt.Sprintf(format, args...)))
 		return
 	}
 
@@ -111,7 +200,7 @@ func (l *Logger) log(level Level, format string, args ...interface{}) {
 		prefix = "[SUCCESS] "
 	}
 
-	message := fmt.Sprintf(format, args...)
+	message := redact.Message(fmt.Sprintf(format, args...))
 	if l.colorized {
 		switch level {
 		case DebugLevel:
@@ -131,6 +220,26 @@ func (l *Logger) log(level Level, format string, args ...interface{}) {
 	fmt.Fprintf(l.writer, "%s%s\n", prefix, message)
 }
 
+// jsonEntry is a single line written in JSON format
+type jsonEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// logJSON writes the message as a JSON line without color codes
+func (l *Logger) logJSON(level Level, message string) {
+	data, err := json.Marshal(jsonEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Level:   level.String(),
+		Message: ansiPattern.ReplaceAllString(message, ""),
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(l.writer, "%s\n", data)
+}
+
 // LoggingIO wraps logging functionality in an IO monad
 type LoggingIO struct {
 	logger *Logger
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoggerLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		minLevel Level
+		want     []string
+	}{
+		{
+			name:     "Default level hides debug",
+			minLevel: InfoLevel,
+			want:     []string{"[INFO] info", "[WARN] warn", "[ERROR] error", "[SUCCESS] success"},
+		},
+		{
+			name:     "Quiet hides info and success",
+			minLevel: WarnLevel,
+			want:     []string{"[WARN] warn", "[ERROR] error"},
+		},
+		{
+			name:     "Verbose shows debug",
+			minLevel: DebugLevel,
+			want:     []string{"[DEBUG] debug", "[INFO] info", "[WARN] warn", "[ERROR] error", "[SUCCESS] success"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(&buf, tt.minLevel, false)
+
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+			logger.Success("success")
+
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, InfoLevel, true).WithFormat(JSONFormat)
+
+	logger.Warn("cache is \x1b[31mstale\x1b[0m")
+
+	var entry map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not a JSON line: %q (%v)", buf.String(), err)
+	}
+	if entry["level"] != "warn" {
+		t.Errorf("level = %q, want %q", entry["level"], "warn")
+	}
+	if entry["message"] != "cache is stale" {
+		t.Errorf("message = %q, want %q", entry["message"], "cache is stale")
+	}
+	if entry["time"] == "" {
+		t.Error("time is empty")
+	}
+}
+
+func TestParseFormatResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{name: "Empty defaults to text", input: "", want: TextFormat},
+		{name: "Text", input: "text", want: TextFormat},
+		{name: "JSON is case-insensitive", input: "JSON", want: JSONFormat},
+		{name: "Unknown format", input: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseFormatResult(tt.input)
+			if result.IsFailure() != tt.wantErr {
+				t.Fatalf("ParseFormatResult(%q) error = %v, wantErr %v", tt.input, result.GetError(), tt.wantErr)
+			}
+			if !tt.wantErr && result.Unwrap() != tt.want {
+				t.Errorf("ParseFormatResult(%q) = %v, want %v", tt.input, result.Unwrap(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package migrate plans copying the secrets referenced by an env file to another
+// platform or account and rewriting their URIs.
+package migrate
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+)
+
+// MaxValueSize is the largest secret value both AWS Secrets Manager and Google Cloud Secret Manager accept (64 KiB)
+const MaxValueSize = 65536
+
+// maxNameLength is the longest secret name each platform accepts
+var maxNameLength = map[string]int{
+	uri.AwsPlatform:         512,
+	uri.GoogleCloudPlatform: 255,
+}
+
+// googleCloudInvalidChars matches characters Google Cloud secret IDs cannot contain
+var googleCloudInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Destination is the platform, service and account secrets are copied to
+type Destination struct {
+	Platform string
+	Service  string
+	Account  string
+	Region   string // AWS only
+}
+
+// ParseDestinationResult parses a destination such as "aws:secretsmanager/<profile>?region=us-east-1"
+// or "googlecloud:secretmanager/<project>". The sem:// prefix is optional.
+func ParseDestinationResult(raw string) functional.Result[Destination] {
+	path, query, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(raw), uri.URIPrefix), "?")
+	platformService, account, hasAccount := strings.Cut(path, "/")
+	platform, service, hasService := strings.Cut(platformService, ":")
+	if !hasAccount || !hasService || platform == "" || service == "" || account == "" || strings.Contains(account, "/") {
+		return functional.Failure[Destination](fmt.Errorf("invalid destination '%s': expected '<platform>:<service>/<account>'", raw))
+	}
+	if platform != uri.AwsPlatform && platform != uri.GoogleCloudPlatform {
+		return functional.Failure[Destination](fmt.Errorf("unsupported platform '%s'", platform))
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return functional.Failure[Destination](fmt.Errorf("invalid destination query: %w", err))
+	}
+	region := values.Get("region")
+	if platform == uri.AwsPlatform && region == "" {
+		region = uri.AwsDefaultRegion
+	}
+
+	return functional.Success(Destination{Platform: platform, Service: service, Account: account, Region: region})
+}
+
+// String formats the destination as it is written on the command line
+func (d Destination) String() string {
+	if d.Region == "" {
+		return fmt.Sprintf("%s:%s/%s", d.Platform, d.Service, d.Account)
+	}
+	return fmt.Sprintf("%s:%s/%s?region=%s", d.Platform, d.Service, d.Account, d.Region)
+}
+
+// Contains checks if a secret already lives in the destination
+func (d Destination) Contains(secretURI uri.SecretURI) bool {
+	return secretURI.Platform == d.Platform && secretURI.Service == d.Service &&
+		secretURI.Account == d.Account && secretURI.Region == d.Region
+}
+
+// SecretURI returns the URI of a secret after it is copied to the destination.
+// The key and expansion options are kept and the version becomes the current one.
+// The endpoint and credentials of the source are dropped, as they belong to the source platform and account.
+func (d Destination) SecretURI(source uri.SecretURI) uri.SecretURI {
+	result := source.WithEndpointSettings(endpoint.Settings{})
+	result.Platform = d.Platform
+	result.Service = d.Service
+	result.Account = d.Account
+	result.Region = d.Region
+	result.SecretName = SecretName(d.Platform, source.SecretName)
+	if d.Platform == uri.AwsPlatform {
+		result.Version = uri.AwsDefaultVersion
+	} else {
+		result.Version = uri.GoogleCloudDefaultVersion
+	}
+	return result
+}
+
+// SecretName maps a secret name to one the platform accepts.
+// Google Cloud secret IDs only allow letters, digits, '_' and '-', so other characters become '_'.
+func SecretName(platform, name string) string {
+	if platform == uri.GoogleCloudPlatform {
+		return googleCloudInvalidChars.ReplaceAllString(name, "_")
+	}
+	return name
+}
+
+// CheckValue lists the reasons a value cannot be stored on a platform
+func CheckValue(platform, value string) []string {
+	problems := []string{}
+	if len(value) > MaxValueSize {
+		problems = append(problems, fmt.Sprintf("value is %d bytes, the limit is %d", len(value), MaxValueSize))
+	}
+	if platform == uri.AwsPlatform && !utf8.ValidString(value) {
+		problems = append(problems, "binary value cannot be stored as an AWS secret string")
+	}
+	return problems
+}
+
+// Copy is a secret version copied to the destination
+type Copy struct {
+	Source      uri.SecretURI // Secret version to read (without key and expansion options)
+	Destination uri.SecretURI // Secret to write (without key and expansion options)
+	Lines       []int         // Lines of the env file that reference the source
+}
+
+// Plan describes the secrets to copy and the lines to rewrite
+type Plan struct {
+	Copies   []Copy
+	Edits    []parser.LineEdit
+	Problems []string // Naming conflicts and names the destination does not accept
+}
+
+// NewPlanResult collects the secret URIs of an env file that are not in the destination yet.
+// Includes are not followed, as their lines belong to other files.
+func NewPlanResult(content []byte, destination Destination) functional.Result[Plan] {
+	linesResult := parser.NewContentLinesResult(parser.PreprocessContent(content))
+	if linesResult.IsFailure() {
+		return functional.Failure[Plan](linesResult.GetError())
+	}
+
+	plan := Plan{}
+	copies := map[string]int{} // Source reference -> index in plan.Copies
+	for _, line := range linesResult.Unwrap() {
+		rawURI, isReference := lineURI(line)
+		if !isReference {
+			continue
+		}
+		uriResult := uri.ParseResult(rawURI)
+		if uriResult.IsFailure() {
+			continue
+		}
+		source := uriResult.Unwrap()
+		if (source.Platform != uri.AwsPlatform && source.Platform != uri.GoogleCloudPlatform) || destination.Contains(source) {
+			continue
+		}
+
+		target := destination.SecretURI(source)
+		plan.Edits = append(plan.Edits, parser.LineEdit{
+			Number: line.Number,
+			Before: line.Content,
+			After:  strings.Replace(line.Content, rawURI, target.GetUri(), 1),
+		})
+
+		reference := source.Reference()
+		if index, exists := copies[reference]; exists {
+			plan.Copies[index].Lines = append(plan.Copies[index].Lines, line.Number)
+			continue
+		}
+		copies[reference] = len(plan.Copies)
+		plan.Copies = append(plan.Copies, Copy{
+			Source:      secretOnly(source),
+			Destination: secretOnly(target),
+			Lines:       []int{line.Number},
+		})
+	}
+
+	plan.Problems = namingProblems(plan.Copies, destination)
+	return functional.Success(plan)
+}
+
+// lineURI returns the secret URI written on a line
+func lineURI(line parser.Line) (string, bool) {
+	switch {
+	case line.IsSecret():
+		return line.Trimmed, true
+	case line.IsKeyValue():
+		entryResult := line.ToEnvEntry()
+		if entryResult.IsFailure() {
+			return "", false
+		}
+		value := formatting.UnwrapQuotes(strings.TrimSpace(entryResult.Unwrap().Value))
+		return value, strings.HasPrefix(value, uri.URIPrefix)
+	default:
+		return "", false
+	}
+}
+
+// secretOnly removes the key and expansion options of a URI
+func secretOnly(secretURI uri.SecretURI) uri.SecretURI {
+	return secretURI.WithKey("").WithExpand("").WithPrefix("").WithRename("")
+}
+
+// namingProblems reports destination secrets written from more than one source and names that are too long
+func namingProblems(copies []Copy, destination Destination) []string {
+	sources := map[string][]string{}
+	for _, c := range copies {
+		sources[c.Destination.SecretName] = append(sources[c.Destination.SecretName], c.Source.Reference())
+	}
+
+	problems := []string{}
+	for name, references := range sources {
+		if len(references) > 1 {
+			problems = append(problems, fmt.Sprintf("'%s' would be written from %d sources: %s",
+				name, len(references), strings.Join(references, ", ")))
+		}
+		if len(name) > maxNameLength[destination.Platform] {
+			problems = append(problems, fmt.Sprintf("'%s' is longer than %d characters", name, maxNameLength[destination.Platform]))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,172 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+)
+
+func TestParseDestinationResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected Destination
+		wantErr  bool
+	}{
+		{
+			name:     "AWS with default region",
+			raw:      "aws:secretsmanager/prod",
+			expected: Destination{Platform: "aws", Service: "secretsmanager", Account: "prod", Region: "ap-northeast-1"},
+		},
+		{
+			name:     "AWS with region and prefix",
+			raw:      "sem://aws:secretsmanager/prod?region=us-east-1",
+			expected: Destination{Platform: "aws", Service: "secretsmanager", Account: "prod", Region: "us-east-1"},
+		},
+		{
+			name:     "Google Cloud",
+			raw:      "googlecloud:secretmanager/my-project",
+			expected: Destination{Platform: "googlecloud", Service: "secretmanager", Account: "my-project"},
+		},
+		{name: "Missing account", raw: "aws:secretsmanager", wantErr: true},
+		{name: "Secret name given", raw: "aws:secretsmanager/prod/app", wantErr: true},
+		{name: "Unsupported platform", raw: "azure:keyvault/prod", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseDestinationResult(tt.raw)
+			if tt.wantErr {
+				if result.IsSuccess() {
+					t.Fatalf("expected an error, got %+v", result.Unwrap())
+				}
+				return
+			}
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if result.Unwrap() != tt.expected {
+				t.Errorf("ParseDestinationResult() = %+v, want %+v", result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		value    string
+		expected int
+	}{
+		{"Text value", uri.AwsPlatform, "secret", 0},
+		{"Binary value to AWS", uri.AwsPlatform, "\xff\xfe", 1},
+		{"Binary value to Google Cloud", uri.GoogleCloudPlatform, "\xff\xfe", 0},
+		{"Value over the size limit", uri.GoogleCloudPlatform, strings.Repeat("a", MaxValueSize+1), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if problems := CheckValue(tt.platform, tt.value); len(problems) != tt.expected {
+				t.Errorf("CheckValue() = %v, want %d problems", problems, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewPlanResult(t *testing.T) {
+	content := []byte(`# Google Cloud secrets
+DB_PASSWORD=sem://googlecloud:secretmanager/proj/db?key=password
+DB_USER=sem://googlecloud:secretmanager/proj/db?key=user
+sem://googlecloud:secretmanager/proj/app.config
+APP_CONFIG=sem://googlecloud:secretmanager/proj/app-config
+ALREADY=sem://aws:secretsmanager/prod/done
+PORT=8080
+`)
+	aws := Destination{Platform: "aws", Service: "secretsmanager", Account: "prod", Region: "ap-northeast-1"}
+	google := Destination{Platform: "googlecloud", Service: "secretmanager", Account: "other"}
+
+	t.Run("Copies and edits", func(t *testing.T) {
+		plan := NewPlanResult(content, aws).Unwrap()
+
+		wantCopies := []Copy{
+			{
+				Source:      uri.NewSecretURI("googlecloud", "secretmanager", "proj", "db").WithVersion("latest"),
+				Destination: uri.NewSecretURI("aws", "secretsmanager", "prod", "db").WithVersion("AWSCURRENT").WithRegion("ap-northeast-1"),
+				Lines:       []int{2, 3},
+			},
+			{
+				Source:      uri.NewSecretURI("googlecloud", "secretmanager", "proj", "app.config").WithVersion("latest"),
+				Destination: uri.NewSecretURI("aws", "secretsmanager", "prod", "app.config").WithVersion("AWSCURRENT").WithRegion("ap-northeast-1"),
+				Lines:       []int{4},
+			},
+			{
+				Source:      uri.NewSecretURI("googlecloud", "secretmanager", "proj", "app-config").WithVersion("latest"),
+				Destination: uri.NewSecretURI("aws", "secretsmanager", "prod", "app-config").WithVersion("AWSCURRENT").WithRegion("ap-northeast-1"),
+				Lines:       []int{5},
+			},
+		}
+		if !reflect.DeepEqual(plan.Copies, wantCopies) {
+			t.Errorf("Copies = %+v, want %+v", plan.Copies, wantCopies)
+		}
+
+		wantEdit := parser.LineEdit{
+			Number: 2,
+			Before: "DB_PASSWORD=sem://googlecloud:secretmanager/proj/db?key=password",
+			After:  "DB_PASSWORD=sem://aws:secretsmanager/prod/db?version=AWSCURRENT&key=password&region=ap-northeast-1",
+		}
+		if len(plan.Edits) != 4 || plan.Edits[0] != wantEdit {
+			t.Errorf("Edits = %+v, want 4 edits starting with %+v", plan.Edits, wantEdit)
+		}
+		if len(plan.Problems) != 0 {
+			t.Errorf("Problems = %v, want none", plan.Problems)
+		}
+	})
+
+	t.Run("Source endpoint settings are not copied to the destination", func(t *testing.T) {
+		local := []byte("X=sem://aws:secretsmanager/dev/x?endpoint=http://localhost:4566&credentials=static\n")
+		plan := NewPlanResult(local, google).Unwrap()
+
+		wantCopy := Copy{
+			Source: uri.NewSecretURI("aws", "secretsmanager", "dev", "x").WithVersion("AWSCURRENT").WithRegion("ap-northeast-1").
+				WithEndpointSettings(endpoint.Settings{Endpoint: "http://localhost:4566", Credentials: "static"}),
+			Destination: uri.NewSecretURI("googlecloud", "secretmanager", "other", "x").WithVersion("latest"),
+			Lines:       []int{1},
+		}
+		if len(plan.Copies) != 1 || !reflect.DeepEqual(plan.Copies[0], wantCopy) {
+			t.Errorf("Copies = %+v, want [%+v]", plan.Copies, wantCopy)
+		}
+		wantAfter := "X=sem://googlecloud:secretmanager/other/x?version=latest"
+		if len(plan.Edits) != 1 || plan.Edits[0].After != wantAfter {
+			t.Errorf("Edits = %+v, want one edit to %q", plan.Edits, wantAfter)
+		}
+	})
+
+	t.Run("Lone CR line endings", func(t *testing.T) {
+		crContent := []byte("# comment\rA=sem://aws:secretsmanager/prod/a\rB=sem://aws:secretsmanager/prod/b\r")
+		plan := NewPlanResult(crContent, google).Unwrap()
+
+		want := "# comment\r" +
+			"A=sem://googlecloud:secretmanager/other/a?version=latest\r" +
+			"B=sem://googlecloud:secretmanager/other/b?version=latest\r"
+		if got := string(parser.ApplyLineEdits(crContent, plan.Edits)); got != want {
+			t.Errorf("ApplyLineEdits() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Naming conflicts", func(t *testing.T) {
+		conflicting := []byte("A=sem://aws:secretsmanager/prod/app.config\nB=sem://aws:secretsmanager/prod/app_config\n")
+		plan := NewPlanResult(conflicting, google).Unwrap()
+
+		wantProblems := []string{"'app_config' would be written from 2 sources: " +
+			"sem://aws:secretsmanager/prod/app.config?version=AWSCURRENT&region=ap-northeast-1, " +
+			"sem://aws:secretsmanager/prod/app_config?version=AWSCURRENT&region=ap-northeast-1"}
+		if !reflect.DeepEqual(plan.Problems, wantProblems) {
+			t.Errorf("Problems = %v, want %v", plan.Problems, wantProblems)
+		}
+	})
+}
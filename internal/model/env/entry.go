@@ -1,14 +1,36 @@
 // Package env provides environment variable related models and utilities
 package env
 
-import "github.com/gumi-tsd/secret-env-manager/internal/functional"
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
 
 // Entry represents a key-value pair from an environment file line.
 // This is used when parsing input configuration files.
 type Entry struct {
-	Index int    // Position in the original file
-	Key   string // Environment variable name
-	Value string // Environment variable value or secret URI
+	Index   int          // Position in the original file
+	Key     string       // Environment variable name
+	Value   string       // Environment variable value or secret URI
+	Source  string       // File the entry was read from (empty when parsed from raw content)
+	Column  int          // 1-based column of the secret URI in the env file line, 0 when unknown
+	Options EntryOptions // Per-entry options (available in structured input formats)
+}
+
+// EntryOptions holds per-entry options that cannot be expressed in the flat .env syntax
+type EntryOptions struct {
+	Required    bool   // The variable must resolve to a non-empty value
+	Default     string // Value used when the entry resolves to an empty value
+	Description string // Human readable description of the variable
+	Pattern     string // Regular expression the resolved value must match
+	MinLength   int    // Minimum length of the resolved value
+	Type        string // Expected value type (string, int, port, url)
+}
+
+// HasValidation checks if the options declare rules for the resolved value
+func (o EntryOptions) HasValidation() bool {
+	return o.Pattern != "" || o.MinLength > 0 || o.Type != ""
 }
 
 // NewEntry creates a new Entry with the specified values
@@ -22,29 +44,57 @@ func NewEntry(index int, key, value string) Entry {
 
 // WithIndex returns a new Entry with the specified index
 func (e Entry) WithIndex(index int) Entry {
-	return Entry{
-		Index: index,
-		Key:   e.Key,
-		Value: e.Value,
-	}
+	result := e
+	result.Index = index
+	return result
 }
 
 // WithKey returns a new Entry with the specified key
 func (e Entry) WithKey(key string) Entry {
-	return Entry{
-		Index: e.Index,
-		Key:   key,
-		Value: e.Value,
-	}
+	result := e
+	result.Key = key
+	return result
 }
 
 // WithValue returns a new Entry with the specified value
 func (e Entry) WithValue(value string) Entry {
-	return Entry{
-		Index: e.Index,
-		Key:   e.Key,
-		Value: value,
+	result := e
+	result.Value = value
+	return result
+}
+
+// WithSource returns a new Entry with the specified source file
+func (e Entry) WithSource(source string) Entry {
+	result := e
+	result.Source = source
+	return result
+}
+
+// WithColumn returns a new Entry with the specified secret URI column
+func (e Entry) WithColumn(column int) Entry {
+	result := e
+	result.Column = column
+	return result
+}
+
+// WithOptions returns a new Entry with the specified options
+func (e Entry) WithOptions(options EntryOptions) Entry {
+	result := e
+	result.Options = options
+	return result
+}
+
+// IsRequired checks if the entry is marked as required
+func (e Entry) IsRequired() bool {
+	return e.Options.Required
+}
+
+// Location returns the "file:line" position of the entry, or "line N" when the source is unknown
+func (e Entry) Location() string {
+	if e.Source == "" {
+		return fmt.Sprintf("line %d", e.Index)
 	}
+	return fmt.Sprintf("%s:%d", e.Source, e.Index)
 }
 
 // IsEmpty checks if both key and value are empty
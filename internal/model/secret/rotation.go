@@ -0,0 +1,35 @@
+package secret
+
+import "time"
+
+// Rotation status values reported by Rotation.Status
+const (
+	RotationDisabled = "disabled" // Rotation is not configured
+	RotationOK       = "ok"       // Rotation is configured and on schedule
+	RotationOverdue  = "overdue"  // The scheduled rotation date has passed
+	RotationPending  = "pending"  // A pending version exists (rotation in progress or failed)
+)
+
+// Rotation describes the rotation configuration and history of a secret
+type Rotation struct {
+	Enabled        bool      // Automatic rotation is configured
+	Rotator        string    // Rotation Lambda ARN (AWS) or notification topics (Google Cloud)
+	Schedule       string    // Rotation schedule (e.g. "every 30 days" or a schedule expression)
+	LastRotatedAt  time.Time // Time of the last successful rotation (zero if unknown)
+	NextRotationAt time.Time // Time of the next scheduled rotation (zero if unknown)
+	PendingVersion string    // Version carrying the AWSPENDING label (AWS only)
+}
+
+// Status summarizes the rotation state at the given time
+func (r Rotation) Status(now time.Time) string {
+	switch {
+	case r.PendingVersion != "":
+		return RotationPending
+	case !r.Enabled:
+		return RotationDisabled
+	case !r.NextRotationAt.IsZero() && r.NextRotationAt.Before(now):
+		return RotationOverdue
+	default:
+		return RotationOK
+	}
+}
@@ -0,0 +1,38 @@
+package secret
+
+import "time"
+
+// Version describes a version of a secret
+type Version struct {
+	ID        string    // Version ID (AWS) or version number (Google Cloud)
+	Stages    []string  // Staging labels (AWSCURRENT, AWSPREVIOUS, ...) or Google Cloud aliases (latest, ...)
+	CreatedAt time.Time // Creation time of the version
+	State     string    // State of the version (Google Cloud only: ENABLED, DISABLED, DESTROYED)
+}
+
+// IsAccessible checks if the value of the version can be read.
+// AWS versions have no state; Google Cloud versions must be ENABLED.
+func (v Version) IsAccessible() bool {
+	return v.State == "" || v.State == "ENABLED"
+}
+
+// HasStage checks if the version carries the given staging label or alias
+func (v Version) HasStage(stage string) bool {
+	for _, s := range v.Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if a version requested in a URI (an ID or a staging label) refers to this version
+func (v Version) Matches(requested string) bool {
+	return v.ID == requested || v.HasStage(requested)
+}
+
+// Written describes the version created by writing a secret
+type Written struct {
+	VersionID string // Version ID (AWS) or version number (Google Cloud) of the new version
+	Created   bool   // The secret did not exist and was created
+}
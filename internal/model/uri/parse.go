@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 )
 
@@ -41,9 +42,14 @@ type parsedPath struct {
 
 // ParsedQuery represents the components extracted from the query part of a URI
 type parsedQuery struct {
-	Version string
-	Region  string
-	Key     string
+	Version     string
+	Region      string
+	Key         string
+	Expand      string
+	Prefix      string
+	Rename      string
+	Endpoint    string
+	Credentials string
 }
 
 // splitPathAndQuery separates the path and query parts of a URI.
@@ -87,12 +93,27 @@ func parseQueryParams(query string) functional.Result[parsedQuery] {
 	}
 
 	return functional.Success(parsedQuery{
-		Version: q.Get("version"),
-		Region:  q.Get("region"),
-		Key:     q.Get("key"),
+		Version:     q.Get("version"),
+		Region:      q.Get("region"),
+		Key:         q.Get("key"),
+		Expand:      q.Get("expand"),
+		Prefix:      q.Get("prefix"),
+		Rename:      firstNonEmpty(q.Get("map"), q.Get("rename")),
+		Endpoint:    q.Get("endpoint"),
+		Credentials: q.Get("credentials"),
 	})
 }
 
+// firstNonEmpty returns the first non-empty value (used for parameter aliases)
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // determineVersion sets the version based on platform or query parameter.
 func determineVersion(platform, queryVersion string) string {
 	if queryVersion != "" {
@@ -141,6 +162,22 @@ func createSecretURI(path parsedPath, query parsedQuery) SecretURI {
 		secretURI = secretURI.WithKey(query.Key)
 	}
 
+	if query.Expand != "" {
+		secretURI = secretURI.WithExpand(query.Expand)
+	}
+
+	if query.Prefix != "" {
+		secretURI = secretURI.WithPrefix(query.Prefix)
+	}
+
+	if query.Rename != "" {
+		secretURI = secretURI.WithRename(query.Rename)
+	}
+
+	if query.Endpoint != "" || query.Credentials != "" {
+		secretURI = secretURI.WithEndpointSettings(endpoint.Settings{Endpoint: query.Endpoint, Credentials: query.Credentials})
+	}
+
 	return secretURI
 }
 
@@ -161,10 +198,13 @@ func splitAndProcess(trimmed string) functional.Result[SecretURI] {
 
 // combinePathAndQuery combines path and query parsing results into a SecretURI
 func combinePathAndQuery(path parsedPath, query string) functional.Result[SecretURI] {
-	return functional.MapResultTo(
+	return functional.Chain(
 		parseQueryParams(query),
-		func(parsedQuery parsedQuery) SecretURI {
-			return createSecretURI(path, parsedQuery)
+		func(parsedQuery parsedQuery) functional.Result[SecretURI] {
+			if err := endpoint.ValidateURIEndpointResult(path.Platform, parsedQuery.Endpoint).GetError(); err != nil {
+				return functional.Failure[SecretURI](err)
+			}
+			return functional.Success(createSecretURI(path, parsedQuery))
 		},
 	)
 }
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 )
 
@@ -16,13 +17,18 @@ const (
 
 // SecretURI represents a parsed secret URI
 type SecretURI struct {
-	Platform   string // Cloud platform (aws, googlecloud)
-	Service    string // Service type (secretsmanager, secretmanager)
-	Account    string // Account identifier (e.g., AWS profile, Google Cloud project ID)
-	SecretName string // Name of the secret
-	Key        string // Optional key name for JSON secrets
-	Version    string // Version of the secret
-	Region     string // Region (mainly for AWS)
+	Platform    string // Cloud platform (aws, googlecloud)
+	Service     string // Service type (secretsmanager, secretmanager)
+	Account     string // Account identifier (e.g., AWS profile, Google Cloud project ID)
+	SecretName  string // Name of the secret
+	Key         string // Optional key name for JSON secrets
+	Version     string // Version of the secret
+	Region      string // Region (mainly for AWS)
+	Expand      string // Optional JSON expansion spec (e.g. "depth:1,case:upper")
+	Prefix      string // Optional prefix added to every variable name
+	Rename      string // Optional rename spec (e.g. "username:DB_USER,password:DB_PASS")
+	Endpoint    string // Optional API endpoint overriding the configured one
+	Credentials string // Optional credential source overriding the configured one (see package endpoint)
 }
 
 // Methods for SecretURI type
@@ -48,6 +54,45 @@ func (s SecretURI) WithKey(key string) SecretURI {
 	return result
 }
 
+// WithExpand returns a copy of the SecretURI with the specified JSON expansion spec
+func (s SecretURI) WithExpand(expand string) SecretURI {
+	result := s
+	result.Expand = expand
+	return result
+}
+
+// WithPrefix returns a copy of the SecretURI with the specified variable name prefix
+func (s SecretURI) WithPrefix(prefix string) SecretURI {
+	result := s
+	result.Prefix = prefix
+	return result
+}
+
+// WithRename returns a copy of the SecretURI with the specified rename spec
+func (s SecretURI) WithRename(rename string) SecretURI {
+	result := s
+	result.Rename = rename
+	return result
+}
+
+// WithEndpointSettings returns a copy of the SecretURI with the specified endpoint and credential source
+func (s SecretURI) WithEndpointSettings(settings endpoint.Settings) SecretURI {
+	result := s
+	result.Endpoint = settings.Endpoint
+	result.Credentials = settings.Credentials
+	return result
+}
+
+// EndpointSettings returns the endpoint settings given by the URI itself
+func (s SecretURI) EndpointSettings() endpoint.Settings {
+	return endpoint.Settings{Endpoint: s.Endpoint, Credentials: s.Credentials}
+}
+
+// ResolveEndpoint returns the endpoint settings of the secret, the URI parameters taking priority over the resolver
+func (s SecretURI) ResolveEndpoint(resolver endpoint.Resolver) endpoint.Settings {
+	return resolver.Resolve(s.Platform, s.Account, s.Region, s.EndpointSettings())
+}
+
 // IsComplete checks if the URI has all required fields
 func (s SecretURI) IsComplete() bool {
 	return s.Platform != "" && s.Service != "" &&
@@ -59,6 +104,15 @@ func (s SecretURI) GetCacheKey() string {
 	return BuildCacheKey(s.Account, s.Service, s.SecretName, s.Version, s.Region)
 }
 
+// Reference returns the URI of the secret version without the key and expansion options.
+// Entries with the same reference fetch the same value from the provider.
+func (s SecretURI) Reference() string {
+	return NewSecretURI(s.Platform, s.Service, s.Account, s.SecretName).
+		WithVersion(s.Version).
+		WithRegion(s.Region).
+		GetUri()
+}
+
 // AsOption converts a SecretURI to an Option type
 func (s SecretURI) AsOption() functional.Option[SecretURI] {
 	if !s.IsComplete() {
@@ -118,6 +172,11 @@ func collectQueryParams(s SecretURI) []string {
 		{"version", s.Version},
 		{"key", s.Key},
 		{"region", s.Region},
+		{"expand", s.Expand},
+		{"prefix", s.Prefix},
+		{"map", s.Rename},
+		{"endpoint", s.Endpoint},
+		{"credentials", s.Credentials},
 	}
 
 	// Filter out empty values and map to parameter strings
@@ -0,0 +1,169 @@
+package uri
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/text"
+)
+
+// PlatformServices lists the services supported on each platform
+var PlatformServices = map[string][]string{
+	AwsPlatform:         {"secretsmanager"},
+	GoogleCloudPlatform: {"secretmanager"},
+}
+
+// platformAliases maps common names of a platform to its identifier
+var platformAliases = map[string]string{
+	"gcp":    GoogleCloudPlatform,
+	"gcloud": GoogleCloudPlatform,
+	"google": GoogleCloudPlatform,
+	"amazon": AwsPlatform,
+}
+
+// QueryParameters lists the query parameters understood in secret URIs
+var QueryParameters = []string{"version", "region", "key", "expand", "prefix", "map", "rename", "endpoint", "credentials"}
+
+// Formats of the account and region of each platform
+var (
+	awsProfilePattern         = regexp.MustCompile(`^[A-Za-z0-9_.+@-]+$`)
+	awsRegionPattern          = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+	googleCloudProjectPattern = regexp.MustCompile(`^(([a-z][a-z0-9.-]*:)?[a-z][a-z0-9-]{4,28}[a-z0-9]|[0-9]+)$`)
+	googleCloudRegionPattern  = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+)
+
+// ValidationError describes the first problem of a secret URI
+type ValidationError struct {
+	Offset     int    // Byte offset of the problem in the URI (0 is the start of "sem://")
+	Message    string // What is wrong
+	Suggestion string // Likely intended text, when known
+}
+
+// Error formats the problem with its suggestion
+func (e *ValidationError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("%s (did you mean %q?)", e.Message, e.Suggestion)
+	}
+	return e.Message
+}
+
+// ValidateResult parses a secret URI and checks that its platform, service, account,
+// region and query parameters are known to sem. Failures are *ValidationError.
+func ValidateResult(uriString string) functional.Result[SecretURI] {
+	trimmed := strings.TrimLeft(uriString, " \t")
+	leading := len(uriString) - len(trimmed)
+	cleaned := cleanURI(trimmed)
+
+	if !strings.HasPrefix(cleaned, URIPrefix) {
+		return invalid(leading, fmt.Sprintf("invalid URI: must start with '%s'", URIPrefix), "")
+	}
+	path, query := splitPathAndQuery(strings.TrimPrefix(cleaned, URIPrefix))
+	offset := leading + len(URIPrefix)
+
+	pathResult := parsePathComponents(path)
+	if pathResult.IsFailure() {
+		return invalid(offset, pathResult.GetError().Error(), "")
+	}
+	parsed := pathResult.Unwrap()
+
+	if err := validatePath(parsed, offset); err != nil {
+		return functional.Failure[SecretURI](err)
+	}
+	if err := validateQuery(parsed.Platform, query, offset+len(path)+1); err != nil {
+		return functional.Failure[SecretURI](err)
+	}
+	return combinePathAndQuery(parsed, query)
+}
+
+// validatePath checks the platform, service and account of a parsed URI path starting at offset
+func validatePath(path parsedPath, offset int) *ValidationError {
+	services, known := PlatformServices[path.Platform]
+	if !known {
+		suggestion := platformAliases[strings.ToLower(path.Platform)]
+		if suggestion == "" {
+			suggestion = text.Closest(path.Platform, []string{AwsPlatform, GoogleCloudPlatform}).UnwrapOr("")
+		}
+		return &ValidationError{Offset: offset, Message: fmt.Sprintf("unknown platform '%s'", path.Platform), Suggestion: suggestion}
+	}
+
+	serviceOffset := offset + len(path.Platform) + 1
+	if !functional.Contains(services, path.Service) {
+		return &ValidationError{
+			Offset:     serviceOffset,
+			Message:    fmt.Sprintf("unknown service '%s' for platform %s", path.Service, path.Platform),
+			Suggestion: text.Closest(path.Service, services).UnwrapOr(""),
+		}
+	}
+
+	accountOffset := serviceOffset + len(path.Service) + 1
+	switch path.Platform {
+	case AwsPlatform:
+		if !awsProfilePattern.MatchString(path.Account) {
+			return &ValidationError{Offset: accountOffset, Message: fmt.Sprintf("invalid AWS profile name '%s'", path.Account)}
+		}
+	case GoogleCloudPlatform:
+		if !googleCloudProjectPattern.MatchString(path.Account) {
+			return &ValidationError{Offset: accountOffset, Message: fmt.Sprintf(
+				"invalid Google Cloud project ID '%s' (6-30 lowercase letters, digits and hyphens, starting with a letter)", path.Account)}
+		}
+	}
+	return nil
+}
+
+// validateQuery checks the query parameters of a URI whose query starts at offset
+func validateQuery(platform, query string, offset int) *ValidationError {
+	if query == "" {
+		return nil
+	}
+
+	for _, param := range strings.Split(query, "&") {
+		name, value, _ := strings.Cut(param, "=")
+		if name != "" && !functional.Contains(QueryParameters, name) {
+			return &ValidationError{
+				Offset:     offset,
+				Message:    fmt.Sprintf("unknown query parameter '%s'", name),
+				Suggestion: text.Closest(name, QueryParameters).UnwrapOr(""),
+			}
+		}
+		switch name {
+		case "region":
+			if err := validateRegion(platform, value, offset+len(name)+1); err != nil {
+				return err
+			}
+		case "endpoint":
+			if err := endpoint.ValidateURIEndpointResult(platform, value).GetError(); err != nil {
+				return &ValidationError{Offset: offset + len(name) + 1, Message: err.Error()}
+			}
+		case "credentials":
+			if err := endpoint.ValidateCredentialsResult(platform, value).GetError(); err != nil {
+				return &ValidationError{
+					Offset:     offset + len(name) + 1,
+					Message:    err.Error(),
+					Suggestion: text.Closest(value, endpoint.SupportedCredentials(platform)).UnwrapOr(""),
+				}
+			}
+		}
+		offset += len(param) + 1
+	}
+	return nil
+}
+
+// validateRegion checks the format of a region of the platform
+func validateRegion(platform, region string, offset int) *ValidationError {
+	pattern, example := awsRegionPattern, "ap-northeast-1"
+	if platform == GoogleCloudPlatform {
+		pattern, example = googleCloudRegionPattern, "asia-northeast1"
+	}
+	if !pattern.MatchString(region) {
+		return &ValidationError{Offset: offset, Message: fmt.Sprintf("invalid %s region '%s' (expected a region such as %s)", platform, region, example)}
+	}
+	return nil
+}
+
+// invalid returns a failed validation at offset
+func invalid(offset int, message, suggestion string) functional.Result[SecretURI] {
+	return functional.Failure[SecretURI](&ValidationError{Offset: offset, Message: message, Suggestion: suggestion})
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+)
+
+// LineEdit replaces the content of a line of an environment file
+type LineEdit struct {
+	Number int    // Line number (1-based)
+	Before string // Current content of the line
+	After  string // New content of the line
+}
+
+// ReplaceLineValue replaces everything after the first '=' of a key-value line,
+// keeping the indentation, any !override marker and the key as written
+func ReplaceLineValue(content, value string) string {
+	eqIndex := strings.Index(content, "=")
+	if eqIndex == -1 {
+		return content
+	}
+	return content[:eqIndex+1] + value
+}
+
+// ApplyLineEdits rewrites the edited lines of a file, keeping every other line and the line endings as they are.
+// Lines are numbered as PreprocessContent does, so a lone CR ends a line like LF and CRLF.
+func ApplyLineEdits(content []byte, edits []LineEdit) []byte {
+	replacements := make(map[int]string, len(edits))
+	for _, edit := range edits {
+		replacements[edit.Number] = edit.After
+	}
+
+	var result bytes.Buffer
+	for i, line := range splitRawLines(content) {
+		after, exists := replacements[i+1]
+		if !exists {
+			result.Write(line.content)
+			result.Write(line.ending)
+			continue
+		}
+
+		if i == 0 && bytes.HasPrefix(line.content, []byte{0xEF, 0xBB, 0xBF}) {
+			result.Write([]byte{0xEF, 0xBB, 0xBF})
+		}
+		result.WriteString(after)
+		result.Write(line.ending)
+	}
+	return result.Bytes()
+}
+
+// rawLine is a line of a file with the line ending it was written with
+type rawLine struct {
+	content []byte
+	ending  []byte // "\n", "\r\n", "\r", or empty for the last line
+}
+
+// splitRawLines splits content into lines ending with LF, CRLF or a lone CR
+func splitRawLines(content []byte) []rawLine {
+	lines := []rawLine{}
+	start := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\n':
+			lines = append(lines, rawLine{content: content[start:i], ending: content[i : i+1]})
+			start = i + 1
+		case '\r':
+			end := i + 1
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			lines = append(lines, rawLine{content: content[start:i], ending: content[i:end]})
+			start = end
+			i = end - 1
+		}
+	}
+	return append(lines, rawLine{content: content[start:]})
+}
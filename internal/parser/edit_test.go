@@ -0,0 +1,72 @@
+package parser
+
+import "testing"
+
+func TestReplaceLineValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		value    string
+		expected string
+	}{
+		{"Key-value line", "API_KEY=12345", "sem://x", "API_KEY=sem://x"},
+		{"Indented override", "  !override API_KEY = 'a=b'", "sem://x", "  !override API_KEY =sem://x"},
+		{"Line without equals sign", "API_KEY", "sem://x", "API_KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ReplaceLineValue(tt.content, tt.value); result != tt.expected {
+				t.Errorf("ReplaceLineValue() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyLineEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		edits    []LineEdit
+		expected string
+	}{
+		{
+			name:     "Edits keep other lines",
+			content:  "# comment\nA=1\nB=2\n",
+			edits:    []LineEdit{{Number: 2, Before: "A=1", After: "A=x"}},
+			expected: "# comment\nA=x\nB=2\n",
+		},
+		{
+			name:     "CRLF line endings are kept",
+			content:  "A=1\r\nB=2\r\n",
+			edits:    []LineEdit{{Number: 2, Before: "B=2", After: "B=x"}},
+			expected: "A=1\r\nB=x\r\n",
+		},
+		{
+			name:     "Lone CR line endings are counted as line breaks",
+			content:  "# comment\rA=1\rB=2\r\nC=3\n",
+			edits:    []LineEdit{{Number: 3, Before: "B=2", After: "B=x"}, {Number: 4, Before: "C=3", After: "C=x"}},
+			expected: "# comment\rA=1\rB=x\r\nC=x\n",
+		},
+		{
+			name:     "BOM is kept",
+			content:  "\xEF\xBB\xBFA=1",
+			edits:    []LineEdit{{Number: 1, Before: "A=1", After: "A=x"}},
+			expected: "\xEF\xBB\xBFA=x",
+		},
+		{
+			name:     "No edits",
+			content:  "A=1",
+			edits:    nil,
+			expected: "A=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := string(ApplyLineEdits([]byte(tt.content), tt.edits)); result != tt.expected {
+				t.Errorf("ApplyLineEdits() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package parser provides utilities for parsing environment files
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+// FileReader reads the content of a file referenced by an include directive
+type FileReader func(path string) ([]byte, error)
+
+// includeResolver holds the state used while resolving include directives.
+//
+// Override rules:
+//   - Entries are evaluated in file order, with included entries placed at the position of the directive.
+//   - Redefining a key within the same file keeps the existing last-wins behavior.
+//   - Redefining a key that was defined in another file is an error unless the line is marked !override,
+//     in which case the earlier definition is removed.
+type includeResolver struct {
+	read    FileReader
+	stack   []string       // Normalized paths of the files currently being parsed (for cycle detection)
+	entries []env.Entry    // Collected entries in evaluation order
+	defined map[string]int // Key -> index in entries of its current definition
+	removed map[int]bool   // Indexes of entries replaced by !override
+}
+
+// ParsePlainFileWithIncludesResult parses an env file and recursively resolves
+// #include and @import directives. Include paths are relative to the including file.
+// Errors are reported as "file:line" followed by the chain of include directives.
+func ParsePlainFileWithIncludesResult(path string, content []byte, read FileReader) functional.Result[[]env.Entry] {
+	resolver := &includeResolver{
+		read:    read,
+		defined: make(map[string]int),
+		removed: make(map[int]bool),
+	}
+
+	if err := resolver.parseFile(path, content, nil); err != nil {
+		return functional.Failure[[]env.Entry](err)
+	}
+
+	return functional.Success(resolver.result())
+}
+
+// parseFile parses a single file, recursing into include directives.
+// trace holds the "file:line" positions of the directives that led to this file, outermost first.
+func (r *includeResolver) parseFile(path string, content []byte, trace []string) error {
+	normalized := normalizeIncludePath(path)
+	for _, parent := range r.stack {
+		if parent == normalized {
+			return fmt.Errorf("include cycle detected: '%s' is already being included%s", path, formatIncludeTrace(trace))
+		}
+	}
+
+	r.stack = append(r.stack, normalized)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	linesResult := NewContentLinesResult(PreprocessContent(content))
+	if linesResult.IsFailure() {
+		return fmt.Errorf("%s: %w%s", path, linesResult.GetError(), formatIncludeTrace(trace))
+	}
+
+	for _, line := range linesResult.Unwrap() {
+		position := fmt.Sprintf("%s:%d", path, line.Number)
+
+		if line.IsInclude() {
+			if err := r.includeFile(path, line, extendIncludeTrace(trace, position)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		entryResult := line.ToEnvEntry()
+		if entryResult.IsFailure() {
+			return fmt.Errorf("%s: %w%s", position, entryResult.GetError(), formatIncludeTrace(trace))
+		}
+
+		entry := entryResult.Unwrap()
+		if !IsValidEntry(entry) {
+			continue
+		}
+
+		if err := r.addEntry(entry.WithSource(path), line.Override); err != nil {
+			return fmt.Errorf("%s: %w%s", position, err, formatIncludeTrace(trace))
+		}
+	}
+
+	return nil
+}
+
+// includeFile reads and parses the file referenced by an include directive.
+// Relative paths are resolved from the directory of the including file, absolute paths are used as-is.
+func (r *includeResolver) includeFile(parentPath string, line Line, trace []string) error {
+	includePath := line.IncludePath()
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(filepath.Dir(parentPath), includePath)
+	}
+
+	content, err := r.read(includePath)
+	if err != nil {
+		return fmt.Errorf("%s: failed to include '%s': %w%s",
+			trace[len(trace)-1], line.IncludePath(), err, formatIncludeTrace(trace[:len(trace)-1]))
+	}
+
+	return r.parseFile(includePath, content, trace)
+}
+
+// addEntry appends an entry, applying the override rules for duplicate keys
+func (r *includeResolver) addEntry(entry env.Entry, override bool) error {
+	// Bare secret URI lines have no variable name to collide on
+	if strings.HasPrefix(entry.Key, "sem://") {
+		r.entries = append(r.entries, entry)
+		return nil
+	}
+
+	if previousIdx, exists := r.defined[entry.Key]; exists {
+		previous := r.entries[previousIdx]
+		switch {
+		case override:
+			r.removed[previousIdx] = true
+		case previous.Source != entry.Source:
+			return fmt.Errorf("duplicate key '%s' already defined at %s (mark the line with %s to replace it)",
+				entry.Key, previous.Location(), OverrideDirective)
+		}
+	}
+
+	r.defined[entry.Key] = len(r.entries)
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// result returns the collected entries without the ones replaced by !override
+func (r *includeResolver) result() []env.Entry {
+	entries := make([]env.Entry, 0, len(r.entries))
+	for i, entry := range r.entries {
+		if !r.removed[i] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// normalizeIncludePath returns a canonical form of a path for cycle detection
+func normalizeIncludePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// extendIncludeTrace returns a copy of the trace with an additional include position
+func extendIncludeTrace(trace []string, position string) []string {
+	result := make([]string, len(trace), len(trace)+1)
+	copy(result, trace)
+	return append(result, position)
+}
+
+// formatIncludeTrace formats the chain of include directives, innermost first
+func formatIncludeTrace(trace []string) string {
+	if len(trace) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(trace))
+	for i := len(trace) - 1; i >= 0; i-- {
+		parts = append(parts, "included from "+trace[i])
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
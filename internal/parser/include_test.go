@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+// memoryReader returns a FileReader backed by an in-memory map of files
+func memoryReader(files map[string]string) FileReader {
+	return func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("file not found: %s", path)
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestIncludeLineClassification(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedType LineType
+		expectedPath string
+		override     bool
+	}{
+		{"Include directive", "#include base.env", IncludeLine, "base.env", false},
+		{"Import directive", "@import shared/base.env", IncludeLine, "shared/base.env", false},
+		{"Include directive with a tab", "#include\tbase.env", IncludeLine, "base.env", false},
+		{"Import directive with a tab", "@import\t shared/base.env", IncludeLine, "shared/base.env", false},
+		{"Include without path is a comment", "#include", CommentLine, "", false},
+		{"Comment starting with include word", "#included values below", CommentLine, "", false},
+		{"Override key-value", "!override KEY=value", KeyValueLine, "", true},
+		{"Override secret URI", "!override KEY=sem://aws:secretsmanager/p/s", KeyValueLine, "", true},
+		{"Override word as key", "!overrideKEY=value", KeyValueLine, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := NewLine(tt.content, 1)
+			if line.Type != tt.expectedType {
+				t.Errorf("NewLine(%q).Type = %v, want %v", tt.content, line.Type, tt.expectedType)
+			}
+			if line.IncludePath() != tt.expectedPath {
+				t.Errorf("NewLine(%q).IncludePath() = %q, want %q", tt.content, line.IncludePath(), tt.expectedPath)
+			}
+			if line.Override != tt.override {
+				t.Errorf("NewLine(%q).Override = %v, want %v", tt.content, line.Override, tt.override)
+			}
+		})
+	}
+}
+
+func TestParsePlainFileWithIncludesResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		root        string
+		expected    []env.Entry
+		errContains []string
+	}{
+		{
+			name: "Included entries are placed at the directive",
+			files: map[string]string{
+				"staging.env": "FIRST=1\n#include base.env\nLAST=3",
+				"base.env":    "BASE=2",
+			},
+			root: "staging.env",
+			expected: []env.Entry{
+				{Index: 1, Key: "FIRST", Value: "1", Source: "staging.env"},
+				{Index: 1, Key: "BASE", Value: "2", Source: "base.env"},
+				{Index: 3, Key: "LAST", Value: "3", Source: "staging.env"},
+			},
+		},
+		{
+			name: "Include paths are relative to the including file",
+			files: map[string]string{
+				"envs/staging.env":     "@import common/base.env",
+				"envs/common/base.env": "BASE=2",
+			},
+			root: "envs/staging.env",
+			expected: []env.Entry{
+				{Index: 1, Key: "BASE", Value: "2", Source: "envs/common/base.env"},
+			},
+		},
+		{
+			name: "Absolute include paths are used as-is",
+			files: map[string]string{
+				"envs/staging.env":     "#include /shared/base.env",
+				"/shared/base.env":     "BASE=2",
+				"envs/shared/base.env": "BASE=wrong",
+			},
+			root: "envs/staging.env",
+			expected: []env.Entry{
+				{Index: 1, Key: "BASE", Value: "2", Source: "/shared/base.env"},
+			},
+		},
+		{
+			name: "Override replaces an included key",
+			files: map[string]string{
+				"staging.env": "#include base.env\n!override DB_HOST=staging-db",
+				"base.env":    "DB_HOST=localhost\nDB_PORT=5432",
+			},
+			root: "staging.env",
+			expected: []env.Entry{
+				{Index: 2, Key: "DB_PORT", Value: "5432", Source: "base.env"},
+				{Index: 2, Key: "DB_HOST", Value: "staging-db", Source: "staging.env"},
+			},
+		},
+		{
+			name: "Duplicates within the same file keep last-wins",
+			files: map[string]string{
+				"staging.env": "KEY=1\nKEY=2",
+			},
+			root: "staging.env",
+			expected: []env.Entry{
+				{Index: 1, Key: "KEY", Value: "1", Source: "staging.env"},
+				{Index: 2, Key: "KEY", Value: "2", Source: "staging.env"},
+			},
+		},
+		{
+			name: "Duplicate across files without override is an error",
+			files: map[string]string{
+				"staging.env": "#include base.env\nDB_HOST=staging-db",
+				"base.env":    "DB_HOST=localhost",
+			},
+			root:        "staging.env",
+			errContains: []string{"staging.env:2", "duplicate key 'DB_HOST'", "base.env:1", "!override"},
+		},
+		{
+			name: "Include cycle is detected",
+			files: map[string]string{
+				"a.env": "#include b.env",
+				"b.env": "#include a.env",
+			},
+			root:        "a.env",
+			errContains: []string{"include cycle", "included from b.env:1", "included from a.env:1"},
+		},
+		{
+			name: "Errors in included files report the include trace",
+			files: map[string]string{
+				"staging.env": "OK=1\n#include base.env",
+				"base.env":    "#include missing.env",
+			},
+			root:        "staging.env",
+			errContains: []string{"base.env:1: failed to include 'missing.env'", "(included from staging.env:2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := memoryReader(tt.files)
+			result := ParsePlainFileWithIncludesResult(tt.root, []byte(tt.files[tt.root]), read)
+
+			if len(tt.errContains) > 0 {
+				if result.IsSuccess() {
+					t.Fatalf("expected error, got entries %+v", result.Unwrap())
+				}
+				for _, part := range tt.errContains {
+					if !strings.Contains(result.GetError().Error(), part) {
+						t.Errorf("error %q does not contain %q", result.GetError().Error(), part)
+					}
+				}
+				return
+			}
+
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("entries = %+v, want %+v", result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
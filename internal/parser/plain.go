@@ -20,14 +20,23 @@ const (
 	SecretURILine
 	KeyValueLine
 	KeyOnlyLine
+	IncludeLine
+)
+
+// Directives recognized by the parser
+const (
+	IncludeDirective  = "#include"  // Includes another env file (e.g. "#include base.env")
+	ImportDirective   = "@import"   // Alias of #include (e.g. "@import base.env")
+	OverrideDirective = "!override" // Marks an entry that replaces a key defined in an included file
 )
 
 // Line represents a single line from a file with its properties
 type Line struct {
-	Content string
-	Number  int
-	Type    LineType
-	Trimmed string
+	Content  string
+	Number   int
+	Type     LineType
+	Trimmed  string
+	Override bool // Whether the line is marked with !override
 }
 
 // IsEmpty returns whether the line is empty
@@ -55,6 +64,21 @@ func (l Line) IsKeyOnly() bool {
 	return l.Type == KeyOnlyLine
 }
 
+// IsInclude returns whether the line is an include directive
+func (l Line) IsInclude() bool {
+	return l.Type == IncludeLine
+}
+
+// IncludePath returns the file referenced by an include directive
+func (l Line) IncludePath() string {
+	if !l.IsInclude() {
+		return ""
+	}
+	// The directive is followed by a space or a tab; the path itself may contain spaces
+	separator := strings.IndexAny(l.Trimmed, " \t")
+	return strings.TrimSpace(l.Trimmed[separator+1:])
+}
+
 // IsValid returns whether the line represents a valid entry
 func (l Line) IsValid() bool {
 	return l.Type == SecretURILine || l.Type == KeyValueLine || l.Type == KeyOnlyLine
@@ -63,23 +87,49 @@ func (l Line) IsValid() bool {
 // ToEnvEntry converts a Line to an EnvEntry
 func (l Line) ToEnvEntry() functional.Result[env.Entry] {
 	switch l.Type {
-	case EmptyLine, CommentLine:
+	case EmptyLine, CommentLine, IncludeLine:
 		return functional.Success(env.Entry{})
 
 	case SecretURILine:
-		return functional.Success(env.NewEntry(l.Number, l.Trimmed, ""))
+		return functional.Success(env.NewEntry(l.Number, l.Trimmed, "").WithColumn(l.uriColumn()))
 
 	case KeyValueLine:
-		return parseKeyValueLine(l)
+		return parseKeyValueLine(l).MapResult(func(entry env.Entry) env.Entry {
+			return entry.WithColumn(l.uriColumn())
+		})
 
 	case KeyOnlyLine:
-		return functional.Success(env.NewEntry(l.Number, l.Trimmed, ""))
+		// A bare KEY declares a variable that must come from the environment or a default
+		return functional.Success(env.NewEntry(l.Number, l.Trimmed, "").
+			WithOptions(env.EntryOptions{Required: true}))
 	}
 
 	// This should never happen due to exhaustive handling
 	return functional.Success(env.Entry{})
 }
 
+// uriColumn returns the 1-based column where the secret URI of the line starts in its raw content,
+// skipping indentation, the !override directive, spaces around '=' and an opening quote.
+// It returns 0 when the line has no secret URI.
+func (l Line) uriColumn() int {
+	start := 0
+	if l.Type == KeyValueLine {
+		start = strings.Index(l.Content, "=") + 1
+	}
+	index := strings.Index(l.Content[start:], "sem://")
+	if index == -1 {
+		return 0
+	}
+	prefix := strings.TrimSpace(l.Content[start : start+index])
+	if l.Type == SecretURILine {
+		prefix, _ = stripOverride(prefix)
+	}
+	if strings.Trim(prefix, `"'`) != "" {
+		return 0
+	}
+	return start + index + 1
+}
+
 // ContentLines represents the lines of content with their properties
 type ContentLines struct {
 	Lines []Line
@@ -90,12 +140,13 @@ type ContentLines struct {
 
 // NewLine creates a new Line instance
 func NewLine(content string, number int) Line {
-	trimmed := strings.TrimSpace(content)
+	trimmed, override := stripOverride(strings.TrimSpace(content))
 	return Line{
-		Content: content,
-		Number:  number,
-		Type:    classifyLine(trimmed),
-		Trimmed: trimmed,
+		Content:  content,
+		Number:   number,
+		Type:     classifyLine(trimmed),
+		Trimmed:  trimmed,
+		Override: override,
 	}
 }
 
@@ -139,6 +190,10 @@ func classifyLine(line string) LineType {
 		return EmptyLine
 	}
 
+	if isIncludeDirective(line) {
+		return IncludeLine
+	}
+
 	if strings.HasPrefix(line, "#") {
 		return CommentLine
 	}
@@ -154,12 +209,32 @@ func classifyLine(line string) LineType {
 	return KeyOnlyLine
 }
 
+// isIncludeDirective checks if a trimmed line is an #include or @import directive
+func isIncludeDirective(line string) bool {
+	for _, directive := range []string{IncludeDirective, ImportDirective} {
+		rest, found := strings.CutPrefix(line, directive)
+		if found && len(rest) > 0 && (rest[0] == ' ' || rest[0] == '\t') && strings.TrimSpace(rest) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// stripOverride removes a leading !override marker from a trimmed line
+func stripOverride(line string) (string, bool) {
+	rest, found := strings.CutPrefix(line, OverrideDirective)
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return line, false
+	}
+	return strings.TrimSpace(rest), true
+}
+
 // parseKeyValueLine parses a line containing key=value format
 func parseKeyValueLine(line Line) functional.Result[env.Entry] {
 	eqIndex := strings.Index(line.Trimmed, "=")
 	if eqIndex == -1 {
 		return functional.Failure[env.Entry](
-			fmt.Errorf("invalid key-value line: %s", line.Content))
+			fmt.Errorf("invalid key-value line %d: missing '='", line.Number))
 	}
 
 	key := strings.TrimSpace(line.Trimmed[:eqIndex])
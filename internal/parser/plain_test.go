@@ -280,7 +280,7 @@ func TestLine_ToEnvEntry(t *testing.T) {
 				Type:    SecretURILine,
 				Trimmed: "sem://aws/secretsmanager/my-secret",
 			},
-			expected: functional.Success(env.NewEntry(3, "sem://aws/secretsmanager/my-secret", "")),
+			expected: functional.Success(env.NewEntry(3, "sem://aws/secretsmanager/my-secret", "").WithColumn(1)),
 			wantErr:  false,
 		},
 		{
@@ -302,7 +302,7 @@ func TestLine_ToEnvEntry(t *testing.T) {
 				Type:    KeyOnlyLine,
 				Trimmed: "DATABASE_URL",
 			},
-			expected: functional.Success(env.NewEntry(5, "DATABASE_URL", "")),
+			expected: functional.Success(env.NewEntry(5, "DATABASE_URL", "").WithOptions(env.EntryOptions{Required: true})),
 			wantErr:  false,
 		},
 	}
@@ -359,7 +359,7 @@ func TestParseEnvLine(t *testing.T) {
 			name:     "Secret URI line",
 			content:  "sem://aws/secretsmanager/my-secret",
 			lineNum:  3,
-			expected: env.NewEntry(3, "sem://aws/secretsmanager/my-secret", ""),
+			expected: env.NewEntry(3, "sem://aws/secretsmanager/my-secret", "").WithColumn(1),
 			wantErr:  false,
 		},
 		{
@@ -380,7 +380,7 @@ func TestParseEnvLine(t *testing.T) {
 			name:     "Key only line",
 			content:  "DATABASE_URL",
 			lineNum:  6,
-			expected: env.NewEntry(6, "DATABASE_URL", ""),
+			expected: env.NewEntry(6, "DATABASE_URL", "").WithOptions(env.EntryOptions{Required: true}),
 			wantErr:  false,
 		},
 		{
@@ -397,6 +397,27 @@ func TestParseEnvLine(t *testing.T) {
 			expected: env.NewEntry(8, "CONNECTION", "host=localhost port=5432"),
 			wantErr:  false,
 		},
+		{
+			name:     "Secret URI value with spaces and quotes",
+			content:  `  DB = "sem://aws:secretsmanager/default/db"`,
+			lineNum:  9,
+			expected: env.NewEntry(9, "DB", ` "sem://aws:secretsmanager/default/db"`).WithColumn(9),
+			wantErr:  false,
+		},
+		{
+			name:     "Override secret URI line",
+			content:  "\t!override sem://aws:secretsmanager/default/db",
+			lineNum:  10,
+			expected: env.NewEntry(10, "sem://aws:secretsmanager/default/db", "").WithColumn(12),
+			wantErr:  false,
+		},
+		{
+			name:     "Secret URI after other text is not located",
+			content:  "NOTE=see sem://aws:secretsmanager/default/db",
+			lineNum:  11,
+			expected: env.NewEntry(11, "NOTE", "see sem://aws:secretsmanager/default/db"),
+			wantErr:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -504,7 +525,7 @@ func TestParsePlainFileContentResult(t *testing.T) {
 			content: []byte("# Header\nKEY1=value1\n\nsem://aws/secret\nKEY2="),
 			expected: []env.Entry{
 				env.NewEntry(2, "KEY1", "value1"),
-				env.NewEntry(4, "sem://aws/secret", ""),
+				env.NewEntry(4, "sem://aws/secret", "").WithColumn(1),
 				env.NewEntry(5, "KEY2", ""),
 			},
 			wantErr: false,
@@ -0,0 +1,232 @@
+// Package parser provides utilities for parsing environment files
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"gopkg.in/yaml.v3"
+)
+
+// Option keys recognized in structured (YAML/JSON) entry specifications
+const (
+	ValueOption       = "value"
+	RequiredOption    = "required"
+	DefaultOption     = "default"
+	DescriptionOption = "description"
+	PatternOption     = "pattern"
+	MinLengthOption   = "min_length"
+	TypeOption        = "type"
+)
+
+// GroupSeparator joins nested group names into environment variable names (DB: {HOST: ...} -> DB_HOST)
+const GroupSeparator = "_"
+
+// entryOptionKeys lists the keys allowed in an entry specification
+var entryOptionKeys = map[string]bool{
+	ValueOption:       true,
+	RequiredOption:    true,
+	DefaultOption:     true,
+	DescriptionOption: true,
+	PatternOption:     true,
+	MinLengthOption:   true,
+	TypeOption:        true,
+}
+
+// ParseYAMLContentResult parses a YAML input file into environment entries.
+//
+// Each key maps to a secret URI or literal value. Nested mappings are flattened into
+// prefixed names, and a mapping containing only entry option keys
+// (value/required/default/description/pattern/min_length/type)
+// is treated as a single entry with options:
+//
+//	DB:
+//	  HOST: localhost
+//	  PASSWORD:
+//	    value: sem://aws:secretsmanager/profile/db?key=password
+//	    required: true
+//	    description: Database password
+func ParseYAMLContentResult(path string, content []byte) functional.Result[[]env.Entry] {
+	return parseStructuredResult(path, PreprocessContent(content))
+}
+
+// ParseJSONContentResult parses a JSON input file into environment entries.
+// The structure is the same as the YAML format.
+func ParseJSONContentResult(path string, content []byte) functional.Result[[]env.Entry] {
+	processed := PreprocessContent(content)
+	if !json.Valid(processed) {
+		var js interface{}
+		err := json.Unmarshal(processed, &js)
+		return functional.Failure[[]env.Entry](fmt.Errorf("%s: invalid JSON: %w", path, err))
+	}
+	return parseStructuredResult(path, processed)
+}
+
+// parseStructuredResult decodes the document into a node tree and flattens it into entries
+func parseStructuredResult(path string, content []byte) functional.Result[[]env.Entry] {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return functional.Failure[[]env.Entry](fmt.Errorf("%s: %w", path, err))
+	}
+
+	// Empty document
+	if len(document.Content) == 0 {
+		return functional.Success([]env.Entry{})
+	}
+
+	root := resolveAlias(document.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return functional.Failure[[]env.Entry](
+			fmt.Errorf("%s:%d: top level must be a mapping of variable names to values", path, root.Line))
+	}
+
+	flattener := &structuredFlattener{path: path, seen: make(map[string]int)}
+	if err := flattener.flattenMapping("", root); err != nil {
+		return functional.Failure[[]env.Entry](err)
+	}
+
+	return functional.Success(flattener.entries)
+}
+
+// structuredFlattener collects entries while walking the node tree
+type structuredFlattener struct {
+	path    string
+	entries []env.Entry
+	seen    map[string]int // Flattened key -> line of its first definition
+}
+
+// flattenMapping walks a mapping node, emitting entries with the given name prefix
+func (f *structuredFlattener) flattenMapping(prefix string, node *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := resolveAlias(node.Content[i+1])
+
+		// YAML merge keys (<<: *base) merge the referenced mapping into the current group
+		if keyNode.Tag == "!!merge" {
+			if valueNode.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s:%d: merge key must reference a mapping", f.path, keyNode.Line)
+			}
+			if err := f.flattenMapping(prefix, valueNode); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if keyNode.Value == "" {
+			return fmt.Errorf("%s:%d: variable name must not be empty", f.path, keyNode.Line)
+		}
+		key := prefix + keyNode.Value
+
+		switch {
+		case valueNode.Kind == yaml.ScalarNode:
+			if err := f.addEntry(keyNode.Line, env.NewEntry(keyNode.Line, key, scalarValue(valueNode))); err != nil {
+				return err
+			}
+
+		case valueNode.Kind == yaml.MappingNode && isEntrySpec(valueNode):
+			entry, err := f.parseEntrySpec(keyNode.Line, key, valueNode)
+			if err != nil {
+				return err
+			}
+			if err := f.addEntry(keyNode.Line, entry); err != nil {
+				return err
+			}
+
+		case valueNode.Kind == yaml.MappingNode:
+			if err := f.flattenMapping(key+GroupSeparator, valueNode); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("%s:%d: unsupported value for '%s': expected a string, a group or an entry specification",
+				f.path, valueNode.Line, key)
+		}
+	}
+
+	return nil
+}
+
+// addEntry appends an entry after checking that its flattened name is unique
+func (f *structuredFlattener) addEntry(line int, entry env.Entry) error {
+	if firstLine, exists := f.seen[entry.Key]; exists {
+		return fmt.Errorf("%s:%d: duplicate variable '%s' (first defined at line %d)",
+			f.path, line, entry.Key, firstLine)
+	}
+	f.seen[entry.Key] = line
+	f.entries = append(f.entries, entry.WithSource(f.path))
+	return nil
+}
+
+// parseEntrySpec converts an entry specification mapping into an entry with options
+func (f *structuredFlattener) parseEntrySpec(line int, key string, node *yaml.Node) (env.Entry, error) {
+	var value string
+	options := env.EntryOptions{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		optionNode := node.Content[i]
+		valueNode := resolveAlias(node.Content[i+1])
+
+		if valueNode.Kind != yaml.ScalarNode {
+			return env.Entry{}, fmt.Errorf("%s:%d: option '%s' of '%s' must be a scalar value",
+				f.path, valueNode.Line, optionNode.Value, key)
+		}
+
+		switch optionNode.Value {
+		case ValueOption:
+			value = scalarValue(valueNode)
+		case DefaultOption:
+			options.Default = scalarValue(valueNode)
+		case DescriptionOption:
+			options.Description = scalarValue(valueNode)
+		case RequiredOption:
+			if err := valueNode.Decode(&options.Required); err != nil {
+				return env.Entry{}, fmt.Errorf("%s:%d: option 'required' of '%s' must be true or false",
+					f.path, valueNode.Line, key)
+			}
+		case PatternOption:
+			options.Pattern = scalarValue(valueNode)
+		case MinLengthOption:
+			if err := valueNode.Decode(&options.MinLength); err != nil || options.MinLength < 0 {
+				return env.Entry{}, fmt.Errorf("%s:%d: option 'min_length' of '%s' must be a non-negative integer",
+					f.path, valueNode.Line, key)
+			}
+		case TypeOption:
+			options.Type = scalarValue(valueNode)
+		}
+	}
+
+	return env.NewEntry(line, key, value).WithOptions(options), nil
+}
+
+// isEntrySpec checks if a mapping node describes a single entry rather than a group
+func isEntrySpec(node *yaml.Node) bool {
+	hasDefiningKey := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !entryOptionKeys[key] {
+			return false
+		}
+		if key != DescriptionOption {
+			hasDefiningKey = true
+		}
+	}
+	return hasDefiningKey
+}
+
+// scalarValue returns the string form of a scalar node (null becomes an empty string)
+func scalarValue(node *yaml.Node) string {
+	if node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// resolveAlias follows YAML aliases to the node they reference
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
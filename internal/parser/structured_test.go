@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+func TestParseYAMLContentResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    []env.Entry
+		errContains []string
+	}{
+		{
+			name:    "Flat keys",
+			content: "API_KEY: sem://aws:secretsmanager/default/api\nLOG_LEVEL: debug\n",
+			expected: []env.Entry{
+				{Index: 1, Key: "API_KEY", Value: "sem://aws:secretsmanager/default/api", Source: "app.yaml"},
+				{Index: 2, Key: "LOG_LEVEL", Value: "debug", Source: "app.yaml"},
+			},
+		},
+		{
+			name:    "Nested groups flatten to prefixed names",
+			content: "DB:\n  HOST: localhost\n  READ:\n    PORT: 5432\n",
+			expected: []env.Entry{
+				{Index: 2, Key: "DB_HOST", Value: "localhost", Source: "app.yaml"},
+				{Index: 4, Key: "DB_READ_PORT", Value: "5432", Source: "app.yaml"},
+			},
+		},
+		{
+			name: "Entry options",
+			content: "DB:\n  PASSWORD:\n    value: sem://gcp:secretmanager/project/db\n    required: true\n" +
+				"    description: Database password\nPORT:\n  default: \"8080\"\n",
+			expected: []env.Entry{
+				{
+					Index: 2, Key: "DB_PASSWORD", Value: "sem://gcp:secretmanager/project/db", Source: "app.yaml",
+					Options: env.EntryOptions{Required: true, Description: "Database password"},
+				},
+				{Index: 6, Key: "PORT", Value: "", Source: "app.yaml", Options: env.EntryOptions{Default: "8080"}},
+			},
+		},
+		{
+			name:    "Validation options",
+			content: "API_URL:\n  value: sem://aws:secretsmanager/p/api\n  type: url\n  pattern: ^https\n  min_length: 12\n",
+			expected: []env.Entry{
+				{
+					Index: 1, Key: "API_URL", Value: "sem://aws:secretsmanager/p/api", Source: "app.yaml",
+					Options: env.EntryOptions{Type: "url", Pattern: "^https", MinLength: 12},
+				},
+			},
+		},
+		{
+			name:        "Invalid min_length option",
+			content:     "KEY:\n  value: x\n  min_length: -1\n",
+			errContains: []string{"app.yaml:3", "'min_length' of 'KEY'"},
+		},
+		{
+			name:    "Null value is empty",
+			content: "EMPTY:\n",
+			expected: []env.Entry{
+				{Index: 1, Key: "EMPTY", Value: "", Source: "app.yaml"},
+			},
+		},
+		{
+			name:    "Merge keys",
+			content: "base: &base\n  HOST: localhost\nAPP:\n  <<: *base\n  NAME: app\n",
+			expected: []env.Entry{
+				{Index: 2, Key: "base_HOST", Value: "localhost", Source: "app.yaml"},
+				{Index: 2, Key: "APP_HOST", Value: "localhost", Source: "app.yaml"},
+				{Index: 5, Key: "APP_NAME", Value: "app", Source: "app.yaml"},
+			},
+		},
+		{
+			name:        "Duplicate flattened name",
+			content:     "DB_HOST: a\nDB:\n  HOST: b\n",
+			errContains: []string{"app.yaml:3", "duplicate variable 'DB_HOST'", "line 1"},
+		},
+		{
+			name:        "Sequence values are rejected",
+			content:     "HOSTS:\n  - a\n  - b\n",
+			errContains: []string{"app.yaml:2", "unsupported value for 'HOSTS'"},
+		},
+		{
+			name:        "Invalid required option",
+			content:     "KEY:\n  value: x\n  required: maybe\n",
+			errContains: []string{"app.yaml:3", "'required' of 'KEY'"},
+		},
+		{
+			name:        "Top level must be a mapping",
+			content:     "- a\n- b\n",
+			errContains: []string{"top level must be a mapping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseYAMLContentResult("app.yaml", []byte(tt.content))
+
+			if len(tt.errContains) > 0 {
+				if result.IsSuccess() {
+					t.Fatalf("expected error, got entries %+v", result.Unwrap())
+				}
+				for _, part := range tt.errContains {
+					if !strings.Contains(result.GetError().Error(), part) {
+						t.Errorf("error %q does not contain %q", result.GetError().Error(), part)
+					}
+				}
+				return
+			}
+
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("entries = %+v, want %+v", result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseJSONContentResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expected  []env.Entry
+		expectErr bool
+	}{
+		{
+			name:    "Nested groups and options",
+			content: "{\n  \"DB\": {\n    \"HOST\": \"localhost\",\n    \"PASSWORD\": {\"value\": \"sem://aws:secretsmanager/p/db\", \"required\": true}\n  }\n}",
+			expected: []env.Entry{
+				{Index: 3, Key: "DB_HOST", Value: "localhost", Source: "app.json"},
+				{
+					Index: 4, Key: "DB_PASSWORD", Value: "sem://aws:secretsmanager/p/db", Source: "app.json",
+					Options: env.EntryOptions{Required: true},
+				},
+			},
+		},
+		{
+			name:      "Invalid JSON",
+			content:   "{\"KEY\": \"value\",}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseJSONContentResult("app.json", []byte(tt.content))
+
+			if result.IsFailure() != tt.expectErr {
+				t.Fatalf("failure = %v, want %v (error: %v)", result.IsFailure(), tt.expectErr, result.GetError())
+			}
+			if !tt.expectErr && !reflect.DeepEqual(result.Unwrap(), tt.expected) {
+				t.Errorf("entries = %+v, want %+v", result.Unwrap(), tt.expected)
+			}
+		})
+	}
+}
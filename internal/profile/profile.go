@@ -0,0 +1,172 @@
+// Package profile provides named environments (dev, staging, prod, ...) that map
+// to input files and their cache files.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// Constants for environment resolution
+const (
+	EnvFileExtension = ".env"     // Extension of environment input files
+	DefaultName      = "default"  // Name used for the plain .env input file
+	ActiveEnvFile    = ".sem-env" // File that records the active environment
+)
+
+// productionNames lists environment names that are always treated as production
+var productionNames = []string{"prod", "production", "prd"}
+
+// inputFileExtensions lists the input file extensions stripped when deriving an environment name
+var inputFileExtensions = []string{EnvFileExtension, ".yaml", ".yml", ".json"}
+
+// Environment represents a named environment and the files that belong to it
+type Environment struct {
+	Name       string // Environment name (e.g. "staging")
+	InputFile  string // Input file containing secret URIs
+	CacheFile  string // Cache file containing resolved values
+	Production bool   // Whether this environment requires explicit confirmation
+}
+
+// NewEnvironment creates an Environment for the given name and input file
+func NewEnvironment(name, inputFile string) Environment {
+	return Environment{
+		Name:       name,
+		InputFile:  inputFile,
+		CacheFile:  fileio.GenerateCacheFileName(inputFile),
+		Production: IsProduction(name),
+	}
+}
+
+// Resolve returns the environment for a name, using the first existing file among
+// "<name>.env", "<name>.yaml", "<name>.yml" and "<name>.json" ("<name>.env" when none exists)
+func Resolve(name string) Environment {
+	stem := name
+	if name == DefaultName {
+		stem = ""
+	}
+	for _, ext := range inputFileExtensions {
+		if _, err := os.Stat(stem + ext); err == nil {
+			return NewEnvironment(name, stem+ext)
+		}
+	}
+	return NewEnvironment(name, stem+EnvFileExtension)
+}
+
+// FromInputFile derives the environment from an input file name.
+// Pure function: Always returns the same output for the same input
+func FromInputFile(inputFile string) Environment {
+	return NewEnvironment(NameFromInputFile(inputFile), inputFile)
+}
+
+// NameFromInputFile extracts the environment name from an input file name
+// (e.g. "config/staging.env" -> "staging", "prod.yaml" -> "prod", ".env" -> "default")
+func NameFromInputFile(inputFile string) string {
+	name := filepath.Base(inputFile)
+	for _, ext := range inputFileExtensions {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	if name == "" || name == "." {
+		return DefaultName
+	}
+	return name
+}
+
+// IsInputFile reports whether a file name has the extension of an input file (.env, .yaml, .yml or .json)
+func IsInputFile(inputFile string) bool {
+	return functional.Any(inputFileExtensions, func(ext string) bool {
+		return strings.HasSuffix(inputFile, ext)
+	})
+}
+
+// IsProduction reports whether an environment name denotes production.
+// Names such as "prod", "production", "prod-eu" or "app_prod" are considered production.
+func IsProduction(name string) bool {
+	lowered := strings.ToLower(name)
+	for _, prod := range productionNames {
+		if lowered == prod ||
+			strings.HasPrefix(lowered, prod+"-") || strings.HasPrefix(lowered, prod+"_") ||
+			strings.HasSuffix(lowered, "-"+prod) || strings.HasSuffix(lowered, "_"+prod) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateName checks that an environment name can be mapped to a file name
+func ValidateName(name string) functional.Result[string] {
+	if name == "" {
+		return functional.Failure[string](fmt.Errorf("environment name must not be empty"))
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return functional.Failure[string](
+			fmt.Errorf("invalid environment name '%s': must not contain path separators or start with '.'", name))
+	}
+	return functional.Success(name)
+}
+
+// ReadActive reads the active environment name from the state file.
+// Returns None when no environment has been selected with 'sem use'.
+func ReadActive() functional.Result[functional.Option[string]] {
+	data, err := os.ReadFile(ActiveEnvFile)
+	if os.IsNotExist(err) {
+		return functional.Success(functional.None[string]())
+	}
+	if err != nil {
+		return functional.Failure[functional.Option[string]](
+			fmt.Errorf("failed to read active environment from '%s': %w", ActiveEnvFile, err))
+	}
+
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		return functional.Success(functional.None[string]())
+	}
+	return functional.Success(functional.Some(name))
+}
+
+// WriteActive records the active environment name in the state file
+func WriteActive(name string) functional.Result[bool] {
+	return fileio.WriteStringToFile(ActiveEnvFile, name+"\n")
+}
+
+// Discover lists environments whose input files (.env, .yaml, .yml or .json) exist in the given directory, sorted by name.
+// When several files share a name, the one chosen by Resolve is listed.
+func Discover(dir string) functional.Result[[]Environment] {
+	environments := []Environment{}
+	seen := map[string]bool{}
+	for _, ext := range inputFileExtensions {
+		matches, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			return functional.Failure[[]Environment](fmt.Errorf("failed to list environment files: %w", err))
+		}
+
+		for _, match := range matches {
+			base := filepath.Base(match)
+			// Skip cache files generated by update and other dotfiles such as .sem-endpoints.yaml,
+			// keeping the default input files (.env, .yaml, ...)
+			if strings.HasPrefix(base, ".") && base != ext {
+				continue
+			}
+			environment := FromInputFile(base)
+			if seen[environment.Name] {
+				continue
+			}
+			seen[environment.Name] = true
+			environments = append(environments, environment)
+		}
+	}
+
+	sort.Slice(environments, func(i, j int) bool {
+		return environments[i].Name < environments[j].Name
+	})
+	return functional.Success(environments)
+}
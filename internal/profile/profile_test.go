@@ -0,0 +1,173 @@
+package profile
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		envName  string
+		expected Environment
+	}{
+		{
+			name:    "Staging environment",
+			envName: "staging",
+			expected: Environment{
+				Name:       "staging",
+				InputFile:  "staging.env",
+				CacheFile:  ".cache.staging.env",
+				Production: false,
+			},
+		},
+		{
+			name:    "Production environment",
+			envName: "prod",
+			expected: Environment{
+				Name:       "prod",
+				InputFile:  "prod.env",
+				CacheFile:  ".cache.prod.env",
+				Production: true,
+			},
+		},
+		{
+			name:    "Default environment",
+			envName: DefaultName,
+			expected: Environment{
+				Name:       DefaultName,
+				InputFile:  ".env",
+				CacheFile:  ".cache.env",
+				Production: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Resolve(tt.envName)
+			if result != tt.expected {
+				t.Errorf("Resolve(%q) = %+v, want %+v", tt.envName, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveAndDiscoverStructuredFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, file := range []string{".env", "staging.yaml", "prod.json", "dev.env", "dev.json", ".sem-endpoints.yaml", ".cache.staging.yaml"} {
+		if err := os.WriteFile(file, []byte{}, 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	resolved := map[string]string{"staging": "staging.yaml", "prod": "prod.json", "dev": "dev.env", "qa": "qa.env", DefaultName: ".env"}
+	for name, want := range resolved {
+		if got := Resolve(name).InputFile; got != want {
+			t.Errorf("Resolve(%q).InputFile = %q, want %q", name, got, want)
+		}
+	}
+
+	result := Discover(".")
+	if result.IsFailure() {
+		t.Fatalf("Discover() error = %v", result.GetError())
+	}
+	got := []string{}
+	for _, environment := range result.Unwrap() {
+		got = append(got, environment.Name+":"+environment.InputFile)
+	}
+	want := []string{"default:.env", "dev:dev.env", "prod:prod.json", "staging:staging.yaml"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+}
+
+func TestNameFromInputFile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"staging.env", "staging"},
+		{"config/prod.env", "prod"},
+		{"staging.yaml", "staging"},
+		{"prod.json", "prod"},
+		{".env", DefaultName},
+		{"settings", "settings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := NameFromInputFile(tt.input); result != tt.expected {
+				t.Errorf("NameFromInputFile(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsProduction(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"prod", true},
+		{"Production", true},
+		{"prod-eu", true},
+		{"app_prod", true},
+		{"staging", false},
+		{"dev", false},
+		{"product", false},
+		{"preprod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsProduction(tt.name); result != tt.expected {
+				t.Errorf("IsProduction(%q) = %v, want %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name      string
+		envName   string
+		expectErr bool
+	}{
+		{"Valid name", "staging", false},
+		{"Empty name", "", true},
+		{"Path separator", "../prod", true},
+		{"Hidden name", ".secret", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateName(tt.envName)
+			if result.IsFailure() != tt.expectErr {
+				t.Errorf("ValidateName(%q) failure = %v, want %v", tt.envName, result.IsFailure(), tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{30 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := FormatAge(tt.age); result != tt.expected {
+				t.Errorf("FormatAge(%v) = %q, want %q", tt.age, result, tt.expected)
+			}
+		})
+	}
+}
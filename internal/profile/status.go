@@ -0,0 +1,89 @@
+// Package profile provides named environments (dev, staging, prod, ...) that map
+// to input files and their cache files.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// CacheState describes how up to date the cache file of an environment is
+type CacheState int
+
+const (
+	// CacheMissing means the cache file has not been generated yet
+	CacheMissing CacheState = iota
+	// CacheFresh means the cache file is newer than its input file
+	CacheFresh
+	// CacheStale means the input file was modified after the last update
+	CacheStale
+)
+
+// String returns a human readable description of the cache state
+func (s CacheState) String() string {
+	switch s {
+	case CacheFresh:
+		return "fresh"
+	case CacheStale:
+		return "stale (input file changed after last update)"
+	default:
+		return "missing (run 'sem update')"
+	}
+}
+
+// Status represents the state of an environment's files
+type Status struct {
+	Environment  Environment
+	InputExists  bool
+	CacheState   CacheState
+	CacheUpdated time.Time // Modification time of the cache file (zero if missing)
+}
+
+// Age returns how long ago the cache was updated relative to now
+func (s Status) Age(now time.Time) time.Duration {
+	if s.CacheState == CacheMissing {
+		return 0
+	}
+	return now.Sub(s.CacheUpdated)
+}
+
+// GetStatus inspects the input and cache files of an environment
+func GetStatus(environment Environment) Status {
+	status := Status{Environment: environment, CacheState: CacheMissing}
+
+	inputInfo, inputErr := os.Stat(environment.InputFile)
+	status.InputExists = inputErr == nil
+
+	cacheInfo, cacheErr := os.Stat(environment.CacheFile)
+	if cacheErr != nil {
+		return status
+	}
+
+	status.CacheUpdated = cacheInfo.ModTime()
+	status.CacheState = DetermineCacheState(inputInfo, inputErr == nil, cacheInfo.ModTime())
+	return status
+}
+
+// DetermineCacheState compares the input file modification time with the cache update time
+func DetermineCacheState(inputInfo os.FileInfo, inputExists bool, cacheUpdated time.Time) CacheState {
+	if inputExists && inputInfo.ModTime().After(cacheUpdated) {
+		return CacheStale
+	}
+	return CacheFresh
+}
+
+// FormatAge formats a duration as a short relative time (e.g. "5m ago", "3h ago", "2d ago")
+// Pure function: Always returns the same output for the same input
+func FormatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,141 @@
+// Package provenance records where each cached variable came from in a metadata file
+// stored next to the cache file. The metadata never contains secret values.
+package provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider"
+)
+
+// FileSuffix is appended to the cache file name to form the metadata file name
+const FileSuffix = ".meta.json"
+
+// Source identifies an input entry
+type Source struct {
+	Location string `json:"location"`      // Location of the entry in the input file (e.g. "staging.env:3")
+	URI      string `json:"uri,omitempty"` // Secret URI of the entry (empty for plain values)
+}
+
+// String formats the source as its location and URI
+func (s Source) String() string {
+	if s.URI == "" {
+		return s.Location
+	}
+	return fmt.Sprintf("%s (%s)", s.Location, s.URI)
+}
+
+// Record describes the provenance of a single variable
+type Record struct {
+	Key string `json:"key"`
+	Source
+	Version   string   `json:"version,omitempty"` // Version ID the secret resolved to
+	Path      string   `json:"path,omitempty"`    // JSON path of the value within the secret
+	Expanded  bool     `json:"expanded"`          // The name came from the secret rather than the entry
+	Overrides []Source `json:"overrides,omitempty"`
+}
+
+// Metadata is the content of the metadata file
+type Metadata struct {
+	Input     string    `json:"input"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Variables []Record  `json:"variables"`
+}
+
+// FileName returns the metadata file name for a cache file
+func FileName(cacheFileName string) string {
+	return cacheFileName + FileSuffix
+}
+
+// FromOrigins builds the metadata of the given keys from the origins recorded while resolving secrets
+func FromOrigins(input string, keys []string, origins map[string]provider.Origin, collisions []provider.Collision, updatedAt time.Time) Metadata {
+	records := make([]Record, 0, len(keys))
+	for _, key := range keys {
+		origin, exists := origins[key]
+		if !exists {
+			continue
+		}
+		records = append(records, NewRecord(key, origin, provider.CollisionsFor(collisions, key)))
+	}
+
+	return Metadata{
+		Input:     input,
+		UpdatedAt: updatedAt,
+		Variables: records,
+	}
+}
+
+// NewRecord creates the record of a key from its origin and the definitions it overwrote
+func NewRecord(key string, origin provider.Origin, collisions []provider.Collision) Record {
+	overrides := functional.Map(collisions, func(c provider.Collision) Source {
+		return Source{Location: c.Previous.Location, URI: c.Previous.URI}
+	})
+	if len(overrides) == 0 {
+		overrides = nil
+	}
+
+	return Record{
+		Key:       key,
+		Source:    Source{Location: origin.Location, URI: origin.URI},
+		Version:   origin.Version,
+		Path:      origin.Path,
+		Expanded:  origin.Expanded,
+		Overrides: overrides,
+	}
+}
+
+// Find returns the record of a key
+func (m Metadata) Find(key string) functional.Option[Record] {
+	return functional.Find(m.Variables, func(r Record) bool {
+		return r.Key == key
+	})
+}
+
+// ProducedKeys groups the recorded keys by the location of the entry that produced them
+func (m Metadata) ProducedKeys() map[string][]string {
+	produced := make(map[string][]string)
+	for _, r := range m.Variables {
+		produced[r.Location] = append(produced[r.Location], r.Key)
+	}
+	return produced
+}
+
+// IsSecret reports whether a key was resolved from a secret URI rather than copied from a plain value
+func (m Metadata) IsSecret(key string) bool {
+	return functional.Any(m.Variables, func(r Record) bool {
+		return r.Key == key && r.URI != ""
+	})
+}
+
+// WriteResult writes the metadata file with owner-only permissions
+func WriteResult(fileName string, metadata Metadata) functional.Result[bool] {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return functional.Failure[bool](fmt.Errorf("failed to encode metadata: %w", err))
+	}
+	return fileio.WriteStringToFile(fileName, string(data)+"\n")
+}
+
+// ReadResult reads a metadata file. A missing file is reported as None.
+func ReadResult(fileName string) functional.Result[functional.Option[Metadata]] {
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return functional.Success(functional.None[Metadata]())
+	}
+	if err != nil {
+		return functional.Failure[functional.Option[Metadata]](
+			fmt.Errorf("failed to read metadata file '%s': %w", fileName, err))
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return functional.Failure[functional.Option[Metadata]](
+			fmt.Errorf("invalid metadata file '%s': %w", fileName, err))
+	}
+	return functional.Success(functional.Some(metadata))
+}
@@ -0,0 +1,79 @@
+package provenance
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/provider"
+)
+
+func TestFromOrigins(t *testing.T) {
+	secretOrigin := provider.Origin{Location: ".env:2", URI: "sem://aws:secretsmanager/p/db", Version: "v1", Path: "password", Expanded: true}
+	origins := map[string]provider.Origin{
+		"DB_password": secretOrigin,
+		"PORT":        {Location: ".env:3"},
+	}
+	collisions := []provider.Collision{
+		{Key: "DB_password", Previous: provider.Origin{Location: ".env:1"}, Current: secretOrigin},
+	}
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	metadata := FromOrigins(".env", []string{"DB_password", "PORT", "FROM_ENV"}, origins, collisions, updatedAt)
+
+	expected := Metadata{
+		Input:     ".env",
+		UpdatedAt: updatedAt,
+		Variables: []Record{
+			{
+				Key:       "DB_password",
+				Source:    Source{Location: ".env:2", URI: "sem://aws:secretsmanager/p/db"},
+				Version:   "v1",
+				Path:      "password",
+				Expanded:  true,
+				Overrides: []Source{{Location: ".env:1"}},
+			},
+			{Key: "PORT", Source: Source{Location: ".env:3"}},
+		},
+	}
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("FromOrigins() = %+v, want %+v", metadata, expected)
+	}
+
+	if record := metadata.Find("PORT"); record.IsNone() || record.Unwrap().Location != ".env:3" {
+		t.Errorf("Find(PORT) = %+v", record)
+	}
+	if metadata.Find("FROM_ENV").IsSome() {
+		t.Errorf("Find(FROM_ENV) should be None for keys without an origin")
+	}
+}
+
+func TestWriteAndReadResult(t *testing.T) {
+	fileName := FileName(filepath.Join(t.TempDir(), ".cache.env"))
+	if filepath.Base(fileName) != ".cache.env.meta.json" {
+		t.Errorf("FileName() = %q", fileName)
+	}
+
+	missing := ReadResult(fileName)
+	if missing.IsFailure() || missing.Unwrap().IsSome() {
+		t.Fatalf("ReadResult() of a missing file = %+v, want None", missing)
+	}
+
+	metadata := Metadata{
+		Input:     ".env",
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Variables: []Record{{Key: "A", Source: Source{Location: ".env:1"}, Path: "a[0]"}},
+	}
+	if result := WriteResult(fileName, metadata); result.IsFailure() {
+		t.Fatalf("WriteResult() failed: %v", result.GetError())
+	}
+
+	result := ReadResult(fileName)
+	if result.IsFailure() || result.Unwrap().IsNone() {
+		t.Fatalf("ReadResult() = %+v", result)
+	}
+	if !reflect.DeepEqual(result.Unwrap().Unwrap(), metadata) {
+		t.Errorf("ReadResult() = %+v, want %+v", result.Unwrap().Unwrap(), metadata)
+	}
+}
@@ -4,36 +4,54 @@ package aws
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
 	"github.com/gumi-tsd/secret-env-manager/internal/secret"
 )
 
 // SecretRequest encapsulates all parameters needed to retrieve a secret
 type SecretRequest struct {
 	URI      uri.SecretURI
-	Endpoint string
+	Settings endpoint.Settings // Endpoint and credential source
 	Ctx      context.Context
 }
 
+// versionIDPattern matches version IDs (UUIDs), as opposed to staging labels such as AWSCURRENT
+var versionIDPattern = regexp.MustCompile(`^[0-9a-fA-F-]{32,64}$`)
+
+// IsVersionID reports whether a version is a version ID rather than a staging label
+func IsVersionID(version string) bool {
+	return versionIDPattern.MatchString(version)
+}
+
+// SecretVersion is a secret value together with the version ID it resolved to
+type SecretVersion struct {
+	Value     string
+	VersionID string
+}
+
 // NewSecretRequest creates a new SecretRequest with default context
 func NewSecretRequest(uri uri.SecretURI) SecretRequest {
 	return SecretRequest{
 		URI:      uri,
-		Endpoint: "",
+		Settings: endpoint.Settings{},
 		Ctx:      context.Background(),
 	}
 }
 
-// WithEndpoint returns a new SecretRequest with the specified endpoint
-func (r SecretRequest) WithEndpoint(endpoint string) SecretRequest {
+// WithSettings returns a new SecretRequest with the specified endpoint settings
+func (r SecretRequest) WithSettings(settings endpoint.Settings) SecretRequest {
 	return SecretRequest{
 		URI:      r.URI,
-		Endpoint: endpoint,
+		Settings: settings,
 		Ctx:      r.Ctx,
 	}
 }
@@ -42,14 +60,22 @@ func (r SecretRequest) WithEndpoint(endpoint string) SecretRequest {
 func (r SecretRequest) WithContext(ctx context.Context) SecretRequest {
 	return SecretRequest{
 		URI:      r.URI,
-		Endpoint: r.Endpoint,
+		Settings: r.Settings,
 		Ctx:      ctx,
 	}
 }
 
-// GetCacheKey returns a unique identifier for caching
+// GetCacheKey returns a unique identifier for caching.
+// The endpoint settings are part of the key so that the same secret fetched from another endpoint
+// or with other credentials is not served from the cache.
 func (r SecretRequest) GetCacheKey() string {
-	return uri.BuildCacheKey(r.URI.Account, r.URI.Service, r.URI.SecretName, r.URI.Version, r.URI.Region)
+	key := uri.BuildCacheKey(r.URI.Account, r.URI.Service, r.URI.SecretName, r.URI.Version, r.URI.Region)
+	return fmt.Sprintf("%s|%s|%s", key, r.Settings.Endpoint, r.Settings.Credentials)
+}
+
+// ResolvedVersionID returns the version ID that a previously retrieved secret resolved to with the given endpoint settings
+func (p *AwsProvider) ResolvedVersionID(uri uri.SecretURI, settings endpoint.Settings) functional.Option[string] {
+	return p.GetCachedVersionID(NewSecretRequest(uri).WithSettings(settings).GetCacheKey())
 }
 
 // GetSecrets retrieves secrets using a background context
@@ -65,8 +91,8 @@ func (p *AwsProvider) GetSecrets(uri uri.SecretURI) (string, error) {
 }
 
 // GetSecretsWithEndpoint retrieves secrets using a background context and a custom endpoint
-func (p *AwsProvider) GetSecretsWithEndpoint(uri uri.SecretURI, endpoint string) (string, error) {
-	req := NewSecretRequest(uri).WithEndpoint(endpoint)
+func (p *AwsProvider) GetSecretsWithEndpoint(uri uri.SecretURI, settings endpoint.Settings) (string, error) {
+	req := NewSecretRequest(uri).WithSettings(settings)
 	result := p.GetSecretValue(req)
 
 	if result.IsFailure() {
@@ -85,6 +111,7 @@ func (p *AwsProvider) GetSecretValue(req SecretRequest) functional.Result[string
 
 	// If found in cache, parse and return
 	if cachedSecret.IsSome() {
+		audit.RecordAccess(req.URI, p.GetCachedVersionID(cacheKey).UnwrapOr(""), audit.CacheHit, nil)
 		valueResult := secret.ParseValueResult(cachedSecret.Unwrap(), req.URI.Key)
 		if valueResult.IsFailure() {
 			// キーが見つからない場合のエラーをより詳細なメッセージに変換
@@ -128,7 +155,7 @@ func (p *AwsProvider) GetSecretValue(req SecretRequest) functional.Result[string
 // 副作用のある関数: AWS APIを呼び出します
 func (p *AwsProvider) RetrieveSecret(req SecretRequest) functional.Result[string] {
 	// Get or create client
-	client, err := p.GetClient(req.Ctx, req.URI.Account, req.URI.Region, req.Endpoint)
+	client, err := p.GetClient(req.Ctx, req.URI.Account, req.URI.Region, req.Settings)
 	if err != nil {
 		return functional.Failure[string](
 			fmt.Errorf("failed to get AWS client - account: %s, region: %s: %w",
@@ -136,22 +163,37 @@ func (p *AwsProvider) RetrieveSecret(req SecretRequest) functional.Result[string
 	}
 
 	// Fetch secret
-	secretResult := FetchSecret(req.Ctx, client, req.URI)
+	secretResult := fetchAuditedSecretVersion(req.Ctx, client, req.URI)
 	if secretResult.IsFailure() {
 		return functional.Failure[string](
 			fmt.Errorf("failed to retrieve secret [%s/%s] - account: %s, region: %s: %w",
 				req.URI.Service, req.URI.SecretName, req.URI.Account, req.URI.Region, secretResult.GetError()))
 	}
 
-	return secretResult
+	// Remember which version the stage resolved to
+	secretVersion := secretResult.Unwrap()
+	p.CacheVersionID(req.GetCacheKey(), secretVersion.VersionID)
+
+	return functional.Success(secretVersion.Value)
 }
 
 // FetchSecret calls AWS Secrets Manager API to get a secret value
 func FetchSecret(ctx context.Context, client *secretsmanager.Client, uri uri.SecretURI) functional.Result[string] {
-	// Create input for GetSecretValue
+	return functional.MapResultTo(fetchAuditedSecretVersion(ctx, client, uri), func(v SecretVersion) string {
+		return v.Value
+	})
+}
+
+// FetchSecretVersion calls AWS Secrets Manager API to get a secret value and its version ID
+func FetchSecretVersion(ctx context.Context, client *secretsmanager.Client, uri uri.SecretURI) functional.Result[SecretVersion] {
+	// Create input for GetSecretValue (a pinned version ID or a staging label)
 	input := &secretsmanager.GetSecretValueInput{
-		SecretId:     aws.String(uri.SecretName),
-		VersionStage: aws.String(uri.Version),
+		SecretId: aws.String(uri.SecretName),
+	}
+	if IsVersionID(uri.Version) {
+		input.VersionId = aws.String(uri.Version)
+	} else {
+		input.VersionStage = aws.String(uri.Version)
 	}
 
 	// Use proper logging format instead of fmt.Println
@@ -160,18 +202,36 @@ func FetchSecret(ctx context.Context, client *secretsmanager.Client, uri uri.Sec
 	// Call AWS API
 	result, err := client.GetSecretValue(ctx, input)
 	if err != nil {
-		return functional.Failure[string](
+		return functional.Failure[SecretVersion](
 			fmt.Errorf("AWS Secrets Manager API error [%s] - version: %s, region: %s: %w",
 				uri.SecretName, uri.Version, uri.Region, err))
 	}
 
-	// Validate response
+	// Validate response (binary secrets cannot be written to environment variables)
+	if result.SecretString == nil && result.SecretBinary != nil {
+		return functional.Failure[SecretVersion](
+			fmt.Errorf("binary secret values are not supported [%s] - version: %s, region: %s",
+				uri.SecretName, uri.Version, uri.Region))
+	}
 	if result.SecretString == nil {
-		return functional.Failure[string](
+		return functional.Failure[SecretVersion](
 			fmt.Errorf("empty secret value [%s] - version: %s, region: %s",
 				uri.SecretName, uri.Version, uri.Region))
 	}
 
-	// Return the secret string
-	return functional.Success(*result.SecretString)
+	// Remember the value so that it is scrubbed from everything sem prints
+	redact.Register(*result.SecretString)
+
+	// Return the secret string with the version ID the stage resolved to
+	return functional.Success(SecretVersion{
+		Value:     *result.SecretString,
+		VersionID: aws.ToString(result.VersionId),
+	})
+}
+
+// fetchAuditedSecretVersion fetches a secret and records the access in the audit log
+func fetchAuditedSecretVersion(ctx context.Context, client *secretsmanager.Client, uri uri.SecretURI) functional.Result[SecretVersion] {
+	result := FetchSecretVersion(ctx, client, uri)
+	audit.RecordAccess(uri, result.GetValue().VersionID, audit.CacheMiss, result.GetError())
+	return result
 }
@@ -3,10 +3,14 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/logging"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
 )
 
 // Secrets is a collection of Secret objects
@@ -17,7 +21,7 @@ type Secrets struct {
 // ListSecretsResult lists all secrets in a specific AWS account and region
 func (p *AwsProvider) ListSecretsResult(ctx context.Context, account string, region string) functional.Result[[]string] {
 	// Get client for listing secrets
-	client, err := p.GetClient(ctx, account, region, "")
+	client, err := p.GetClient(ctx, account, region, endpoint.Settings{})
 	if err != nil {
 		return functional.Failure[[]string](fmt.Errorf("failed to get AWS client: %w", err))
 	}
@@ -27,7 +31,7 @@ func (p *AwsProvider) ListSecretsResult(ctx context.Context, account string, reg
 		MaxResults: nil, // Use default limit
 	}
 
-	fmt.Println("Listing secrets for account:", account, "in region:", region)
+	logging.DefaultLogger().Info("Listing secrets for account: %s in region: %s", account, region)
 
 	result, err := client.ListSecrets(ctx, input)
 	if err != nil {
@@ -56,16 +60,16 @@ func (p *AwsProvider) ListSecrets(ctx context.Context, account string, region st
 
 // ListSecretVersions lists all the versions for a specific secret
 func (p *AwsProvider) ListSecretVersions(ctx context.Context, profile string, region string, secretName string) ([]string, error) {
-	return p.ListSecretVersionsWithEndpoint(ctx, profile, region, secretName, "")
+	return p.ListSecretVersionsWithEndpoint(ctx, profile, region, secretName, endpoint.Settings{})
 }
 
 // ListSecretVersionsWithEndpoint lists all the versions for a specific secret with a custom endpoint
-func (p *AwsProvider) ListSecretVersionsWithEndpoint(ctx context.Context, profile string, region string, secretName string, endpoint string) ([]string, error) {
+func (p *AwsProvider) ListSecretVersionsWithEndpoint(ctx context.Context, profile string, region string, secretName string, settings endpoint.Settings) ([]string, error) {
 	if region == "" {
 		return nil, fmt.Errorf("region is required")
 	}
 
-	client, err := p.GetClient(ctx, profile, region, endpoint)
+	client, err := p.GetClient(ctx, profile, region, settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get AWS client: %w", err)
 	}
@@ -88,3 +92,39 @@ func (p *AwsProvider) ListSecretVersionsWithEndpoint(ctx context.Context, profil
 
 	return versions, nil
 }
+
+// ListSecretVersionsResult lists the versions of a secret with their staging labels and creation dates, newest first
+func (p *AwsProvider) ListSecretVersionsResult(ctx context.Context, profile string, region string, secretName string, settings endpoint.Settings) functional.Result[[]secret.Version] {
+	client, err := p.GetClient(ctx, profile, region, settings)
+	if err != nil {
+		return functional.Failure[[]secret.Version](fmt.Errorf("failed to get AWS client: %w", err))
+	}
+
+	versions := []secret.Version{}
+	input := &secretsmanager.ListSecretVersionIdsInput{
+		SecretId:          aws.String(secretName),
+		IncludeDeprecated: aws.Bool(true),
+	}
+
+	paginator := secretsmanager.NewListSecretVersionIdsPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return functional.Failure[[]secret.Version](
+				fmt.Errorf("AWS ListSecretVersionIds API error [%s] - region: %s: %w", secretName, region, err))
+		}
+
+		for _, entry := range page.Versions {
+			versions = append(versions, secret.Version{
+				ID:        aws.ToString(entry.VersionId),
+				Stages:    entry.VersionStages,
+				CreatedAt: aws.ToTime(entry.CreatedDate),
+			})
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreatedAt.After(versions[j].CreatedAt)
+	})
+	return functional.Success(versions)
+}
@@ -6,41 +6,44 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 )
 
 // ClientConfig holds configuration for creating an AWS client
 type ClientConfig struct {
-	Profile  string
-	Region   string
-	Endpoint string
+	Profile     string
+	Region      string
+	Endpoint    string
+	Credentials string // Credential source (see package endpoint); the profile is used unless static credentials are requested
 }
 
 // NewClientConfig creates a new ClientConfig with the provided values
-func NewClientConfig(profile string, region string, endpoint string) ClientConfig {
+func NewClientConfig(profile string, region string, settings endpoint.Settings) ClientConfig {
 	return ClientConfig{
-		Profile:  profile,
-		Region:   region,
-		Endpoint: endpoint,
+		Profile:     profile,
+		Region:      region,
+		Endpoint:    settings.Endpoint,
+		Credentials: settings.Credentials,
 	}
 }
 
 // WithEndpoint returns a new ClientConfig with the specified endpoint
 func (c ClientConfig) WithEndpoint(endpoint string) ClientConfig {
 	return ClientConfig{
-		Profile:  c.Profile,
-		Region:   c.Region,
-		Endpoint: endpoint,
+		Profile:     c.Profile,
+		Region:      c.Region,
+		Endpoint:    endpoint,
+		Credentials: c.Credentials,
 	}
 }
 
 // GetCacheKey returns a unique identifier for this configuration
 func (c ClientConfig) GetCacheKey() string {
-	return fmt.Sprintf("%s:%s:%s", c.Profile, c.Region, c.Endpoint)
+	return fmt.Sprintf("%s:%s:%s:%s", c.Profile, c.Region, c.Endpoint, c.Credentials)
 }
 
 // AwsProvider handles interactions with AWS Secrets Manager,
@@ -50,6 +53,10 @@ type AwsProvider struct {
 	secretCache    map[string]string
 	secretCacheMux sync.RWMutex
 
+	// Version IDs that the retrieved secrets resolved to
+	versionCache    map[string]string
+	versionCacheMux sync.RWMutex
+
 	// Cache of API clients for different profiles/regions
 	clientCache    map[string]*secretsmanager.Client
 	clientCacheMux sync.RWMutex
@@ -60,8 +67,9 @@ type AwsProvider struct {
 // as secrets are requested.
 func NewAwsProvider() *AwsProvider {
 	return &AwsProvider{
-		secretCache: make(map[string]string),
-		clientCache: make(map[string]*secretsmanager.Client),
+		secretCache:  make(map[string]string),
+		versionCache: make(map[string]string),
+		clientCache:  make(map[string]*secretsmanager.Client),
 	}
 }
 
@@ -84,6 +92,25 @@ func (p *AwsProvider) CacheSecret(cacheKey string, value string) {
 	p.secretCache[cacheKey] = value
 }
 
+// GetCachedVersionID attempts to retrieve the resolved version ID of a secret from the cache
+func (p *AwsProvider) GetCachedVersionID(cacheKey string) functional.Option[string] {
+	p.versionCacheMux.RLock()
+	defer p.versionCacheMux.RUnlock()
+
+	if versionID, exists := p.versionCache[cacheKey]; exists {
+		return functional.Some(versionID)
+	}
+	return functional.None[string]()
+}
+
+// CacheVersionID stores the resolved version ID of a secret
+func (p *AwsProvider) CacheVersionID(cacheKey string, versionID string) {
+	p.versionCacheMux.Lock()
+	defer p.versionCacheMux.Unlock()
+
+	p.versionCache[cacheKey] = versionID
+}
+
 // GetCachedClient attempts to retrieve a client from the cache
 func (p *AwsProvider) GetCachedClient(cacheKey string) functional.Option[*secretsmanager.Client] {
 	p.clientCacheMux.RLock()
@@ -103,31 +130,25 @@ func (p *AwsProvider) CacheClient(cacheKey string, client *secretsmanager.Client
 	p.clientCache[cacheKey] = client
 }
 
-// CreateAwsClient creates a new AWS Secrets Manager client based on the configuration
+// CreateAwsClient creates a new AWS Secrets Manager client based on the configuration.
+// A custom endpoint does not change the credentials: dummy static credentials are only
+// used when requested, so that endpoints such as VPC endpoints work with real credentials.
 func CreateAwsClient(ctx context.Context, clientConfig ClientConfig) functional.Result[*secretsmanager.Client] {
-	var cfg aws.Config
-	var err error
-
-	// If endpoint is specified, use LocalStack mode with dummy credentials
-	if clientConfig.Endpoint != "" {
-		// For LocalStack, use dummy credentials
+	options := []func(*config.LoadOptions) error{config.WithRegion(clientConfig.Region)}
+	if clientConfig.Credentials == endpoint.StaticCredentials {
+		// Emulators such as LocalStack accept any credentials
 		staticProvider := credentials.NewStaticCredentialsProvider("test", "test", "")
-
-		cfg, err = config.LoadDefaultConfig(ctx,
-			config.WithRegion(clientConfig.Region),
-			config.WithCredentialsProvider(staticProvider),
-		)
-	} else {
+		options = append(options, config.WithCredentialsProvider(staticProvider))
+	} else if clientConfig.Profile != "" {
 		// For real AWS, use profile-based configuration
-		var options []func(*config.LoadOptions) error
-		options = append(options, config.WithRegion(clientConfig.Region))
-		if clientConfig.Profile != "" {
-			options = append(options, config.WithSharedConfigProfile(clientConfig.Profile))
-		}
-
-		cfg, err = config.LoadDefaultConfig(ctx, options...)
+		options = append(options, config.WithSharedConfigProfile(clientConfig.Profile))
 	}
 
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil && clientConfig.Endpoint != "" {
+		return functional.Failure[*secretsmanager.Client](
+			fmt.Errorf("failed to load AWS config (use credentials=static for emulators such as LocalStack): %w", err))
+	}
 	if err != nil {
 		return functional.Failure[*secretsmanager.Client](
 			fmt.Errorf("failed to load AWS config: %w", err))
@@ -147,10 +168,10 @@ func CreateAwsClient(ctx context.Context, clientConfig ClientConfig) functional.
 }
 
 // GetClient returns a cached or new secretsmanager.Client
-// It creates a new client for the given profile and region if not found in cache
-func (p *AwsProvider) GetClient(ctx context.Context, profile string, region string, endpoint string) (*secretsmanager.Client, error) {
+// It creates a new client for the given profile, region and endpoint settings if not found in cache
+func (p *AwsProvider) GetClient(ctx context.Context, profile string, region string, settings endpoint.Settings) (*secretsmanager.Client, error) {
 	// Create a configuration for the client
-	clientConfig := NewClientConfig(profile, region, endpoint)
+	clientConfig := NewClientConfig(profile, region, settings)
 	cacheKey := clientConfig.GetCacheKey()
 
 	// Try to get from cache first
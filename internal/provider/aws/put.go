@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
+)
+
+// ReadCurrentValueResult fetches the current value of a secret. A missing secret is reported as None.
+func (p *AwsProvider) ReadCurrentValueResult(ctx context.Context, profile string, region string, secretName string, settings endpoint.Settings) functional.Result[functional.Option[string]] {
+	client, err := p.GetClient(ctx, profile, region, settings)
+	if err != nil {
+		return functional.Failure[functional.Option[string]](fmt.Errorf("failed to get AWS client: %w", err))
+	}
+
+	output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretName),
+	})
+	auditCurrentValueAccess(profile, region, secretName, output, err)
+	if isNotFound(err) {
+		return functional.Success(functional.None[string]())
+	}
+	if err != nil {
+		return functional.Failure[functional.Option[string]](
+			fmt.Errorf("AWS Secrets Manager API error [%s] - region: %s: %w", secretName, region, err))
+	}
+	// A binary secret would otherwise be read as empty and overwritten by a new JSON document
+	if output.SecretString == nil && output.SecretBinary != nil {
+		return functional.Failure[functional.Option[string]](
+			fmt.Errorf("binary secret values are not supported [%s] - region: %s", secretName, region))
+	}
+	redact.Register(aws.ToString(output.SecretString))
+	return functional.Success(functional.Some(aws.ToString(output.SecretString)))
+}
+
+// auditCurrentValueAccess records the read of the current value in the audit log
+func auditCurrentValueAccess(profile string, region string, secretName string, output *secretsmanager.GetSecretValueOutput, err error) {
+	secretURI := uri.NewSecretURI(uri.AwsPlatform, "secretsmanager", profile, secretName)
+	secretURI.Version = uri.AwsDefaultVersion
+	secretURI.Region = region
+
+	versionID := ""
+	if output != nil {
+		versionID = aws.ToString(output.VersionId)
+	}
+	audit.RecordAccess(secretURI, versionID, audit.CacheMiss, err)
+}
+
+// PutSecretValueResult stores a value as the new AWSCURRENT version of a secret, creating the secret if it does not exist
+func (p *AwsProvider) PutSecretValueResult(ctx context.Context, profile string, region string, secretName string, value string, settings endpoint.Settings) functional.Result[secret.Written] {
+	client, err := p.GetClient(ctx, profile, region, settings)
+	if err != nil {
+		return functional.Failure[secret.Written](fmt.Errorf("failed to get AWS client: %w", err))
+	}
+
+	output, err := client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretName),
+		SecretString: aws.String(value),
+	})
+	if err == nil {
+		return functional.Success(secret.Written{VersionID: aws.ToString(output.VersionId)})
+	}
+	if !isNotFound(err) {
+		return functional.Failure[secret.Written](
+			fmt.Errorf("AWS PutSecretValue API error [%s] - region: %s: %w", secretName, region, err))
+	}
+
+	created, err := client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(secretName),
+		SecretString: aws.String(value),
+	})
+	if err != nil {
+		return functional.Failure[secret.Written](
+			fmt.Errorf("AWS CreateSecret API error [%s] - region: %s: %w", secretName, region, err))
+	}
+	return functional.Success(secret.Written{VersionID: aws.ToString(created.VersionId), Created: true})
+}
+
+// isNotFound checks if an AWS API error reports a missing secret
+func isNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+)
+
+// PendingStage is the staging label a rotation Lambda gives the new version before it is promoted
+const PendingStage = "AWSPENDING"
+
+// currentStage is the staging label of the version returned by default
+const currentStage = "AWSCURRENT"
+
+// DescribeRotationResult reports the rotation configuration and history of a secret using DescribeSecret
+func (p *AwsProvider) DescribeRotationResult(ctx context.Context, profile string, region string, secretName string, settings endpoint.Settings) functional.Result[secret.Rotation] {
+	client, err := p.GetClient(ctx, profile, region, settings)
+	if err != nil {
+		return functional.Failure[secret.Rotation](fmt.Errorf("failed to get AWS client: %w", err))
+	}
+
+	output, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretName),
+	})
+	if err != nil {
+		return functional.Failure[secret.Rotation](
+			fmt.Errorf("AWS DescribeSecret API error [%s] - region: %s: %w", secretName, region, err))
+	}
+
+	return functional.Success(secret.Rotation{
+		Enabled:        aws.ToBool(output.RotationEnabled),
+		Rotator:        aws.ToString(output.RotationLambdaARN),
+		Schedule:       formatRotationRules(output.RotationRules),
+		LastRotatedAt:  aws.ToTime(output.LastRotatedDate),
+		NextRotationAt: aws.ToTime(output.NextRotationDate),
+		PendingVersion: findPendingVersion(output.VersionIdsToStages),
+	})
+}
+
+// formatRotationRules formats the rotation schedule of a secret
+func formatRotationRules(rules *types.RotationRulesType) string {
+	if rules == nil {
+		return ""
+	}
+	if rules.ScheduleExpression != nil {
+		return aws.ToString(rules.ScheduleExpression)
+	}
+	if rules.AutomaticallyAfterDays != nil {
+		return fmt.Sprintf("every %d days", aws.ToInt64(rules.AutomaticallyAfterDays))
+	}
+	return ""
+}
+
+// findPendingVersion returns the version labeled AWSPENDING that has not been promoted to AWSCURRENT
+func findPendingVersion(versionStages map[string][]string) string {
+	for versionID, stages := range versionStages {
+		pending, current := false, false
+		for _, stage := range stages {
+			pending = pending || stage == PendingStage
+			current = current || stage == currentStage
+		}
+		if pending && !current {
+			return versionID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+// Package provider supplies interfaces and implementations for retrieving secrets
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+)
+
+// Origin records which input entry produced an output key
+type Origin struct {
+	Location string // Location of the entry in the input file (e.g. "staging.env:3")
+	URI      string // Secret URI of the entry (empty for plain values)
+	Version  string // Version ID the secret resolved to (empty for plain values)
+	Path     string // JSON path of the value within the secret (empty for the whole value)
+	Expanded bool   // The key name came from the secret (JSON expansion or bare URI) rather than the entry
+}
+
+// String formats the origin as its location and URI
+func (o Origin) String() string {
+	if o.URI == "" {
+		return o.Location
+	}
+	return fmt.Sprintf("%s (%s)", o.Location, o.URI)
+}
+
+// Collision describes an output key produced by more than one entry
+type Collision struct {
+	Key      string
+	Previous Origin // Entry whose value was overwritten
+	Current  Origin // Entry whose value was kept
+}
+
+// IsError reports whether the collision silently drops a value that came from a secret.
+// Redefining a plain variable keeps the last definition and is only a warning.
+func (c Collision) IsError() bool {
+	return c.Previous.Expanded || c.Current.Expanded
+}
+
+// String formats the collision with the location of both entries
+func (c Collision) String() string {
+	return fmt.Sprintf("'%s' is produced by %s and overwritten by %s", c.Key, c.Previous, c.Current)
+}
+
+// originsFor creates the origins of the keys produced by an entry from the origin of the entry
+// and the JSON path of each key
+func originsFor(origin Origin, entryKey string, paths map[string]string, values map[string]string) map[string]Origin {
+	origins := make(map[string]Origin, len(values))
+	for key := range values {
+		keyOrigin := origin
+		keyOrigin.Path = paths[key]
+		keyOrigin.Expanded = key != entryKey
+		origins[key] = keyOrigin
+	}
+	return origins
+}
+
+// ProducedKeys groups output keys by the location of the entry that produced them
+func ProducedKeys(origins map[string]Origin) map[string][]string {
+	produced := make(map[string][]string)
+	for key, origin := range origins {
+		produced[origin.Location] = append(produced[origin.Location], key)
+	}
+	return produced
+}
+
+// CollisionsFor returns the collisions of a single key, in the order they happened
+func CollisionsFor(collisions []Collision, key string) []Collision {
+	return functional.Filter(collisions, func(c Collision) bool {
+		return c.Key == key
+	})
+}
+
+// detectCollisions finds keys of the next result that were already produced by a different entry
+func detectCollisions(previous, next map[string]Origin) []Collision {
+	keys := make([]string, 0, len(next))
+	for key := range next {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	collisions := []Collision{}
+	for _, key := range keys {
+		before, exists := previous[key]
+		if !exists || before.Location == "" || before.Location == next[key].Location {
+			continue
+		}
+		collisions = append(collisions, Collision{Key: key, Previous: before, Current: next[key]})
+	}
+	return collisions
+}
+
+// SplitCollisions separates collisions into errors and warnings
+func SplitCollisions(collisions []Collision) ([]Collision, []Collision) {
+	errors := []Collision{}
+	warnings := []Collision{}
+	for _, collision := range collisions {
+		if collision.IsError() {
+			errors = append(errors, collision)
+		} else {
+			warnings = append(warnings, collision)
+		}
+	}
+	return errors, warnings
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+func TestProcessEntriesResultCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []env.Entry
+		expected []Collision
+	}{
+		{
+			name: "Distinct keys",
+			entries: []env.Entry{
+				env.NewEntry(1, "A", "1"),
+				env.NewEntry(2, "B", "2"),
+			},
+			expected: []Collision{},
+		},
+		{
+			name: "Redefined plain variable is a warning",
+			entries: []env.Entry{
+				env.NewEntry(1, "A", "1"),
+				env.NewEntry(2, "A", "2"),
+			},
+			expected: []Collision{
+				{Key: "A", Previous: Origin{Location: "line 1"}, Current: Origin{Location: "line 2"}},
+			},
+		},
+		{
+			name: "Expanded key overwriting a variable is an error",
+			entries: []env.Entry{
+				env.NewEntry(1, "DB_user", "admin").WithSource(".env"),
+				env.NewEntry(2, "DB", `{"user": "root", "host": "db"}`).WithSource(".env"),
+			},
+			expected: []Collision{
+				{Key: "DB_user", Previous: Origin{Location: ".env:1"}, Current: Origin{Location: ".env:2", Path: "user", Expanded: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessEntriesResult(tt.entries, map[string]SecretProvider{})
+			if !result.IsSuccess() {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !reflect.DeepEqual(result.Collisions, tt.expected) {
+				t.Errorf("Collisions = %+v, want %+v", result.Collisions, tt.expected)
+			}
+
+			errors, _ := SplitCollisions(result.Collisions)
+			for _, collision := range errors {
+				if !collision.IsError() {
+					t.Errorf("SplitCollisions() returned a warning as an error: %+v", collision)
+				}
+			}
+		})
+	}
+}
+
+func TestCollisionsFor(t *testing.T) {
+	first := Origin{Location: ".env:1", URI: "sem://aws:secretsmanager/p/a"}
+	second := Origin{Location: ".env:2"}
+	third := Origin{Location: ".env:3"}
+	collisions := []Collision{
+		{Key: "A", Previous: first, Current: second},
+		{Key: "B", Previous: first, Current: second},
+		{Key: "A", Previous: second, Current: third},
+	}
+
+	result := CollisionsFor(collisions, "A")
+	expected := []Collision{collisions[0], collisions[2]}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("CollisionsFor() = %+v, want %+v", result, expected)
+	}
+
+	if message := collisions[0].String(); message != "'A' is produced by .env:1 (sem://aws:secretsmanager/p/a) and overwritten by .env:2" {
+		t.Errorf("Collision.String() = %q", message)
+	}
+}
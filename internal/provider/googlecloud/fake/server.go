@@ -0,0 +1,320 @@
+// Package fake provides an in-memory Google Cloud Secret Manager gRPC server for tests.
+// It implements the calls used by sem (secrets, versions and aliases, global and regional)
+// and is reached with --gcp-endpoint, the same way LocalStack is reached with --endpoint-url.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// latestAlias is resolved to the newest enabled version, as in Google Cloud
+const latestAlias = "latest"
+
+// Seed is a secret to create when the server starts
+type Seed struct {
+	Project  string           `json:"project"`
+	Region   string           `json:"region,omitempty"` // Location of a regional secret; empty for a global secret
+	Name     string           `json:"name"`
+	Versions []string         `json:"versions"`          // Payloads of versions 1, 2, ... in order
+	Aliases  map[string]int64 `json:"aliases,omitempty"` // Version aliases such as "stable": 2
+}
+
+// storedSecret is a secret and its versions, the first being version 1
+type storedSecret struct {
+	secret   *secretmanagerpb.Secret
+	versions []storedVersion
+}
+
+// storedVersion is a version and its payload
+type storedVersion struct {
+	version *secretmanagerpb.SecretVersion
+	payload []byte
+}
+
+// Server is an in-memory Secret Manager
+type Server struct {
+	secretmanagerpb.UnimplementedSecretManagerServiceServer
+
+	mu      sync.Mutex
+	secrets map[string]*storedSecret // Keyed by resource name
+	clock   time.Time                // Creation time of the next resource, advanced on each use
+
+	grpcServer *grpc.Server
+}
+
+// NewServer creates an empty server
+func NewServer() *Server {
+	return &Server{
+		secrets: map[string]*storedSecret{},
+		clock:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Start serves on address ("localhost:0" picks a free port) in the background and returns the address listened on
+func (s *Server) Start(address string) (string, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	s.grpcServer = grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(s.grpcServer, s)
+	go func() {
+		_ = s.grpcServer.Serve(listener)
+	}()
+	return listener.Addr().String(), nil
+}
+
+// Stop stops serving and closes the open connections
+func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+// Seed creates a secret with its versions and aliases
+func (s *Server) Seed(seed Seed) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := secretName(seed.Project, seed.Region, seed.Name)
+	if _, exists := s.secrets[name]; exists {
+		return fmt.Errorf("secret %s is seeded twice", name)
+	}
+	for alias, number := range seed.Aliases {
+		if number < 1 || number > int64(len(seed.Versions)) {
+			return fmt.Errorf("alias %s of secret %s refers to missing version %d", alias, name, number)
+		}
+	}
+
+	stored := s.createSecret(name, &secretmanagerpb.Secret{VersionAliases: seed.Aliases})
+	for _, payload := range seed.Versions {
+		s.addVersion(stored, []byte(payload))
+	}
+	return nil
+}
+
+// LoadSeedFile seeds the secrets of a JSON file holding an array of Seed
+func (s *Server) LoadSeedFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	var seeds []Seed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return fmt.Errorf("failed to parse seed file %s: %w", path, err)
+	}
+	for _, seed := range seeds {
+		if err := s.Seed(seed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateSecret creates a secret without versions
+func (s *Server) CreateSecret(_ context.Context, req *secretmanagerpb.CreateSecretRequest) (*secretmanagerpb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.GetSecretId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret_id is required")
+	}
+	name := req.GetParent() + "/secrets/" + req.GetSecretId()
+	if _, exists := s.secrets[name]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "Secret [%s] already exists.", name)
+	}
+
+	// Regional secrets have no replication policy, global secrets require one
+	regional := strings.Contains(req.GetParent(), "/locations/")
+	if regional && req.GetSecret().GetReplication() != nil {
+		return nil, status.Error(codes.InvalidArgument, "regional secrets cannot have a replication policy")
+	}
+	if !regional && req.GetSecret().GetReplication() == nil {
+		return nil, status.Error(codes.InvalidArgument, "replication is required")
+	}
+
+	return proto.Clone(s.createSecret(name, req.GetSecret()).secret).(*secretmanagerpb.Secret), nil
+}
+
+// GetSecret returns the metadata of a secret
+func (s *Server) GetSecret(_ context.Context, req *secretmanagerpb.GetSecretRequest) (*secretmanagerpb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.findSecret(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(stored.secret).(*secretmanagerpb.Secret), nil
+}
+
+// ListSecrets lists the secrets of a project or of a region of a project, in a single page
+func (s *Server) ListSecrets(_ context.Context, req *secretmanagerpb.ListSecretsRequest) (*secretmanagerpb.ListSecretsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := req.GetParent() + "/secrets/"
+	names := []string{}
+	for name := range s.secrets {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	response := &secretmanagerpb.ListSecretsResponse{TotalSize: int32(len(names))}
+	for _, name := range names {
+		response.Secrets = append(response.Secrets, proto.Clone(s.secrets[name].secret).(*secretmanagerpb.Secret))
+	}
+	return response, nil
+}
+
+// AddSecretVersion stores a payload as the next version of a secret
+func (s *Server) AddSecretVersion(_ context.Context, req *secretmanagerpb.AddSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.findSecret(req.GetParent())
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(s.addVersion(stored, req.GetPayload().GetData())).(*secretmanagerpb.SecretVersion), nil
+}
+
+// ListSecretVersions lists the versions of a secret, newest first, in a single page
+func (s *Server) ListSecretVersions(_ context.Context, req *secretmanagerpb.ListSecretVersionsRequest) (*secretmanagerpb.ListSecretVersionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.findSecret(req.GetParent())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &secretmanagerpb.ListSecretVersionsResponse{TotalSize: int32(len(stored.versions))}
+	for i := len(stored.versions) - 1; i >= 0; i-- {
+		response.Versions = append(response.Versions, proto.Clone(stored.versions[i].version).(*secretmanagerpb.SecretVersion))
+	}
+	return response, nil
+}
+
+// AccessSecretVersion returns the payload of a version given by number, alias or "latest"
+func (s *Server) AccessSecretVersion(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parent, version, found := strings.Cut(req.GetName(), "/versions/")
+	if !found {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid version name %s", req.GetName())
+	}
+	stored, err := s.findSecret(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolveVersion(stored, version)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.version.GetState() != secretmanagerpb.SecretVersion_ENABLED {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is in %s state", resolved.version.GetName(), resolved.version.GetState())
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Name:    resolved.version.GetName(),
+		Payload: &secretmanagerpb.SecretPayload{Data: resolved.payload},
+	}, nil
+}
+
+// createSecret stores a copy of a secret under a resource name
+func (s *Server) createSecret(name string, secret *secretmanagerpb.Secret) *storedSecret {
+	created := &secretmanagerpb.Secret{}
+	if secret != nil {
+		created = proto.Clone(secret).(*secretmanagerpb.Secret)
+	}
+	created.Name = name
+	created.CreateTime = s.tick()
+
+	stored := &storedSecret{secret: created}
+	s.secrets[name] = stored
+	return stored
+}
+
+// addVersion appends an enabled version to a secret
+func (s *Server) addVersion(stored *storedSecret, payload []byte) *secretmanagerpb.SecretVersion {
+	version := &secretmanagerpb.SecretVersion{
+		Name:       fmt.Sprintf("%s/versions/%d", stored.secret.GetName(), len(stored.versions)+1),
+		CreateTime: s.tick(),
+		State:      secretmanagerpb.SecretVersion_ENABLED,
+	}
+	stored.versions = append(stored.versions, storedVersion{version: version, payload: append([]byte{}, payload...)})
+	return version
+}
+
+// findSecret returns the secret of a resource name
+func (s *Server) findSecret(name string) (*storedSecret, error) {
+	stored, exists := s.secrets[name]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions.", name)
+	}
+	return stored, nil
+}
+
+// tick returns the current time of the server clock and advances it, so that creation times are distinct
+func (s *Server) tick() *timestamppb.Timestamp {
+	now := s.clock
+	s.clock = s.clock.Add(time.Second)
+	return timestamppb.New(now)
+}
+
+// resolveVersion finds the version of a secret given by number, alias or "latest"
+func resolveVersion(stored *storedSecret, version string) (storedVersion, error) {
+	notFound := status.Errorf(codes.NotFound, "Secret Version [%s/versions/%s] not found.", stored.secret.GetName(), version)
+
+	if version == latestAlias {
+		for i := len(stored.versions) - 1; i >= 0; i-- {
+			if stored.versions[i].version.GetState() == secretmanagerpb.SecretVersion_ENABLED {
+				return stored.versions[i], nil
+			}
+		}
+		return storedVersion{}, notFound
+	}
+
+	number, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		alias, exists := stored.secret.GetVersionAliases()[version]
+		if !exists {
+			return storedVersion{}, notFound
+		}
+		number = alias
+	}
+	if number < 1 || number > int64(len(stored.versions)) {
+		return storedVersion{}, notFound
+	}
+	return stored.versions[number-1], nil
+}
+
+// secretName returns the resource name of a global or regional secret
+func secretName(project, region, name string) string {
+	if region == "" {
+		return fmt.Sprintf("projects/%s/secrets/%s", project, name)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/secrets/%s", project, region, name)
+}
@@ -0,0 +1,156 @@
+package fake_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud"
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud/fake"
+)
+
+const project = "sem-test-project"
+
+// startServer starts a seeded fake server on a free port and returns the settings reaching it
+func startServer(t *testing.T) endpoint.Settings {
+	t.Helper()
+
+	server := fake.NewServer()
+	seeds := []fake.Seed{
+		{Project: project, Name: "simple_secret", Versions: []string{"simpleValue123"}},
+		{Project: project, Name: "versioned_secret", Versions: []string{`{"version":"v1"}`, `{"version":"v2"}`, `{"version":"v3"}`},
+			Aliases: map[string]int64{"stable": 2}},
+		{Project: project, Region: "europe-west1", Name: "regional_secret", Versions: []string{"regionalValue"}},
+	}
+	for _, seed := range seeds {
+		if err := server.Seed(seed); err != nil {
+			t.Fatalf("Seed() error = %v", err)
+		}
+	}
+
+	address, err := server.Start("localhost:0")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(server.Stop)
+	return endpoint.Settings{Endpoint: address}
+}
+
+func TestGetSecrets(t *testing.T) {
+	settings := startServer(t)
+
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Latest version",
+			uri:  "sem://googlecloud:secretmanager/sem-test-project/simple_secret",
+			want: "simpleValue123",
+		},
+		{
+			name: "Version number",
+			uri:  "sem://googlecloud:secretmanager/sem-test-project/versioned_secret?version=1&key=version",
+			want: "v1",
+		},
+		{
+			name: "Version alias",
+			uri:  "sem://googlecloud:secretmanager/sem-test-project/versioned_secret?version=stable&key=version",
+			want: "v2",
+		},
+		{
+			name: "Regional secret",
+			uri:  "sem://googlecloud:secretmanager/sem-test-project/regional_secret?region=europe-west1",
+			want: "regionalValue",
+		},
+		{
+			name:    "Regional secret read as a global secret",
+			uri:     "sem://googlecloud:secretmanager/sem-test-project/regional_secret",
+			wantErr: true,
+		},
+		{
+			name:    "Missing version",
+			uri:     "sem://googlecloud:secretmanager/sem-test-project/versioned_secret?version=4",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secretURI := uri.ParseResult(tt.uri).Unwrap()
+			got, err := googlecloud.NewGoogleCloudProvider().GetSecretsWithEndpoint(secretURI, settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSecretsWithEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSecretsWithEndpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSecretVersion(t *testing.T) {
+	settings := startServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		region      string
+		secretName  string
+		wantVersion string
+		wantCreated bool
+	}{
+		{name: "Existing secret", secretName: "simple_secret", wantVersion: "2", wantCreated: false},
+		{name: "New global secret", secretName: "new_secret", wantVersion: "1", wantCreated: true},
+		{name: "New regional secret", region: "europe-west1", secretName: "new_regional_secret", wantVersion: "1", wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := googlecloud.NewGoogleCloudProvider()
+			written := p.AddSecretVersionResult(ctx, project, tt.region, tt.secretName, "written", settings)
+			if written.IsFailure() {
+				t.Fatalf("AddSecretVersionResult() error = %v", written.GetError())
+			}
+			if got := written.Unwrap(); got.VersionID != tt.wantVersion || got.Created != tt.wantCreated {
+				t.Errorf("AddSecretVersionResult() = %+v, want version %s created %v", got, tt.wantVersion, tt.wantCreated)
+			}
+
+			current := p.ReadCurrentValueResult(ctx, project, tt.region, tt.secretName, settings)
+			if current.IsFailure() || current.Unwrap().UnwrapOr("") != "written" {
+				t.Errorf("ReadCurrentValueResult() = %v, %v, want written", current.GetValue(), current.GetError())
+			}
+		})
+	}
+}
+
+func TestListSecretVersions(t *testing.T) {
+	settings := startServer(t)
+
+	result := googlecloud.NewGoogleCloudProvider().ListSecretVersionsResult(context.Background(), project, "", "versioned_secret", settings)
+	if result.IsFailure() {
+		t.Fatalf("ListSecretVersionsResult() error = %v", result.GetError())
+	}
+
+	versions := result.Unwrap()
+	want := []struct {
+		id     string
+		stages string
+	}{
+		{id: "3", stages: "[latest]"},
+		{id: "2", stages: "[stable]"},
+		{id: "1", stages: "[]"},
+	}
+	if len(versions) != len(want) {
+		t.Fatalf("ListSecretVersionsResult() returned %d versions, want %d", len(versions), len(want))
+	}
+	for i, w := range want {
+		if versions[i].ID != w.id || fmt.Sprint(versions[i].Stages) != w.stages {
+			t.Errorf("version %d = %s %v, want %s %s", i, versions[i].ID, versions[i].Stages, w.id, w.stages)
+		}
+	}
+}
@@ -8,36 +8,65 @@ import (
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
 	"github.com/gumi-tsd/secret-env-manager/internal/secret"
 )
 
 // SecretRequest encapsulates all parameters needed to retrieve a secret
 type SecretRequest struct {
-	URI uri.SecretURI
-	Ctx context.Context
+	URI      uri.SecretURI
+	Settings endpoint.Settings // Endpoint and credential source
+	Ctx      context.Context
+}
+
+// SecretVersion is a secret value together with the version ID it resolved to
+type SecretVersion struct {
+	Value     string
+	VersionID string
 }
 
 // NewSecretRequest creates a new SecretRequest with default context
 func NewSecretRequest(uri uri.SecretURI) SecretRequest {
 	return SecretRequest{
-		URI: uri,
-		Ctx: context.Background(),
+		URI:      uri,
+		Settings: endpoint.Settings{},
+		Ctx:      context.Background(),
+	}
+}
+
+// WithSettings returns a new SecretRequest with the specified endpoint settings
+func (r SecretRequest) WithSettings(settings endpoint.Settings) SecretRequest {
+	return SecretRequest{
+		URI:      r.URI,
+		Settings: settings,
+		Ctx:      r.Ctx,
 	}
 }
 
 // WithContext returns a new SecretRequest with the specified context
 func (r SecretRequest) WithContext(ctx context.Context) SecretRequest {
 	return SecretRequest{
-		URI: r.URI,
-		Ctx: ctx,
+		URI:      r.URI,
+		Settings: r.Settings,
+		Ctx:      ctx,
 	}
 }
 
-// GetCacheKey returns a unique identifier for caching
+// GetCacheKey returns a unique identifier for caching.
+// The endpoint settings are part of the key so that the same secret fetched from another endpoint
+// or with other credentials is not served from the cache.
 func (r SecretRequest) GetCacheKey() string {
-	return uri.BuildCacheKey(r.URI.Account, r.URI.Service, r.URI.SecretName, r.URI.Version, r.URI.Region)
+	key := uri.BuildCacheKey(r.URI.Account, r.URI.Service, r.URI.SecretName, r.URI.Version, r.URI.Region)
+	return fmt.Sprintf("%s|%s|%s", key, r.Settings.Endpoint, r.Settings.Credentials)
+}
+
+// ResolvedVersionID returns the version number that a previously retrieved secret resolved to with the given endpoint settings
+func (p *GoogleCloudProvider) ResolvedVersionID(uri uri.SecretURI, settings endpoint.Settings) functional.Option[string] {
+	return p.GetCachedVersionID(NewSecretRequest(uri).WithSettings(settings).GetCacheKey())
 }
 
 // GetSecrets retrieves secrets using a background context
@@ -52,6 +81,18 @@ func (p *GoogleCloudProvider) GetSecrets(uri uri.SecretURI) (string, error) {
 	return result.Unwrap(), nil
 }
 
+// GetSecretsWithEndpoint retrieves secrets using a background context and a custom endpoint
+func (p *GoogleCloudProvider) GetSecretsWithEndpoint(uri uri.SecretURI, settings endpoint.Settings) (string, error) {
+	req := NewSecretRequest(uri).WithSettings(settings)
+	result := p.GetSecretValue(req)
+
+	if result.IsFailure() {
+		return "", result.GetError()
+	}
+
+	return result.Unwrap(), nil
+}
+
 // GetSecretValue is a functional implementation that fetches and processes secrets
 func (p *GoogleCloudProvider) GetSecretValue(req SecretRequest) functional.Result[string] {
 	// Check cache first
@@ -60,6 +101,7 @@ func (p *GoogleCloudProvider) GetSecretValue(req SecretRequest) functional.Resul
 
 	// If found in cache, parse and return
 	if cachedSecret.IsSome() {
+		audit.RecordAccess(req.URI, p.GetCachedVersionID(cacheKey).UnwrapOr(""), audit.CacheHit, nil)
 		valueResult := secret.ParseValueResult(cachedSecret.Unwrap(), req.URI.Key)
 		if valueResult.IsFailure() {
 			err := valueResult.GetError()
@@ -100,29 +142,40 @@ func (p *GoogleCloudProvider) GetSecretValue(req SecretRequest) functional.Resul
 // RetrieveSecret fetches a secret from Google Cloud Secret Manager
 func (p *GoogleCloudProvider) RetrieveSecret(req SecretRequest) functional.Result[string] {
 	// Get or create client
-	client, err := p.GetClient(req.Ctx)
+	client, err := p.GetClient(req.Ctx, req.URI.Region, req.Settings)
 	if err != nil {
 		return functional.Failure[string](
-			fmt.Errorf("failed to get Google Cloud client - project: %s: %w",
-				req.URI.Account, err))
+			fmt.Errorf("failed to get Google Cloud client - project: %s, region: %s: %w",
+				req.URI.Account, req.URI.Region, err))
 	}
 
 	// Fetch secret
-	secretResult := FetchSecret(req.Ctx, client, req.URI)
+	secretResult := fetchAuditedSecretVersion(req.Ctx, client, req.URI)
 	if secretResult.IsFailure() {
 		return functional.Failure[string](
 			fmt.Errorf("failed to retrieve secret [%s/%s] - project: %s: %w",
 				req.URI.Service, req.URI.SecretName, req.URI.Account, secretResult.GetError()))
 	}
 
-	return secretResult
+	// Remember which version an alias such as "latest" resolved to
+	secretVersion := secretResult.Unwrap()
+	p.CacheVersionID(req.GetCacheKey(), secretVersion.VersionID)
+
+	return functional.Success(secretVersion.Value)
 }
 
 // FetchSecret calls Google Cloud Secret Manager API to get a secret value
 func FetchSecret(ctx context.Context, client *secretmanager.Client, uri uri.SecretURI) functional.Result[string] {
+	return functional.MapResultTo(fetchAuditedSecretVersion(ctx, client, uri), func(v SecretVersion) string {
+		return v.Value
+	})
+}
+
+// FetchSecretVersion calls Google Cloud Secret Manager API to get a secret value and its version number
+func FetchSecretVersion(ctx context.Context, client *secretmanager.Client, uri uri.SecretURI) functional.Result[SecretVersion] {
 	// Construct the resource name
-	// Format: projects/{project}/secrets/{secret}/versions/{version}
-	resourceName := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", uri.Account, uri.SecretName, uri.Version)
+	// Format: projects/{project}[/locations/{region}]/secrets/{secret}/versions/{version}
+	resourceName := VersionResourceName(uri.Account, uri.Region, uri.SecretName, uri.Version)
 
 	// Use proper logging format instead of fmt.Println
 	secret.LogInfoMsg(fmt.Sprintf("Accessing secret: %s", uri.GetUri()))
@@ -135,18 +188,37 @@ func FetchSecret(ctx context.Context, client *secretmanager.Client, uri uri.Secr
 	// Call Google Cloud API
 	result, err := client.AccessSecretVersion(ctx, req)
 	if err != nil {
-		return functional.Failure[string](
+		return functional.Failure[SecretVersion](
 			fmt.Errorf("Google Cloud Secret Manager API error [%s] - version: %s: %w",
 				uri.SecretName, uri.Version, err))
 	}
 
 	// Validate response
 	if result.Payload == nil || result.Payload.Data == nil {
-		return functional.Failure[string](
+		return functional.Failure[SecretVersion](
 			fmt.Errorf("empty secret value [%s] - version: %s",
 				uri.SecretName, uri.Version))
 	}
 
-	// Return the secret string
-	return functional.Success(string(result.Payload.Data))
+	// Remember the value so that it is scrubbed from everything sem prints
+	redact.Register(string(result.Payload.Data))
+
+	// Return the secret string with the version number from the resolved resource name
+	return functional.Success(SecretVersion{
+		Value:     string(result.Payload.Data),
+		VersionID: extractVersionFromResourceName(result.Name),
+	})
+}
+
+// extractVersionFromResourceName extracts the version from a resource name
+// (projects/{project}[/locations/{region}]/secrets/{secret}/versions/{version})
+func extractVersionFromResourceName(resourceName string) string {
+	return resourceName[strings.LastIndex(resourceName, "/")+1:]
+}
+
+// fetchAuditedSecretVersion fetches a secret and records the access in the audit log
+func fetchAuditedSecretVersion(ctx context.Context, client *secretmanager.Client, uri uri.SecretURI) functional.Result[SecretVersion] {
+	result := FetchSecretVersion(ctx, client, uri)
+	audit.RecordAccess(uri, result.GetValue().VersionID, audit.CacheMiss, result.GetError())
+	return result
 }
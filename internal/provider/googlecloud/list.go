@@ -4,30 +4,43 @@ package googlecloud
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/logging"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
 	"google.golang.org/api/iterator"
 )
 
-// ListSecretsResult lists all secrets in a specific GCP project
-func (p *GoogleCloudProvider) ListSecretsResult(ctx context.Context, projectID string, _ string) functional.Result[[]string] {
+// latestAlias is the alias Google Cloud resolves to the newest enabled version
+const latestAlias = "latest"
+
+// ListSecretsResult lists all secrets in a specific GCP project, or in a region of the project when region is not empty
+func (p *GoogleCloudProvider) ListSecretsResult(ctx context.Context, projectID string, region string) functional.Result[[]string] {
+	return p.ListSecretsWithEndpointResult(ctx, projectID, region, endpoint.Settings{})
+}
+
+// ListSecretsWithEndpointResult lists the secrets of a project using a custom endpoint
+func (p *GoogleCloudProvider) ListSecretsWithEndpointResult(ctx context.Context, projectID string, region string, settings endpoint.Settings) functional.Result[[]string] {
 	// Get client for listing secrets
-	client, err := p.GetClient(ctx)
+	client, err := p.GetClient(ctx, region, settings)
 	if err != nil {
 		return functional.Failure[[]string](fmt.Errorf("failed to get Google Cloud client: %w", err))
 	}
 
 	// Construct the parent resource name
-	parent := fmt.Sprintf("projects/%s", projectID)
+	parent := SecretsParent(projectID, region)
 
 	// Build the request
 	req := &secretmanagerpb.ListSecretsRequest{
 		Parent: parent,
 	}
 
-	fmt.Println("Listing secrets for project:", projectID)
+	logging.DefaultLogger().Info("Listing secrets for project: %s", projectID)
 
 	// Call Google Cloud API
 	it := client.ListSecrets(ctx, req)
@@ -64,7 +77,7 @@ func (p *GoogleCloudProvider) ListSecrets(ctx context.Context, projectID string,
 }
 
 // extractSecretNameFromResourceName extracts the secret name from a resource name
-// Format: projects/{project}/secrets/{secret}
+// Format: projects/{project}[/locations/{region}]/secrets/{secret}
 func extractSecretNameFromResourceName(resourceName string) string {
 	// Find the last segment after "secrets/"
 	const prefix = "secrets/"
@@ -74,3 +87,65 @@ func extractSecretNameFromResourceName(resourceName string) string {
 	}
 	return resourceName[idx+len(prefix):]
 }
+
+// ListSecretVersionsResult lists the versions of a secret with their aliases, states and creation dates, newest first.
+// The newest enabled version is marked with the "latest" alias.
+func (p *GoogleCloudProvider) ListSecretVersionsResult(ctx context.Context, projectID string, region string, secretName string, settings endpoint.Settings) functional.Result[[]secret.Version] {
+	client, err := p.GetClient(ctx, region, settings)
+	if err != nil {
+		return functional.Failure[[]secret.Version](fmt.Errorf("failed to get Google Cloud client: %w", err))
+	}
+
+	parent := SecretResourceName(projectID, region, secretName)
+
+	// Aliases are stored on the secret as alias -> version number
+	metadata, err := client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: parent})
+	if err != nil {
+		return functional.Failure[[]secret.Version](
+			fmt.Errorf("Google Cloud GetSecret API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+	aliases := map[string][]string{}
+	for alias, number := range metadata.GetVersionAliases() {
+		id := strconv.FormatInt(number, 10)
+		aliases[id] = append(aliases[id], alias)
+	}
+
+	versions := []secret.Version{}
+	it := client.ListSecretVersions(ctx, &secretmanagerpb.ListSecretVersionsRequest{Parent: parent})
+	for {
+		version, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return functional.Failure[[]secret.Version](
+				fmt.Errorf("error iterating versions of secret [%s] - project: %s: %w", secretName, projectID, err))
+		}
+
+		id := extractVersionFromResourceName(version.GetName())
+		stages := append([]string{}, aliases[id]...)
+		sort.Strings(stages)
+		versions = append(versions, secret.Version{
+			ID:        id,
+			Stages:    stages,
+			CreatedAt: version.GetCreateTime().AsTime(),
+			State:     version.GetState().String(),
+		})
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreatedAt.After(versions[j].CreatedAt)
+	})
+	markLatest(versions)
+	return functional.Success(versions)
+}
+
+// markLatest adds the "latest" alias to the newest enabled version (versions are sorted newest first)
+func markLatest(versions []secret.Version) {
+	for i, version := range versions {
+		if version.State == secretmanagerpb.SecretVersion_ENABLED.String() {
+			versions[i].Stages = append([]string{latestAlias}, version.Stages...)
+			return
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package googlecloud
+
+import "fmt"
+
+// Resource names of Google Cloud Secret Manager.
+// Regional secrets live under projects/{project}/locations/{region} instead of projects/{project}.
+
+// SecretsParent returns the parent of the secrets of a project, in a region when it is not empty
+func SecretsParent(projectID string, region string) string {
+	if region == "" {
+		return fmt.Sprintf("projects/%s", projectID)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s", projectID, region)
+}
+
+// SecretResourceName returns the resource name of a secret
+func SecretResourceName(projectID string, region string, secretName string) string {
+	return fmt.Sprintf("%s/secrets/%s", SecretsParent(projectID, region), secretName)
+}
+
+// VersionResourceName returns the resource name of a version or alias of a secret
+func VersionResourceName(projectID string, region string, secretName string, version string) string {
+	return fmt.Sprintf("%s/versions/%s", SecretResourceName(projectID, region, secretName), version)
+}
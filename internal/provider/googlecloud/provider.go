@@ -4,12 +4,65 @@ package googlecloud
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// ClientConfig holds configuration for creating a Google Cloud client
+type ClientConfig struct {
+	Region      string // Location of regional secrets; empty for global secrets
+	Endpoint    string // Custom API endpoint (host:port) overriding the global or regional endpoint
+	Credentials string // Credential source (see package endpoint)
+}
+
+// NewClientConfig creates a new ClientConfig with the provided values
+func NewClientConfig(region string, settings endpoint.Settings) ClientConfig {
+	return ClientConfig{
+		Region:      region,
+		Endpoint:    settings.Endpoint,
+		Credentials: settings.Credentials,
+	}
+}
+
+// ResolveEndpoint returns the API endpoint of this configuration.
+// Regional secrets are served by secretmanager.<region>.rep.googleapis.com;
+// an empty result means the default global endpoint.
+func (c ClientConfig) ResolveEndpoint() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if c.Region != "" {
+		return fmt.Sprintf("secretmanager.%s.rep.googleapis.com:443", c.Region)
+	}
+	return ""
+}
+
+// GetCacheKey returns a unique identifier for this configuration.
+// Clients are shared by every project using the same endpoint and credentials.
+func (c ClientConfig) GetCacheKey() string {
+	key := c.ResolveEndpoint()
+	if key == "" {
+		key = "default"
+	}
+	if c.Credentials != "" {
+		key += "|" + c.Credentials
+	}
+	return key
+}
+
+// isUnauthenticated reports whether requests are sent without credentials,
+// as requested or because the endpoint is local
+func (c ClientConfig) isUnauthenticated() bool {
+	return c.Credentials == endpoint.NoCredentials || endpoint.IsLocal(c.Endpoint)
+}
+
 // GoogleCloudProvider handles interactions with Google Cloud Secret Manager,
 // including caching of secrets and API clients.
 type GoogleCloudProvider struct {
@@ -17,7 +70,11 @@ type GoogleCloudProvider struct {
 	secretCache    map[string]string
 	secretCacheMux sync.RWMutex
 
-	// Cache of API clients
+	// Version IDs that the retrieved secrets resolved to
+	versionCache    map[string]string
+	versionCacheMux sync.RWMutex
+
+	// Cache of API clients for different endpoints
 	clientCache    map[string]*secretmanager.Client
 	clientCacheMux sync.RWMutex
 }
@@ -27,8 +84,9 @@ type GoogleCloudProvider struct {
 // as secrets are requested.
 func NewGoogleCloudProvider() *GoogleCloudProvider {
 	return &GoogleCloudProvider{
-		secretCache: make(map[string]string),
-		clientCache: make(map[string]*secretmanager.Client),
+		secretCache:  make(map[string]string),
+		versionCache: make(map[string]string),
+		clientCache:  make(map[string]*secretmanager.Client),
 	}
 }
 
@@ -51,6 +109,25 @@ func (p *GoogleCloudProvider) CacheSecret(cacheKey string, value string) {
 	p.secretCache[cacheKey] = value
 }
 
+// GetCachedVersionID attempts to retrieve the resolved version ID of a secret from the cache
+func (p *GoogleCloudProvider) GetCachedVersionID(cacheKey string) functional.Option[string] {
+	p.versionCacheMux.RLock()
+	defer p.versionCacheMux.RUnlock()
+
+	if versionID, exists := p.versionCache[cacheKey]; exists {
+		return functional.Some(versionID)
+	}
+	return functional.None[string]()
+}
+
+// CacheVersionID stores the resolved version ID of a secret
+func (p *GoogleCloudProvider) CacheVersionID(cacheKey string, versionID string) {
+	p.versionCacheMux.Lock()
+	defer p.versionCacheMux.Unlock()
+
+	p.versionCache[cacheKey] = versionID
+}
+
 // GetCachedClient attempts to retrieve a client from the cache
 func (p *GoogleCloudProvider) GetCachedClient(cacheKey string) functional.Option[*secretmanager.Client] {
 	p.clientCacheMux.RLock()
@@ -70,9 +147,22 @@ func (p *GoogleCloudProvider) CacheClient(cacheKey string, client *secretmanager
 	p.clientCache[cacheKey] = client
 }
 
-// CreateGoogleCloudClient creates a new Google Cloud Secret Manager client
-func CreateGoogleCloudClient(ctx context.Context) functional.Result[*secretmanager.Client] {
-	client, err := secretmanager.NewClient(ctx)
+// CreateGoogleCloudClient creates a new Google Cloud Secret Manager client based on the configuration.
+// The default credentials are used unless no credentials are requested; local endpoints
+// are reached with plaintext gRPC and without credentials.
+func CreateGoogleCloudClient(ctx context.Context, clientConfig ClientConfig) functional.Result[*secretmanager.Client] {
+	var options []option.ClientOption
+	if resolved := clientConfig.ResolveEndpoint(); resolved != "" {
+		options = append(options, option.WithEndpoint(strings.TrimPrefix(resolved, "http://")))
+	}
+	if clientConfig.isUnauthenticated() {
+		options = append(options, option.WithoutAuthentication())
+	}
+	if endpoint.IsLocal(clientConfig.Endpoint) {
+		options = append(options, option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	}
+
+	client, err := secretmanager.NewClient(ctx, options...)
 	if err != nil {
 		return functional.Failure[*secretmanager.Client](
 			fmt.Errorf("failed to create secretmanager client: %w", err))
@@ -81,8 +171,11 @@ func CreateGoogleCloudClient(ctx context.Context) functional.Result[*secretmanag
 }
 
 // GetClient returns a cached or new *secretmanager.Client
-func (p *GoogleCloudProvider) GetClient(ctx context.Context) (*secretmanager.Client, error) {
-	const cacheKey = "default" // Using default authentication credentials for GoogleCloud
+// It creates a new client for the given region and endpoint settings if not found in cache
+func (p *GoogleCloudProvider) GetClient(ctx context.Context, region string, settings endpoint.Settings) (*secretmanager.Client, error) {
+	// Create a configuration for the client
+	clientConfig := NewClientConfig(region, settings)
+	cacheKey := clientConfig.GetCacheKey()
 
 	// Try to get from cache first
 	cachedClientOpt := p.GetCachedClient(cacheKey)
@@ -91,7 +184,7 @@ func (p *GoogleCloudProvider) GetClient(ctx context.Context) (*secretmanager.Cli
 	}
 
 	// Create a new client
-	clientResult := CreateGoogleCloudClient(ctx)
+	clientResult := CreateGoogleCloudClient(ctx, clientConfig)
 	if clientResult.IsFailure() {
 		return nil, clientResult.GetError()
 	}
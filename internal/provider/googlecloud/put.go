@@ -0,0 +1,95 @@
+package googlecloud
+
+import (
+	"context"
+	"fmt"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReadCurrentValueResult fetches the latest value of a secret. A missing secret is reported as None.
+func (p *GoogleCloudProvider) ReadCurrentValueResult(ctx context.Context, projectID string, region string, secretName string, settings endpoint.Settings) functional.Result[functional.Option[string]] {
+	client, err := p.GetClient(ctx, region, settings)
+	if err != nil {
+		return functional.Failure[functional.Option[string]](fmt.Errorf("failed to get Google Cloud client: %w", err))
+	}
+
+	result, err := client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: VersionResourceName(projectID, region, secretName, latestAlias),
+	})
+	secretURI := uri.NewSecretURI(uri.GoogleCloudPlatform, "secretmanager", projectID, secretName)
+	secretURI.Version = uri.GoogleCloudDefaultVersion
+	secretURI.Region = region
+	audit.RecordAccess(secretURI, extractVersionFromResourceName(result.GetName()), audit.CacheMiss, err)
+	if status.Code(err) == codes.NotFound {
+		return functional.Success(functional.None[string]())
+	}
+	if err != nil {
+		return functional.Failure[functional.Option[string]](
+			fmt.Errorf("Google Cloud Secret Manager API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+	redact.Register(string(result.GetPayload().GetData()))
+	return functional.Success(functional.Some(string(result.GetPayload().GetData())))
+}
+
+// AddSecretVersionResult stores a value as a new version of a secret, creating the secret
+// if it does not exist: with automatic replication, or in the region for regional secrets
+func (p *GoogleCloudProvider) AddSecretVersionResult(ctx context.Context, projectID string, region string, secretName string, value string, settings endpoint.Settings) functional.Result[secret.Written] {
+	client, err := p.GetClient(ctx, region, settings)
+	if err != nil {
+		return functional.Failure[secret.Written](fmt.Errorf("failed to get Google Cloud client: %w", err))
+	}
+
+	request := &secretmanagerpb.AddSecretVersionRequest{
+		Parent:  SecretResourceName(projectID, region, secretName),
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte(value)},
+	}
+
+	version, err := client.AddSecretVersion(ctx, request)
+	if err == nil {
+		return functional.Success(secret.Written{VersionID: extractVersionFromResourceName(version.GetName())})
+	}
+	if status.Code(err) != codes.NotFound {
+		return functional.Failure[secret.Written](
+			fmt.Errorf("Google Cloud AddSecretVersion API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+
+	_, err = client.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+		Parent:   SecretsParent(projectID, region),
+		SecretId: secretName,
+		Secret:   newSecret(region),
+	})
+	if err != nil {
+		return functional.Failure[secret.Written](
+			fmt.Errorf("Google Cloud CreateSecret API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+
+	version, err = client.AddSecretVersion(ctx, request)
+	if err != nil {
+		return functional.Failure[secret.Written](
+			fmt.Errorf("Google Cloud AddSecretVersion API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+	return functional.Success(secret.Written{VersionID: extractVersionFromResourceName(version.GetName()), Created: true})
+}
+
+// newSecret returns the secret to create. Regional secrets are stored in their region and have no replication policy.
+func newSecret(region string) *secretmanagerpb.Secret {
+	if region != "" {
+		return &secretmanagerpb.Secret{}
+	}
+	return &secretmanagerpb.Secret{
+		Replication: &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_Automatic_{
+				Automatic: &secretmanagerpb.Replication_Automatic{},
+			},
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package googlecloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/secret"
+)
+
+// DescribeRotationResult reports the rotation configuration of a secret.
+// Google Cloud only notifies topics on schedule and does not record when a secret was last rotated.
+func (p *GoogleCloudProvider) DescribeRotationResult(ctx context.Context, projectID string, region string, secretName string, settings endpoint.Settings) functional.Result[secret.Rotation] {
+	client, err := p.GetClient(ctx, region, settings)
+	if err != nil {
+		return functional.Failure[secret.Rotation](fmt.Errorf("failed to get Google Cloud client: %w", err))
+	}
+
+	metadata, err := client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{
+		Name: SecretResourceName(projectID, region, secretName),
+	})
+	if err != nil {
+		return functional.Failure[secret.Rotation](
+			fmt.Errorf("Google Cloud GetSecret API error [%s] - project: %s: %w", secretName, projectID, err))
+	}
+
+	rotation := metadata.GetRotation()
+	if rotation == nil {
+		return functional.Success(secret.Rotation{})
+	}
+
+	topics := make([]string, 0, len(metadata.GetTopics()))
+	for _, topic := range metadata.GetTopics() {
+		topics = append(topics, topic.GetName())
+	}
+
+	result := secret.Rotation{
+		Enabled: true,
+		Rotator: strings.Join(topics, ","),
+	}
+	if rotation.GetRotationPeriod() != nil {
+		result.Schedule = fmt.Sprintf("every %s", rotation.GetRotationPeriod().AsDuration())
+	}
+	if rotation.GetNextRotationTime() != nil {
+		result.NextRotationAt = rotation.GetNextRotationTime().AsTime()
+	}
+	return functional.Success(result)
+}
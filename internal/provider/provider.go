@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
+	"github.com/gumi-tsd/secret-env-manager/internal/expand"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/logging"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
 	"github.com/gumi-tsd/secret-env-manager/internal/provider/aws"
@@ -14,25 +17,41 @@ import (
 	"github.com/gumi-tsd/secret-env-manager/internal/text"
 )
 
+// logger is the shared diagnostics logger (writes to stderr)
+var logger = logging.DefaultLogger()
+
 // ProviderConfig contains configuration for creating providers
 type ProviderConfig struct {
-	EndpointURL  string
+	Endpoints    endpoint.Resolver // Endpoint and credential source of each secret (overridable per URI)
 	NoExpandJson bool
+	Expand       expand.Options // Global JSON expansion options (overridable per URI with ?expand=)
 }
 
-// NewProviderConfig creates a new provider configuration
+// NewProviderConfig creates a new provider configuration with a custom AWS Secrets Manager endpoint
 func NewProviderConfig(endpointURL string) ProviderConfig {
 	return ProviderConfig{
-		EndpointURL:  endpointURL,
+		Endpoints:    endpoint.NewResolver(nil, nil).WithDefault(uri.AwsPlatform, endpoint.Settings{Endpoint: endpointURL}),
 		NoExpandJson: false,
+		Expand:       expand.DefaultOptions(),
+	}
+}
+
+// ExpandOptions returns the effective global expansion options
+func (c ProviderConfig) ExpandOptions() expand.Options {
+	if c.NoExpandJson {
+		return c.Expand.Disabled()
 	}
+	return c.Expand
 }
 
 // SecretResult represents the result of retrieving a secret
 type SecretResult struct {
-	Values map[string]string
-	Keys   []string
-	Error  error
+	Values     map[string]string
+	Keys       []string
+	Error      error
+	Origins    map[string]Origin // Output key -> entry that produced it
+	Collisions []Collision       // Keys produced by more than one entry
+	Versions   map[string]string // Secret reference (see uri.SecretURI.Reference) -> version ID it resolved to
 }
 
 // NewSecretResult creates a successful secret result
@@ -46,11 +65,17 @@ func NewSecretResult(values map[string]string, keys []string) SecretResult {
 
 // WithError creates a new SecretResult with an error
 func (r SecretResult) WithError(err error) SecretResult {
-	return SecretResult{
-		Values: r.Values,
-		Keys:   r.Keys,
-		Error:  err,
-	}
+	result := r
+	result.Error = err
+	return result
+}
+
+// WithOrigin returns a new SecretResult recording that its keys were produced by the entry
+// described by origin, taking each key's JSON path from paths
+func (r SecretResult) WithOrigin(origin Origin, entryKey string, paths map[string]string) SecretResult {
+	result := r
+	result.Origins = originsFor(origin, entryKey, paths, r.Values)
+	return result
 }
 
 // IsSuccess checks if the result is successful
@@ -75,19 +100,31 @@ func (r SecretResult) Merge(other SecretResult) SecretResult {
 		result[k] = v
 	}
 
-	return NewSecretResult(
+	// Track which entry produced each key, recording keys that are overwritten
+	origins := make(map[string]Origin, len(r.Origins)+len(other.Origins))
+	for k, o := range r.Origins {
+		origins[k] = o
+	}
+	for k, o := range other.Origins {
+		origins[k] = o
+	}
+
+	merged := NewSecretResult(
 		result,
 		append(r.Keys, other.Keys...),
 	)
+	merged.Origins = origins
+	merged.Versions = mergeVersions(r.Versions, other.Versions)
+	merged.Collisions = append(append([]Collision{}, r.Collisions...), detectCollisions(r.Origins, other.Origins)...)
+	return merged
 }
 
 // CreateProviderMap constructs a map of platform identifiers to SecretProviders
 func CreateProviderMap(config ProviderConfig) map[string]SecretProvider {
 	return map[string]SecretProvider{
 		"aws": &awsSecretProvider{
-			provider:    aws.NewAwsProvider(),
-			endpointURL: config.EndpointURL,
-			config:      config,
+			provider: aws.NewAwsProvider(),
+			config:   config,
 		},
 		"googlecloud": &googleCloudSecretProvider{
 			provider: googlecloud.NewGoogleCloudProvider(),
@@ -101,9 +138,8 @@ func CreateProviderMap(config ProviderConfig) map[string]SecretProvider {
 func NewAwsSecretProvider(provider *aws.AwsProvider, endpointURL string) SecretProvider {
 	config := NewProviderConfig(endpointURL)
 	return &awsSecretProvider{
-		provider:    provider,
-		endpointURL: endpointURL,
-		config:      config,
+		provider: provider,
+		config:   config,
 	}
 }
 
@@ -133,17 +169,13 @@ func AcquireSecretsMapping(entries []env.Entry, endpointURL string) (map[string]
 }
 
 type awsSecretProvider struct {
-	provider    *aws.AwsProvider
-	endpointURL string
-	config      ProviderConfig
+	provider *aws.AwsProvider
+	config   ProviderConfig
 }
 
-// GetSecrets retrieves secrets from AWS with optional custom endpoint
+// GetSecrets retrieves secrets from AWS using the endpoint settings of the secret
 func (p *awsSecretProvider) GetSecrets(uri uri.SecretURI) (string, error) {
-	if p.endpointURL != "" {
-		return p.provider.GetSecretsWithEndpoint(uri, p.endpointURL)
-	}
-	return p.provider.GetSecrets(uri)
+	return p.provider.GetSecretsWithEndpoint(uri, uri.ResolveEndpoint(p.config.Endpoints))
 }
 
 // GetSecretsResult retrieves secrets from AWS with Result monad
@@ -160,15 +192,20 @@ func (p *awsSecretProvider) GetConfig() ProviderConfig {
 	return p.config
 }
 
+// ResolvedVersionID returns the version ID a previously retrieved AWS secret resolved to
+func (p *awsSecretProvider) ResolvedVersionID(uri uri.SecretURI) functional.Option[string] {
+	return p.provider.ResolvedVersionID(uri, uri.ResolveEndpoint(p.config.Endpoints))
+}
+
 type googleCloudSecretProvider struct {
 	provider *googlecloud.GoogleCloudProvider
 	cache    map[string]string
 	config   ProviderConfig
 }
 
-// GetSecrets retrieves secrets from Google Cloud
+// GetSecrets retrieves secrets from Google Cloud using the endpoint settings of the secret
 func (p *googleCloudSecretProvider) GetSecrets(uri uri.SecretURI) (string, error) {
-	return p.provider.GetSecrets(uri)
+	return p.provider.GetSecretsWithEndpoint(uri, uri.ResolveEndpoint(p.config.Endpoints))
 }
 
 // GetSecretsResult retrieves secrets from Google Cloud with Result monad
@@ -185,12 +222,17 @@ func (p *googleCloudSecretProvider) GetConfig() ProviderConfig {
 	return p.config
 }
 
+// ResolvedVersionID returns the version a previously retrieved Google Cloud secret resolved to
+func (p *googleCloudSecretProvider) ResolvedVersionID(uri uri.SecretURI) functional.Option[string] {
+	return p.provider.ResolvedVersionID(uri, uri.ResolveEndpoint(p.config.Endpoints))
+}
+
 // ProcessEntriesResult processes entries and returns a SecretResult
 func ProcessEntriesResult(entries []env.Entry, providers map[string]SecretProvider) SecretResult {
 	result := NewSecretResult(make(map[string]string), []string{})
 
-	// Get config from first provider to access NoExpandJson setting
-	var config ProviderConfig
+	// Get config from first provider to access the expansion settings
+	config := NewProviderConfig("")
 	for _, p := range providers {
 		// 各プロバイダーから設定を取得
 		config = p.GetConfig()
@@ -199,7 +241,7 @@ func ProcessEntriesResult(entries []env.Entry, providers map[string]SecretProvid
 	}
 
 	for i, entry := range entries {
-		entryResult := ProcessEntryResultWithOptions(i, entry, providers, config.NoExpandJson)
+		entryResult := ProcessEntryResultWithExpand(i, entry, providers, config.ExpandOptions())
 		if !entryResult.IsSuccess() {
 			return entryResult
 		}
@@ -222,45 +264,24 @@ func ProcessEntries(entries []env.Entry, providers map[string]SecretProvider) (m
 // ProcessEntryResult processes a single environment entry and returns a SecretResult
 // 部分的に純粋な関数: ログ出力以外の副作用はありません
 func ProcessEntryResult(idx int, entry env.Entry, providers map[string]SecretProvider) SecretResult {
-	// Try to parse the entry as a secret URI
-	uriResult := ParseEntryAsSecretURI(entry)
-
-	// If it's not a valid secret URI, handle as a regular entry
-	if uriResult.IsFailure() {
-		err := uriResult.GetError()
-		// ログ出力は副作用なのでこの関数は厳密には純粋関数ではありません
-		logSkippedEntry(idx+1, entry.Key, "not a valid secret URI: "+err.Error())
-		return NewSecretResult(handleRegularEntry(entry), []string{entry.Key})
-	}
-
-	uri := uriResult.Unwrap()
-
-	// Retrieve the secret using the appropriate provider
-	secretResult := RetrieveSecretResult(uri, providers)
-	if secretResult.IsFailure() {
-		err := secretResult.GetError()
-
-		// For unsupported platforms, log and handle as a regular entry
-		if strings.HasPrefix(err.Error(), "unsupported platform") {
-			logSkippedEntry(idx+1, entry.Key, err.Error())
-			return NewSecretResult(handleRegularEntry(entry), []string{entry.Key})
-		}
-
-		// Otherwise, return the error
-		return NewSecretResult(nil, nil).WithError(
-			fmt.Errorf("failed to retrieve secret for line %d: %w", idx+1, err))
-	}
-
-	// Process the secret value
-	secretValue := secretResult.Unwrap()
-	key := DetermineEntryKey(entry)
-	vals := ProcessSecret(key, uri, secretValue)
-
-	return NewSecretResult(vals, ExtractKeys(vals))
+	return ProcessEntryResultWithExpand(idx, entry, providers, expand.DefaultOptions())
 }
 
 // ProcessEntryResultWithOptions processes a single environment entry with options and returns a SecretResult
 func ProcessEntryResultWithOptions(idx int, entry env.Entry, providers map[string]SecretProvider, noExpandJson bool) SecretResult {
+	options := expand.DefaultOptions()
+	if noExpandJson {
+		options = options.Disabled()
+	}
+	return ProcessEntryResultWithExpand(idx, entry, providers, options)
+}
+
+// ProcessEntryResultWithExpand processes a single environment entry using the given
+// JSON expansion options, which the URI can override with ?expand=.
+// The result records the provenance (origin) of every key it produces.
+func ProcessEntryResultWithExpand(idx int, entry env.Entry, providers map[string]SecretProvider, options expand.Options) SecretResult {
+	origin := Origin{Location: entry.Location()}
+
 	// Try to parse the entry as a secret URI
 	uriResult := ParseEntryAsSecretURI(entry)
 
@@ -269,10 +290,18 @@ func ProcessEntryResultWithOptions(idx int, entry env.Entry, providers map[strin
 		err := uriResult.GetError()
 		// ログ出力は副作用なのでこの関数は厳密には純粋関数ではありません
 		logSkippedEntry(idx+1, entry.Key, "not a valid secret URI: "+err.Error())
-		return NewSecretResult(handleRegularEntry(entry), []string{entry.Key})
+		return regularEntryResult(entry, options, origin)
 	}
 
 	uri := uriResult.Unwrap()
+	origin.URI = uri.GetUri()
+
+	// Apply per-URI expansion options on top of the global ones
+	optionsResult := uriExpandOptions(uri, options)
+	if optionsResult.IsFailure() {
+		return NewSecretResult(nil, nil).WithError(
+			fmt.Errorf("line %d: %w", idx+1, optionsResult.GetError()))
+	}
 
 	// Retrieve the secret using the appropriate provider
 	secretResult := RetrieveSecretResult(uri, providers)
@@ -282,7 +311,7 @@ func ProcessEntryResultWithOptions(idx int, entry env.Entry, providers map[strin
 		// For unsupported platforms, log and handle as a regular entry
 		if strings.HasPrefix(err.Error(), "unsupported platform") {
 			logSkippedEntry(idx+1, entry.Key, err.Error())
-			return NewSecretResult(handleRegularEntry(entry), []string{entry.Key})
+			return regularEntryResult(entry, options, Origin{Location: entry.Location()})
 		}
 
 		// Otherwise, return the error
@@ -293,9 +322,38 @@ func ProcessEntryResultWithOptions(idx int, entry env.Entry, providers map[strin
 	// Process the secret value with options
 	secretValue := secretResult.Unwrap()
 	key := DetermineEntryKey(entry)
-	vals := ProcessSecretWithOptions(key, uri, secretValue, noExpandJson)
+	vals := applyEntryDefault(entry, ProcessSecretWithExpand(key, uri, secretValue, optionsResult.Unwrap()))
+
+	origin.Version = providers[uri.Platform].ResolvedVersionID(uri).UnwrapOr("")
+	paths := SecretPaths(key, uri, secretValue, optionsResult.Unwrap())
+
+	result := NewSecretResult(vals, ExtractKeys(vals)).WithOrigin(origin, entry.Key, paths)
+	if origin.Version != "" {
+		result.Versions = map[string]string{uri.Reference(): origin.Version}
+	}
+	return result
+}
 
-	return NewSecretResult(vals, ExtractKeys(vals))
+// mergeVersions combines the resolved versions of two results
+func mergeVersions(a, b map[string]string) map[string]string {
+	versions := make(map[string]string, len(a)+len(b))
+	for reference, version := range a {
+		versions[reference] = version
+	}
+	for reference, version := range b {
+		versions[reference] = version
+	}
+	return versions
+}
+
+// regularEntryResult processes a regular (non-secret) entry, recording the entry as the origin of its keys
+func regularEntryResult(entry env.Entry, options expand.Options, origin Origin) SecretResult {
+	vals := handleRegularEntry(entry, options)
+	paths := map[string]string{}
+	if entry.Key != "" && entry.Value != "" {
+		paths = expand.Paths(entry.Key, entry.Value, options)
+	}
+	return NewSecretResult(vals, ExtractKeys(vals)).WithOrigin(origin, entry.Key, paths)
 }
 
 // ProcessEntry processes a single environment entry
@@ -316,6 +374,28 @@ func ParseEntryAsSecretURI(entry env.Entry) functional.Result[uri.SecretURI] {
 	return uri.ParseResult(v)
 }
 
+// WithEntryURI replaces the secret URI of an entry, keeping its name and options
+func WithEntryURI(entry env.Entry, secretURI uri.SecretURI) env.Entry {
+	result := entry
+	if entry.Key != "" && entry.Value != "" {
+		result.Value = secretURI.GetUri()
+	} else {
+		result.Key = secretURI.GetUri()
+	}
+	return result
+}
+
+// OverrideVersion rewrites the secret URIs of the given platform to fetch the given version or stage
+func OverrideVersion(entries []env.Entry, platform, version string) []env.Entry {
+	return functional.Map(entries, func(entry env.Entry) env.Entry {
+		uriResult := ParseEntryAsSecretURI(entry)
+		if uriResult.IsFailure() || uriResult.Unwrap().Platform != platform {
+			return entry
+		}
+		return WithEntryURI(entry, uriResult.Unwrap().WithVersion(version))
+	})
+}
+
 // DetermineEntryKey determines the key to use for the environment entry
 func DetermineEntryKey(entry env.Entry) string {
 	if entry.Key == "" || entry.Value == "" {
@@ -333,12 +413,24 @@ func ExtractKeys(m map[string]string) []string {
 	return keys
 }
 
-// handleRegularEntry processes a regular (non-secret) environment entry
-func handleRegularEntry(entry env.Entry) map[string]string {
+// handleRegularEntry processes a regular (non-secret) environment entry.
+// Literal JSON values are expanded with the same options as secrets.
+func handleRegularEntry(entry env.Entry, options expand.Options) map[string]string {
 	if entry.Key != "" && entry.Value != "" {
-		return map[string]string{entry.Key: entry.Value}
+		return expand.ToMap(expand.Value(entry.Key, entry.Value, options))
 	}
-	return map[string]string{entry.Key: ""}
+	return map[string]string{entry.Key: entry.Options.Default}
+}
+
+// applyEntryDefault replaces an empty value of the entry's own key with its default value
+func applyEntryDefault(entry env.Entry, values map[string]string) map[string]string {
+	if entry.Options.Default == "" {
+		return values
+	}
+	if value, exists := values[entry.Key]; exists && value == "" {
+		values[entry.Key] = entry.Options.Default
+	}
+	return values
 }
 
 // RetrieveSecretResult retrieves a secret using the appropriate provider with Result monad
@@ -372,68 +464,74 @@ func ProcessSecret(entryKey string, uri uri.SecretURI, secretValue string) map[s
 
 // ProcessSecretWithOptions processes a secret value with additional options
 func ProcessSecretWithOptions(entryKey string, uri uri.SecretURI, secretValue string, noExpandJson bool) map[string]string {
-	// Try to parse as JSON first
-	jsonResult := text.ParseJSONMapResult(secretValue)
-
-	if jsonResult.IsSuccess() && !noExpandJson {
-		// Only expand JSON if not disabled
-		return processJSONSecret(entryKey, uri, jsonResult.Unwrap())
+	options := expand.DefaultOptions()
+	if noExpandJson {
+		options = options.Disabled()
 	}
-
-	// If not JSON or JSON expansion is disabled, process as plain text
-	return processPlainTextSecret(entryKey, uri, secretValue)
+	return ProcessSecretWithExpand(entryKey, uri, secretValue, options)
 }
 
-// processJSONSecret processes a JSON secret value
-func processJSONSecret(entryKey string, uri uri.SecretURI, jsonValues map[string]interface{}) map[string]string {
-	result := make(map[string]string, len(jsonValues))
-	for jsonKey, jsonValue := range jsonValues {
-		finalKey := jsonKey
-		if entryKey != "" {
-			finalKey = entryKey + "_" + jsonKey
-		}
-
-		// Convert the interface{} value to string
-		var stringValue string
-		switch v := jsonValue.(type) {
-		case string:
-			stringValue = v
-		case nil:
-			stringValue = ""
-		default:
-			// For non-string types, convert to JSON string
-			jsonBytes, err := text.MarshalToJSONString(v)
-			if err == nil {
-				stringValue = jsonBytes
-			} else {
-				stringValue = fmt.Sprintf("%v", v)
-			}
-		}
+// ProcessSecretWithExpand processes a secret value, expanding JSON values with the given options.
+// A whole JSON object secret is expanded under the entry key (or without a prefix for bare URIs);
+// any other value is stored under the final key and expanded if it is JSON.
+func ProcessSecretWithExpand(entryKey string, uri uri.SecretURI, secretValue string, options expand.Options) map[string]string {
+	prefix, value, _ := secretExpansion(entryKey, uri, secretValue, options)
 
+	result := make(map[string]string)
+	for _, variable := range expand.Value(prefix, value, options) {
 		// Remove any surrounding quotes that might have been added
-		result[finalKey] = strings.Trim(stringValue, "'")
+		result[variable.Key] = strings.Trim(variable.Value, "'")
 	}
 	return result
 }
 
-// processPlainTextSecret processes a plain text secret value
-func processPlainTextSecret(entryKey string, uri uri.SecretURI, secretValue string) map[string]string {
-	finalValue := secretValue
+// SecretPaths returns the JSON path within the secret of each key produced by ProcessSecretWithExpand
+func SecretPaths(entryKey string, uri uri.SecretURI, secretValue string, options expand.Options) map[string]string {
+	prefix, value, basePath := secretExpansion(entryKey, uri, secretValue, options)
+
+	paths := expand.Paths(prefix, value, options)
+	for key, path := range paths {
+		paths[key] = expand.JoinPath(basePath, path)
+	}
+	return paths
+}
 
-	// If a specific key was requested, try to extract it
+// secretExpansion determines the prefix and value to expand for a secret, and the JSON path
+// of that value within the secret
+func secretExpansion(entryKey string, uri uri.SecretURI, secretValue string, options expand.Options) (string, string, string) {
+	if uri.Key != "" || !options.Enabled || !text.IsJSONObject(secretValue) {
+		return DetermineFinalKey(entryKey, uri), extractSecretValue(uri, secretValue), uri.Key
+	}
+	return entryKey, secretValue, ""
+}
+
+// uriExpandOptions applies the ?expand=, ?prefix= and ?map= options of a URI on top of the given options
+func uriExpandOptions(uri uri.SecretURI, options expand.Options) functional.Result[expand.Options] {
+	optionsResult := expand.ParseResult(uri.Expand, options)
+	if optionsResult.IsFailure() {
+		return optionsResult
+	}
+
+	renameResult := expand.ParseRenameResult(uri.Rename)
+	if renameResult.IsFailure() {
+		return functional.Failure[expand.Options](renameResult.GetError())
+	}
+
+	result := optionsResult.Unwrap()
+	result.Prefix = uri.Prefix
+	result.Rename = renameResult.Unwrap()
+	return functional.Success(result)
+}
+
+// extractSecretValue extracts the value of the requested key from a JSON secret
+func extractSecretValue(uri uri.SecretURI, secretValue string) string {
 	if uri.Key != "" {
 		valueResult := secret.ParseValueResult(secretValue, uri.Key)
 		if valueResult.IsSuccess() {
-			finalValue = valueResult.Unwrap()
+			return valueResult.Unwrap()
 		}
 	}
-
-	// Remove any surrounding single quotes that might have been added
-	finalValue = strings.Trim(finalValue, "'")
-
-	finalKey := DetermineFinalKey(entryKey, uri)
-
-	return map[string]string{finalKey: finalValue}
+	return secretValue
 }
 
 // DetermineFinalKey determines the final key to use for the secret
@@ -451,5 +549,5 @@ func DetermineFinalKey(entryKey string, uri uri.SecretURI) string {
 
 // logSkippedEntry logs information about skipped entries
 func logSkippedEntry(lineNum int, key, reason string) {
-	fmt.Printf("Line %d skipped: %s (reason: %s)\n", lineNum, key, reason)
+	logger.Debug("Line %d skipped: %s (reason: %s)", lineNum, key, reason)
 }
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+)
+
+// staticProvider returns fixed secret values and version IDs keyed by secret name
+type staticProvider struct {
+	values   map[string]string
+	versions map[string]string
+}
+
+func (p staticProvider) GetSecrets(u uri.SecretURI) (string, error) {
+	return p.GetSecretsResult(u).Unwrap(), nil
+}
+
+func (p staticProvider) GetSecretsResult(u uri.SecretURI) functional.Result[string] {
+	return functional.Success(p.values[u.SecretName])
+}
+
+func (p staticProvider) GetConfig() ProviderConfig {
+	return NewProviderConfig("")
+}
+
+func (p staticProvider) ResolvedVersionID(u uri.SecretURI) functional.Option[string] {
+	if version, exists := p.versions[u.SecretName]; exists {
+		return functional.Some(version)
+	}
+	return functional.None[string]()
+}
+
+func TestProcessEntriesResultOrigins(t *testing.T) {
+	providers := map[string]SecretProvider{
+		"aws": staticProvider{
+			values:   map[string]string{"db": `{"user": "admin", "password": "secret"}`},
+			versions: map[string]string{"db": "v-123"},
+		},
+	}
+	dbURI := "sem://aws:secretsmanager/default/db"
+	keyURI := "sem://aws:secretsmanager/default/db?key=password"
+	// Origins record the normalized URI, including the default version and region
+	normalized := func(raw string) string {
+		return uri.ParseResult(raw).Unwrap().GetUri()
+	}
+
+	tests := []struct {
+		name     string
+		entry    env.Entry
+		expected map[string]Origin
+	}{
+		{
+			name:  "Expanded JSON secret",
+			entry: env.NewEntry(1, "DB", dbURI).WithSource(".env"),
+			expected: map[string]Origin{
+				"DB_password": {Location: ".env:1", URI: normalized(dbURI), Version: "v-123", Path: "password", Expanded: true},
+				"DB_user":     {Location: ".env:1", URI: normalized(dbURI), Version: "v-123", Path: "user", Expanded: true},
+			},
+		},
+		{
+			name:  "Key of a JSON secret",
+			entry: env.NewEntry(2, "DB_PASSWORD", keyURI).WithSource(".env"),
+			expected: map[string]Origin{
+				"DB_PASSWORD": {Location: ".env:2", URI: normalized(keyURI), Version: "v-123", Path: "password"},
+			},
+		},
+		{
+			name:  "Plain value",
+			entry: env.NewEntry(3, "PORT", "8080").WithSource(".env"),
+			expected: map[string]Origin{
+				"PORT": {Location: ".env:3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessEntriesResult([]env.Entry{tt.entry}, providers)
+			if !result.IsSuccess() {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !reflect.DeepEqual(result.Origins, tt.expected) {
+				t.Errorf("Origins = %+v, want %+v", result.Origins, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOverrideVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    env.Entry
+		expected env.Entry
+	}{
+		{
+			name:     "Named AWS entry",
+			entry:    env.NewEntry(1, "DB_PASSWORD", "sem://aws:secretsmanager/default/db?key=password"),
+			expected: env.NewEntry(1, "DB_PASSWORD", "sem://aws:secretsmanager/default/db?version=AWSPENDING&key=password&region=ap-northeast-1"),
+		},
+		{
+			name:     "Unnamed AWS entry",
+			entry:    env.NewEntry(2, "sem://aws:secretsmanager/default/db", ""),
+			expected: env.NewEntry(2, "sem://aws:secretsmanager/default/db?version=AWSPENDING&region=ap-northeast-1", ""),
+		},
+		{
+			name:     "Other platform",
+			entry:    env.NewEntry(3, "API_KEY", "sem://googlecloud:secretmanager/project/api-key"),
+			expected: env.NewEntry(3, "API_KEY", "sem://googlecloud:secretmanager/project/api-key"),
+		},
+		{
+			name:     "Plain value",
+			entry:    env.NewEntry(4, "PORT", "8080"),
+			expected: env.NewEntry(4, "PORT", "8080"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := OverrideVersion([]env.Entry{tt.entry}, uri.AwsPlatform, "AWSPENDING")
+			if !reflect.DeepEqual(result, []env.Entry{tt.expected}) {
+				t.Errorf("OverrideVersion() = %+v, want %+v", result, []env.Entry{tt.expected})
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package provider
 
 import (
+	"github.com/gumi-tsd/secret-env-manager/internal/endpoint"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
 )
@@ -43,6 +44,8 @@ type SecretProvider interface {
 	GetSecretsResult(uri uri.SecretURI) functional.Result[string]
 	// GetConfig returns the provider configuration
 	GetConfig() ProviderConfig
+	// ResolvedVersionID returns the version ID a previously retrieved secret resolved to
+	ResolvedVersionID(uri uri.SecretURI) functional.Option[string]
 }
 
 // FunctionalSecretProvider defines a provider interface using Result monad
@@ -54,7 +57,7 @@ type FunctionalSecretProvider interface {
 // WithEndpointURL sets the endpoint URL for a provider
 func WithEndpointURL(url string) ProviderOption {
 	return func(config *ProviderConfig) *ProviderConfig {
-		config.EndpointURL = url
+		config.Endpoints = config.Endpoints.WithDefault(uri.AwsPlatform, endpoint.Settings{Endpoint: url})
 		return config
 	}
 }
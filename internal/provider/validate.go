@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/secret"
+)
+
+// URIError is an invalid secret URI of an input file entry
+type URIError struct {
+	Location string // "file:line" or "line N"
+	Column   int    // 1-based column of the problem in the line, 0 when unknown
+	Err      error
+}
+
+// Error formats the problem as location:column: message
+func (e URIError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.Location, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Location, e.Err)
+}
+
+// ValidateEntryURIs returns the problems of the entries whose value starts with sem://
+// but is not a secret URI that sem can resolve. Such entries would otherwise be written as literal values.
+func ValidateEntryURIs(entries []env.Entry) []URIError {
+	problems := []URIError{}
+	for _, entry := range entries {
+		value, column := entryURI(entry)
+		if !secret.IsURI(value) {
+			continue
+		}
+
+		err := uri.ValidateResult(value).GetError()
+		if err == nil {
+			continue
+		}
+		problem := URIError{Location: entry.Location(), Err: err}
+		var validationErr *uri.ValidationError
+		if errors.As(err, &validationErr) && column > 0 {
+			problem.Column = column + validationErr.Offset
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// entryURI returns the URI text of an entry without surrounding spaces and quotes,
+// and the column it starts at in its env file line (0 when the entry does not come from an env file line)
+func entryURI(entry env.Entry) (string, int) {
+	value := entry.Key
+	if entry.Key != "" && entry.Value != "" {
+		value = entry.Value
+	}
+	return formatting.UnwrapQuotes(strings.TrimSpace(value)), entry.Column
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+)
+
+// envLine parses an env file line into an entry
+func envLine(t *testing.T, content string, index int) env.Entry {
+	t.Helper()
+	result := parser.ParseEnvLine(content, index)
+	if result.IsFailure() {
+		t.Fatalf("ParseEnvLine(%q) error = %v", content, result.GetError())
+	}
+	return result.Unwrap()
+}
+
+func TestValidateEntryURIs(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry env.Entry
+		want  string
+	}{
+		{
+			name:  "Valid AWS URI",
+			entry: envLine(t, "DB=sem://aws:secretsmanager/default/db?key=password&region=us-east-1", 1),
+			want:  "",
+		},
+		{
+			name:  "Valid Google Cloud URI",
+			entry: envLine(t, "sem://googlecloud:secretmanager/my-project/api-key?version=3", 1),
+			want:  "",
+		},
+		{
+			name:  "Plain value",
+			entry: envLine(t, "LOG_LEVEL=debug", 1),
+			want:  "",
+		},
+		{
+			name:  "Misspelled service",
+			entry: envLine(t, "DB=sem://aws:secretmanager/default/db", 2).WithSource(".env"),
+			want:  `.env:2:14: unknown service 'secretmanager' for platform aws (did you mean "secretsmanager"?)`,
+		},
+		{
+			name:  "Platform alias",
+			entry: envLine(t, "sem://gcp:secretmanager/my-project/api-key", 3),
+			want:  `line 3:7: unknown platform 'gcp' (did you mean "googlecloud"?)`,
+		},
+		{
+			name:  "Unknown query parameter",
+			entry: envLine(t, "DB=sem://aws:secretsmanager/default/db?key=password&verison=2", 4),
+			want:  `line 4:53: unknown query parameter 'verison' (did you mean "version"?)`,
+		},
+		{
+			name:  "Invalid region",
+			entry: envLine(t, "DB=sem://aws:secretsmanager/default/db?region=tokyo", 5),
+			want:  "line 5:47: invalid aws region 'tokyo' (expected a region such as ap-northeast-1)",
+		},
+		{
+			name:  "Invalid project ID",
+			entry: envLine(t, "API=sem://googlecloud:secretmanager/My_Project/api-key", 6),
+			want:  "line 6:37: invalid Google Cloud project ID 'My_Project' (6-30 lowercase letters, digits and hyphens, starting with a letter)",
+		},
+		{
+			name:  "Google Cloud endpoint outside googleapis.com",
+			entry: envLine(t, "API=sem://googlecloud:secretmanager/my-project/api-key?endpoint=secrets.example.com:443", 7),
+			want:  "line 7:65: endpoint 'secrets.example.com:443' is not allowed in a googlecloud URI (only local or *.googleapis.com endpoints; set others in .sem-endpoints.yaml or with --gcp-endpoint)",
+		},
+		{
+			name:  "Indented override line with spaces and quotes",
+			entry: envLine(t, `  !override DB = "sem://aws:secretmanager/default/db"`, 8),
+			want:  `line 8:29: unknown service 'secretmanager' for platform aws (did you mean "secretsmanager"?)`,
+		},
+		{
+			name:  "Indented bare URI",
+			entry: envLine(t, "\t!override sem://gcp:secretmanager/my-project/api-key", 9),
+			want:  `line 9:18: unknown platform 'gcp' (did you mean "googlecloud"?)`,
+		},
+		{
+			name:  "Missing path fields without a column in structured input",
+			entry: env.NewEntry(7, "DB", "sem://aws:secretsmanager").WithSource("env.yaml"),
+			want:  "env.yaml:7: invalid URI path: expected '<Platform>:<Service>/<Account>/<SecretName>'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := ValidateEntryURIs([]env.Entry{tt.entry})
+			got := ""
+			if len(problems) > 0 {
+				got = problems[0].Error()
+			}
+			if got != tt.want {
+				t.Errorf("ValidateEntryURIs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
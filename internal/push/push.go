@@ -0,0 +1,142 @@
+// Package push plans moving the plaintext values of an env file into cloud secrets
+// and replacing them with secret URIs.
+package push
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+	"github.com/gumi-tsd/secret-env-manager/internal/secret"
+)
+
+// identifierPattern matches names that can be used as environment variables
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Variable is a plaintext variable of the env file
+type Variable struct {
+	Line    int    // Line number in the env file
+	Content string // Line as written
+	Key     string
+	Value   string // Value with surrounding quotes removed
+}
+
+// Write is a secret value to store
+type Write struct {
+	URI   uri.SecretURI
+	Value string
+}
+
+// Plan describes which variables are moved to which secrets
+type Plan struct {
+	Target    uri.SecretURI
+	PerKey    bool       // One secret per variable instead of one JSON secret
+	Variables []Variable // Variables moved to secrets, in file order
+	Skipped   []string   // Lines left as they are, with the reason
+}
+
+// NewPlanResult collects the plaintext KEY=value lines of an env file.
+// Secret URIs, comments, includes and lines with empty values are left as they are.
+func NewPlanResult(content []byte, target uri.SecretURI, perKey bool) functional.Result[Plan] {
+	if target.Key != "" {
+		return functional.Failure[Plan](fmt.Errorf("the target URI must not have ?key= (keys are taken from the env file)"))
+	}
+
+	linesResult := parser.NewContentLinesResult(parser.PreprocessContent(content))
+	if linesResult.IsFailure() {
+		return functional.Failure[Plan](linesResult.GetError())
+	}
+
+	plan := Plan{Target: target, PerKey: perKey}
+	seen := map[string]int{}
+	for _, line := range linesResult.Unwrap() {
+		if !line.IsKeyValue() {
+			continue
+		}
+		entryResult := line.ToEnvEntry()
+		if entryResult.IsFailure() {
+			return functional.Failure[Plan](entryResult.GetError())
+		}
+		entry := entryResult.Unwrap()
+		value := formatting.UnwrapQuotes(strings.TrimSpace(entry.Value))
+
+		switch {
+		case secret.IsURI(value):
+			continue
+		case !identifierPattern.MatchString(entry.Key):
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("line %d: '%s' is not a valid variable name", line.Number, entry.Key))
+			continue
+		case value == "":
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("line %d: %s has an empty value", line.Number, entry.Key))
+			continue
+		}
+
+		if previous, exists := seen[entry.Key]; exists {
+			return functional.Failure[Plan](fmt.Errorf("line %d: %s is already defined at line %d", line.Number, entry.Key, previous))
+		}
+		seen[entry.Key] = line.Number
+
+		plan.Variables = append(plan.Variables, Variable{
+			Line:    line.Number,
+			Content: line.Content,
+			Key:     entry.Key,
+			Value:   value,
+		})
+	}
+	return functional.Success(plan)
+}
+
+// SecretURI returns the URI that references a variable after the push
+func (p Plan) SecretURI(variable Variable) uri.SecretURI {
+	if !p.PerKey {
+		return p.Target.WithKey(variable.Key)
+	}
+	result := p.Target
+	result.SecretName = perKeySecretName(p.Target, variable.Key)
+	return result
+}
+
+// perKeySecretName names the secret of a single variable after the target secret.
+// Google Cloud secret IDs cannot contain '/', so '_' is used there.
+func perKeySecretName(target uri.SecretURI, key string) string {
+	if target.Platform == uri.GoogleCloudPlatform {
+		return target.SecretName + "_" + key
+	}
+	return target.SecretName + "/" + key
+}
+
+// Edits returns the line edits replacing each value with its secret URI
+func (p Plan) Edits() []parser.LineEdit {
+	return functional.Map(p.Variables, func(variable Variable) parser.LineEdit {
+		return parser.LineEdit{
+			Number: variable.Line,
+			Before: variable.Content,
+			After:  parser.ReplaceLineValue(variable.Content, p.SecretURI(variable).GetUri()),
+		}
+	})
+}
+
+// WritesResult returns the secret values to store. Without PerKey, the variables are merged
+// into the current value of the target secret, keeping its other keys.
+func (p Plan) WritesResult(current functional.Option[string]) functional.Result[[]Write] {
+	if p.PerKey {
+		return functional.Success(functional.Map(p.Variables, func(variable Variable) Write {
+			return Write{URI: p.SecretURI(variable).WithKey(""), Value: variable.Value}
+		}))
+	}
+
+	value := current
+	for _, variable := range p.Variables {
+		mergedResult := secret.SetKeyResult(value, variable.Key, variable.Value)
+		if mergedResult.IsFailure() {
+			return functional.Failure[[]Write](
+				fmt.Errorf("cannot add %s to secret '%s': %w", variable.Key, p.Target.SecretName, mergedResult.GetError()))
+		}
+		value = functional.Some(mergedResult.Unwrap())
+	}
+	return functional.Success([]Write{{URI: p.Target, Value: value.UnwrapOr("{}")}})
+}
@@ -0,0 +1,146 @@
+package push
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/uri"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+)
+
+const legacyEnv = `# Database
+DB_USER=admin
+DB_PASSWORD="p@ss=word"
+API_KEY=sem://aws:secretsmanager/default/api?key=key
+EMPTY=
+export TOKEN=abc
+#include base.env
+`
+
+func target(t *testing.T, raw string) uri.SecretURI {
+	t.Helper()
+	result := uri.ParseResult(raw)
+	if result.IsFailure() {
+		t.Fatalf("invalid URI %s: %v", raw, result.GetError())
+	}
+	return result.Unwrap()
+}
+
+func TestNewPlanResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		target        string
+		wantVariables []Variable
+		wantSkipped   []string
+		wantErr       bool
+	}{
+		{
+			name:    "Plaintext values are collected",
+			content: legacyEnv,
+			target:  "sem://aws:secretsmanager/prod/app",
+			wantVariables: []Variable{
+				{Line: 2, Content: "DB_USER=admin", Key: "DB_USER", Value: "admin"},
+				{Line: 3, Content: `DB_PASSWORD="p@ss=word"`, Key: "DB_PASSWORD", Value: "p@ss=word"},
+			},
+			wantSkipped: []string{
+				"line 5: EMPTY has an empty value",
+				"line 6: 'export TOKEN' is not a valid variable name",
+			},
+		},
+		{
+			name:    "Duplicate keys",
+			content: "A=1\nA=2\n",
+			target:  "sem://aws:secretsmanager/prod/app",
+			wantErr: true,
+		},
+		{
+			name:    "Target with a key",
+			content: "A=1\n",
+			target:  "sem://aws:secretsmanager/prod/app?key=A",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewPlanResult([]byte(tt.content), target(t, tt.target), false)
+			if tt.wantErr {
+				if result.IsSuccess() {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			plan := result.Unwrap()
+			if !reflect.DeepEqual(plan.Variables, tt.wantVariables) {
+				t.Errorf("Variables = %+v, want %+v", plan.Variables, tt.wantVariables)
+			}
+			if !reflect.DeepEqual(plan.Skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %v, want %v", plan.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestPlanEditsAndWrites(t *testing.T) {
+	content := []byte("DB_USER=admin\nDB_PASSWORD='secret'\n")
+
+	tests := []struct {
+		name       string
+		target     string
+		perKey     bool
+		current    functional.Option[string]
+		wantEdits  []parser.LineEdit
+		wantWrites []Write
+	}{
+		{
+			name:    "Bundled into one JSON secret",
+			target:  "sem://aws:secretsmanager/prod/app",
+			current: functional.Some(`{"OTHER":"kept"}`),
+			wantEdits: []parser.LineEdit{
+				{Number: 1, Before: "DB_USER=admin", After: "DB_USER=sem://aws:secretsmanager/prod/app?version=AWSCURRENT&key=DB_USER&region=ap-northeast-1"},
+				{Number: 2, Before: "DB_PASSWORD='secret'", After: "DB_PASSWORD=sem://aws:secretsmanager/prod/app?version=AWSCURRENT&key=DB_PASSWORD&region=ap-northeast-1"},
+			},
+			wantWrites: []Write{
+				{URI: uri.NewSecretURI("aws", "secretsmanager", "prod", "app").WithVersion("AWSCURRENT").WithRegion("ap-northeast-1"),
+					Value: `{"DB_PASSWORD":"secret","DB_USER":"admin","OTHER":"kept"}`},
+			},
+		},
+		{
+			name:    "One secret per key",
+			target:  "sem://googlecloud:secretmanager/project/app",
+			perKey:  true,
+			current: functional.None[string](),
+			wantEdits: []parser.LineEdit{
+				{Number: 1, Before: "DB_USER=admin", After: "DB_USER=sem://googlecloud:secretmanager/project/app_DB_USER?version=latest"},
+				{Number: 2, Before: "DB_PASSWORD='secret'", After: "DB_PASSWORD=sem://googlecloud:secretmanager/project/app_DB_PASSWORD?version=latest"},
+			},
+			wantWrites: []Write{
+				{URI: uri.NewSecretURI("googlecloud", "secretmanager", "project", "app_DB_USER").WithVersion("latest"), Value: "admin"},
+				{URI: uri.NewSecretURI("googlecloud", "secretmanager", "project", "app_DB_PASSWORD").WithVersion("latest"), Value: "secret"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewPlanResult(content, target(t, tt.target), tt.perKey).Unwrap()
+
+			if edits := plan.Edits(); !reflect.DeepEqual(edits, tt.wantEdits) {
+				t.Errorf("Edits() = %+v, want %+v", edits, tt.wantEdits)
+			}
+
+			writesResult := plan.WritesResult(tt.current)
+			if writesResult.IsFailure() {
+				t.Fatalf("unexpected error: %v", writesResult.GetError())
+			}
+			if writes := writesResult.Unwrap(); !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("WritesResult() = %+v, want %+v", writes, tt.wantWrites)
+			}
+		})
+	}
+}
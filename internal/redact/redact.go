@@ -0,0 +1,270 @@
+// Package redact scrubs resolved secret values from text before it is printed.
+// Values and their base64 and URL-encoded forms are replaced wherever they appear.
+// Values that look randomly generated are also replaced when only a substring of
+// MinLength or more characters appears, so truncated copies of keys and tokens do not
+// leak into logs either; substrings of other values, such as words, are left alone.
+package redact
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/text"
+)
+
+// MinLength is the shortest value or substring, in characters, that is redacted.
+// Shorter strings would mask too much unrelated output.
+const MinLength = 6
+
+// Thresholds above which the substrings of a value are redacted as well as the value itself
+const (
+	SubstringMinLength  = 12  // Minimum length of the value in characters
+	SubstringMinEntropy = 3.4 // Minimum Shannon entropy of the value in bits per character
+)
+
+// Mask replaces redacted text
+const Mask = "[REDACTED]"
+
+// namePattern matches the secret URIs and file names that sem prints in its messages,
+// which are kept even when they share characters with a secret value
+var namePattern = regexp.MustCompile(`sem://[^\s'"]+|[^\s'"()]*\.(?:env|ya?ml|json|lock)\b`)
+
+// occurrence is a position of a MinLength-character gram within a known form
+type occurrence struct {
+	form   int
+	offset int
+}
+
+// Redactor knows a set of secret values and removes them from text
+type Redactor struct {
+	mu         sync.RWMutex
+	forms      []string                // Registered values and their encoded forms
+	substrings []bool                  // Whether the substrings of each form are redacted
+	known      map[string]bool         // Registered forms, to skip duplicates
+	grams      map[string][]occurrence // First gram of every form, and every gram of the forms whose substrings are redacted
+}
+
+// New creates an empty redactor
+func New() *Redactor {
+	return &Redactor{
+		known: map[string]bool{},
+		grams: map[string][]occurrence{},
+	}
+}
+
+// defaultRedactor is shared by every package so that values resolved anywhere are scrubbed everywhere
+var defaultRedactor = New()
+
+// Default returns the shared redactor
+func Default() *Redactor {
+	return defaultRedactor
+}
+
+// Register adds secret values to the shared redactor
+func Register(values ...string) {
+	defaultRedactor.Add(values...)
+}
+
+// Message scrubs the known secret values from a message printed by sem using the shared redactor
+func Message(text string) string {
+	return defaultRedactor.Message(text)
+}
+
+// Add registers secret values together with their encoded forms.
+// JSON secrets register each of their leaf values rather than the document, so that
+// key names and punctuation are not masked. Values shorter than MinLength are ignored.
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, value := range values {
+		for _, leaf := range leafValues(value) {
+			substrings := looksRandom(leaf)
+			for _, form := range encodedForms(leaf) {
+				r.addForm(form, substrings)
+			}
+		}
+	}
+}
+
+// looksRandom reports whether a value looks randomly generated (a key, a token or a password)
+// rather than a word or a name, whose substrings would match unrelated text
+func looksRandom(value string) bool {
+	return utf8.RuneCountInString(value) >= SubstringMinLength &&
+		!strings.ContainsAny(value, " \t") &&
+		text.ShannonEntropy(value) >= SubstringMinEntropy
+}
+
+// leafValues returns the string and number values of a JSON object or array, or the value itself
+func leafValues(value string) []string {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return []string{value}
+	}
+
+	switch document.(type) {
+	case map[string]interface{}, []interface{}:
+		return collectLeaves(document, []string{})
+	default:
+		return []string{value}
+	}
+}
+
+// collectLeaves appends the scalar values found in a decoded JSON document
+func collectLeaves(node interface{}, leaves []string) []string {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		for _, child := range typed {
+			leaves = collectLeaves(child, leaves)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			leaves = collectLeaves(child, leaves)
+		}
+	case string:
+		leaves = append(leaves, typed)
+	case json.Number:
+		leaves = append(leaves, typed.String())
+	}
+	return leaves
+}
+
+// encodedForms returns a value with its base64 and URL-encoded forms
+func encodedForms(value string) []string {
+	data := []byte(value)
+	return []string{
+		value,
+		base64.StdEncoding.EncodeToString(data),
+		base64.URLEncoding.EncodeToString(data),
+		url.QueryEscape(value),
+		url.PathEscape(value),
+	}
+}
+
+// addForm indexes a form; the caller holds the lock.
+// Grams start and end on character boundaries, so that multibyte characters are never split.
+func (r *Redactor) addForm(form string, substrings bool) {
+	if utf8.RuneCountInString(form) < MinLength || r.known[form] {
+		return
+	}
+	r.known[form] = true
+	index := len(r.forms)
+	r.forms = append(r.forms, form)
+	r.substrings = append(r.substrings, substrings)
+
+	for offset := 0; offset < len(form); {
+		gram, ok := gramAt(form, offset)
+		if !ok {
+			break
+		}
+		r.grams[gram] = append(r.grams[gram], occurrence{form: index, offset: offset})
+		if !substrings {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(form[offset:])
+		offset += size
+	}
+}
+
+// gramAt returns the MinLength characters of s starting at byte offset i
+func gramAt(s string, i int) (string, bool) {
+	end := i
+	for count := 0; count < MinLength; count++ {
+		if end >= len(s) {
+			return "", false
+		}
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+	return s[i:end], true
+}
+
+// Empty reports whether no value has been registered
+func (r *Redactor) Empty() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.forms) == 0
+}
+
+// String replaces every registered value (or encoded form) in text with Mask, as well as
+// every run of MinLength or more consecutive characters of the values that look random
+func (r *Redactor) String(text string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.forms) == 0 {
+		return text
+	}
+
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); {
+		length := r.matchLength(text, i)
+		if length == 0 {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			result = append(result, text[i:i+size]...)
+			i += size
+			continue
+		}
+		result = append(result, Mask...)
+		i += length
+	}
+	return string(result)
+}
+
+// Message scrubs a message printed by sem like String, keeping the secret URIs and
+// file names it contains so that diagnostics name the right secrets and files
+func (r *Redactor) Message(text string) string {
+	if r.Empty() {
+		return text
+	}
+
+	var result strings.Builder
+	last := 0
+	for _, span := range namePattern.FindAllStringIndex(text, -1) {
+		result.WriteString(r.String(text[last:span[0]]))
+		result.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	result.WriteString(r.String(text[last:]))
+	return result.String()
+}
+
+// matchLength returns the length in bytes of the longest match starting at position i, or 0
+func (r *Redactor) matchLength(text string, i int) int {
+	gram, ok := gramAt(text, i)
+	if !ok {
+		return 0
+	}
+
+	longest := 0
+	for _, occ := range r.grams[gram] {
+		form := r.forms[occ.form]
+		length := len(gram)
+		for i+length < len(text) && occ.offset+length < len(form) && text[i+length] == form[occ.offset+length] {
+			length++
+		}
+		if !r.substrings[occ.form] {
+			// Only the whole form is redacted
+			if length != len(form) {
+				continue
+			}
+		} else {
+			// Stop at the end of the last complete character
+			for i+length < len(text) && !utf8.RuneStart(text[i+length]) {
+				length--
+			}
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+	return longest
+}
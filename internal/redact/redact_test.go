@@ -0,0 +1,173 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+func TestRedactorString(t *testing.T) {
+	secret := "s3cr3t-p@ss/word"
+	redactor := New()
+	redactor.Add(secret, "abc")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Whole value",
+			input: "password is " + secret + ".",
+			want:  "password is " + Mask + ".",
+		},
+		{
+			name:  "Substring of six characters or more",
+			input: "truncated: s3cr3t-p",
+			want:  "truncated: " + Mask,
+		},
+		{
+			name:  "Substring shorter than six characters",
+			input: "s3cr3 is fine",
+			want:  "s3cr3 is fine",
+		},
+		{
+			name:  "Base64 encoded",
+			input: "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(secret)),
+			want:  "Authorization: Basic " + Mask,
+		},
+		{
+			name:  "URL encoded",
+			input: "https://example.com/?p=" + url.QueryEscape(secret),
+			want:  "https://example.com/?p=" + Mask,
+		},
+		{
+			name:  "Short values are not registered",
+			input: "abc def",
+			want:  "abc def",
+		},
+		{
+			name:  "Several occurrences",
+			input: secret + " and " + secret,
+			want:  Mask + " and " + Mask,
+		},
+		{
+			name:  "Unrelated text",
+			input: "nothing to hide here",
+			want:  "nothing to hide here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorJSONSecret(t *testing.T) {
+	// Leaf values of JSON secrets are registered, not key names
+	redactor := New()
+	redactor.Add(`{"username":"administrator","password":"hunter2hunter2","port":543210}`)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "DB_PASSWORD=hunter2hunter2", want: "DB_PASSWORD=" + Mask},
+		{input: "user administrator", want: "user " + Mask},
+		{input: "port 543210", want: "port " + Mask},
+		{input: "missing key password", want: "missing key password"},
+	}
+
+	for _, tt := range tests {
+		if got := redactor.String(tt.input); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	redactor := New()
+	redactor.Add("top-secret-value")
+
+	var out bytes.Buffer
+	writer := NewWriter(&out, redactor)
+
+	// The value is split across two writes
+	writer.Write([]byte("token=top-sec"))
+	writer.Write([]byte("ret-value\npartial top-secret-value"))
+	if got, want := out.String(), "token="+Mask+"\n"; got != want {
+		t.Errorf("after Write: %q, want %q", got, want)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got, want := out.String(), "token="+Mask+"\npartial "+Mask; got != want {
+		t.Errorf("after Flush: %q, want %q", got, want)
+	}
+}
+
+func TestRedactorSubstrings(t *testing.T) {
+	// Substrings are only redacted for values that look randomly generated
+	redactor := New()
+	redactor.Add(`{"environment":"production","api_key":"google-api-key-12345","greeting":"こんにちは世界です"}`)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Whole word value", input: "env=production", want: "env=" + Mask},
+		{name: "Substring of a word value", input: "the product is ready", want: "the product is ready"},
+		{name: "Substring of a random value", input: "key google-api-key", want: "key " + Mask},
+		{name: "Multibyte value", input: "「こんにちは世界です」", want: "「" + Mask + "」"},
+		{name: "Multibyte prefix of a word value", input: "こんにちは世界", want: "こんにちは世界"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorMessage(t *testing.T) {
+	// Secret URIs and file names in sem's own messages are kept
+	redactor := New()
+	redactor.Add("google-api-key-12345", "production")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "Fetching sem://googlecloud:secretmanager/sem-test-project/large_secret?key=api_keys",
+			want:  "Fetching sem://googlecloud:secretmanager/sem-test-project/large_secret?key=api_keys",
+		},
+		{
+			input: "Successfully updated 3 environment variables in .cache.tests_googlecloud_input_key_url.env",
+			want:  "Successfully updated 3 environment variables in .cache.tests_googlecloud_input_key_url.env",
+		},
+		{
+			input: "Reading input file: production.yaml",
+			want:  "Reading input file: production.yaml",
+		},
+		{
+			input: "value google-api-key-12345 for production in '.env'",
+			want:  "value " + Mask + " for " + Mask + " in '.env'",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := redactor.Message(tt.input); got != tt.want {
+			t.Errorf("Message(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
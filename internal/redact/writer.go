@@ -0,0 +1,64 @@
+package redact
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxBuffered is the size at which a partial line is written even without a newline
+const maxBuffered = 64 * 1024
+
+// Writer scrubs secret values from everything written to it before passing it on.
+// Output is processed line by line so that a value split across two writes is still found;
+// call Flush to write a trailing partial line.
+type Writer struct {
+	mu       sync.Mutex
+	out      io.Writer
+	redactor *Redactor
+	buffer   []byte
+}
+
+// NewWriter creates a Writer that writes scrubbed output to out
+func NewWriter(out io.Writer, redactor *Redactor) *Writer {
+	return &Writer{out: out, redactor: redactor}
+}
+
+// Write buffers p and writes every complete line after scrubbing it
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+
+	end := bytes.LastIndexByte(w.buffer, '\n') + 1
+	if end == 0 && len(w.buffer) >= maxBuffered {
+		end = len(w.buffer)
+	}
+	if end > 0 {
+		if err := w.writeScrubbed(w.buffer[:end]); err != nil {
+			return 0, err
+		}
+		w.buffer = append(w.buffer[:0], w.buffer[end:]...)
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	err := w.writeScrubbed(w.buffer)
+	w.buffer = w.buffer[:0]
+	return err
+}
+
+// writeScrubbed writes data after removing secret values; the caller holds the lock
+func (w *Writer) writeScrubbed(data []byte) error {
+	_, err := io.WriteString(w.out, w.redactor.String(string(data)))
+	return err
+}
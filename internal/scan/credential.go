@@ -0,0 +1,97 @@
+package scan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/parser"
+	"github.com/gumi-tsd/secret-env-manager/internal/secret"
+)
+
+// TokenFormat is a well-known credential format recognized by its shape
+type TokenFormat struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// TokenFormats lists the credential formats recognized in plaintext values
+var TokenFormats = []TokenFormat{
+	{Name: "AWS access key ID", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{Name: "Slack token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{Name: "Stripe secret key", Pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}`)},
+	{Name: "Google API key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "JSON Web Token", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{Name: "private key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+// credentialNamePattern matches variable names that usually hold credentials
+var credentialNamePattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTH)`)
+
+// MatchTokenFormat returns the name of the credential format a value has, if any
+func MatchTokenFormat(value string) functional.Option[string] {
+	for _, format := range TokenFormats {
+		if format.Pattern.MatchString(value) {
+			return functional.Some(format.Name)
+		}
+	}
+	return functional.None[string]()
+}
+
+// LooksLikeCredential returns why a literal value looks like a credential, if it does.
+// Secret URIs, empty values and ${VAR} references are never credentials.
+func LooksLikeCredential(key, value string) functional.Option[string] {
+	if value == "" || secret.IsURI(value) || strings.HasPrefix(value, "${") {
+		return functional.None[string]()
+	}
+	if format := MatchTokenFormat(value); format.IsSome() {
+		return functional.Some(fmt.Sprintf("the value matches the %s format", format.Unwrap()))
+	}
+	if credentialNamePattern.MatchString(key) {
+		return functional.Some("the name suggests a credential")
+	}
+	return functional.None[string]()
+}
+
+// PlaintextCredential is a literal KEY=value line of an env file that looks like a credential
+type PlaintextCredential struct {
+	Path   string
+	Line   int
+	Key    string
+	Reason string
+}
+
+// String formats the finding without the value
+func (c PlaintextCredential) String() string {
+	return fmt.Sprintf("%s:%d: %s has a literal value (%s); store it with 'sem push' or 'sem put'", c.Path, c.Line, c.Key, c.Reason)
+}
+
+// FindPlaintextCredentials returns the KEY=value lines of env file content whose literal value looks like a credential
+func FindPlaintextCredentials(path string, content []byte) []PlaintextCredential {
+	found := []PlaintextCredential{}
+
+	linesResult := parser.NewContentLinesResult(parser.PreprocessContent(content))
+	if linesResult.IsFailure() {
+		return found
+	}
+
+	for _, line := range linesResult.Unwrap() {
+		if !line.IsKeyValue() {
+			continue
+		}
+		entryResult := line.ToEnvEntry()
+		if entryResult.IsFailure() {
+			continue
+		}
+		entry := entryResult.Unwrap()
+		value := formatting.UnwrapQuotes(strings.TrimSpace(entry.Value))
+
+		if reason := LooksLikeCredential(entry.Key, value); reason.IsSome() {
+			found = append(found, PlaintextCredential{Path: path, Line: line.Number, Key: entry.Key, Reason: reason.Unwrap()})
+		}
+	}
+	return found
+}
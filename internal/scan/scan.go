@@ -0,0 +1,140 @@
+// Package scan finds secrets that are, or could end up, committed to git:
+// cache files in the index or history, generated files missing from the ignore rules,
+// and plaintext copies of resolved secret values in tracked files.
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/fileio"
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/profile"
+	"github.com/gumi-tsd/secret-env-manager/internal/provenance"
+	"github.com/gumi-tsd/secret-env-manager/internal/redact"
+)
+
+// cacheFilePrefix starts the name of every cache file and metadata file
+const cacheFilePrefix = ".cache"
+
+// MaxFileSize is the size above which tracked files are not searched for secret values
+const MaxFileSize = 1 << 20
+
+// IsGeneratedPath reports whether a path is a file written by sem that must not be committed:
+// a cache file, its metadata file or the active environment file
+func IsGeneratedPath(path string) bool {
+	name := filepath.Base(filepath.FromSlash(path))
+	return strings.HasPrefix(name, cacheFilePrefix+".") || name == profile.ActiveEnvFile
+}
+
+// IsCacheFile reports whether a path is a cache file holding secret values (not its metadata file)
+func IsCacheFile(path string) bool {
+	name := filepath.Base(filepath.FromSlash(path))
+	return strings.HasPrefix(name, cacheFilePrefix+".") && !strings.HasSuffix(name, provenance.FileSuffix)
+}
+
+// GeneratedPaths returns the files sem writes for the given environments, plus the active environment file
+func GeneratedPaths(environments []profile.Environment) []string {
+	paths := []string{profile.ActiveEnvFile}
+	seen := map[string]bool{profile.ActiveEnvFile: true}
+	for _, environment := range environments {
+		for _, path := range []string{environment.CacheFile, provenance.FileName(environment.CacheFile)} {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// CacheFileCandidates returns the cache files sem may have written for tracked input files:
+// next to the input file when run from its directory, and in the current directory when run with its path
+func CacheFileCandidates(trackedPaths []string) []string {
+	candidates := []string{}
+	seen := map[string]bool{}
+	for _, path := range trackedPaths {
+		if !profile.IsInputFile(path) || IsGeneratedPath(path) {
+			continue
+		}
+		dir, name := filepath.Split(filepath.FromSlash(path))
+		for _, candidate := range []string{filepath.Join(dir, fileio.GenerateCacheFileName(name)), fileio.GenerateCacheFileName(path)} {
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// SecretValues keeps the variables of a cache file that were resolved from a secret URI,
+// dropping plain values copied from the input file. Without metadata every variable is kept.
+func SecretValues(variables map[string]string, metadata functional.Option[provenance.Metadata]) map[string]string {
+	if metadata.IsNone() {
+		return variables
+	}
+	secrets := map[string]string{}
+	for key, value := range variables {
+		if metadata.Unwrap().IsSecret(key) {
+			secrets[key] = value
+		}
+	}
+	return secrets
+}
+
+// CommittedCaches returns the generated files found among committed paths,
+// keeping the first commit that added each of them
+func CommittedCaches(paths []fileio.CommittedPath) []fileio.CommittedPath {
+	found := []fileio.CommittedPath{}
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if IsGeneratedPath(path.Path) && !seen[path.Path] {
+			seen[path.Path] = true
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// Match is a line of a tracked file containing a resolved secret value
+type Match struct {
+	Path     string
+	Line     int
+	Variable string // Variable whose value was found (the value itself is never reported)
+}
+
+// String formats the match as path:line with the variable name
+func (m Match) String() string {
+	return fmt.Sprintf("%s:%d contains the value of %s", m.Path, m.Line, m.Variable)
+}
+
+// FindValues returns the lines of content that contain one of the values.
+// Values shorter than redact.MinLength are skipped to avoid reporting common words.
+// Binary content is not searched.
+func FindValues(path string, content []byte, values map[string]string) []Match {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return []Match{}
+	}
+
+	variables := make([]string, 0, len(values))
+	for variable, value := range values {
+		if len(value) >= redact.MinLength {
+			variables = append(variables, variable)
+		}
+	}
+	sort.Strings(variables)
+
+	matches := []Match{}
+	for index, line := range strings.Split(string(content), "\n") {
+		for _, variable := range variables {
+			if strings.Contains(line, values[variable]) {
+				matches = append(matches, Match{Path: path, Line: index + 1, Variable: variable})
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseResult parses a schema file written in YAML or JSON.
+//
+// Two formats are accepted. The simple format maps each variable to its rules:
+//
+//	DB_PASSWORD:
+//	  required: true
+//	  min_length: 16
+//	DB_PORT:
+//	  type: port
+//
+// A JSON Schema document (an object with "properties") is also accepted, using
+// the "required", "pattern", "minLength", "type" and "format" keywords. Formats without
+// a matching value type (e.g. "email" or "date-time") are ignored.
+func ParseResult(path string, content []byte) functional.Result[Schema] {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return functional.Failure[Schema](fmt.Errorf("%s: %w", path, err))
+	}
+
+	if len(document.Content) == 0 {
+		return functional.Success(New())
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return functional.Failure[Schema](fmt.Errorf("%s:%d: schema must be a mapping", path, root.Line))
+	}
+
+	parser := schemaParser{path: path}
+	if properties := mappingValue(root, "properties"); properties != nil {
+		return parser.parseJSONSchema(root, properties)
+	}
+	return parser.parseSimple(root)
+}
+
+// schemaParser converts schema documents into rules
+type schemaParser struct {
+	path string
+}
+
+// errorf creates an error prefixed with the position of a node
+func (p schemaParser) errorf(node *yaml.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", p.path, node.Line, fmt.Sprintf(format, args...))
+}
+
+// parseSimple parses the simple "KEY: {rules}" format
+func (p schemaParser) parseSimple(root *yaml.Node) functional.Result[Schema] {
+	rules := []Rule{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, ruleNode := root.Content[i], root.Content[i+1]
+		if ruleNode.Kind != yaml.MappingNode {
+			return functional.Failure[Schema](p.errorf(ruleNode, "rules for '%s' must be a mapping", keyNode.Value))
+		}
+
+		rule := Rule{Key: keyNode.Value, Source: fmt.Sprintf("%s:%d", p.path, keyNode.Line)}
+		for j := 0; j+1 < len(ruleNode.Content); j += 2 {
+			optionNode, valueNode := ruleNode.Content[j], ruleNode.Content[j+1]
+			var err error
+			switch optionNode.Value {
+			case "required":
+				err = valueNode.Decode(&rule.Required)
+			case "pattern":
+				err = valueNode.Decode(&rule.Pattern)
+			case "min_length":
+				err = valueNode.Decode(&rule.MinLength)
+			case "type":
+				err = p.decodeType(valueNode, &rule.Type)
+			case "description":
+				// Documentation only
+			default:
+				err = fmt.Errorf("unknown rule '%s'", optionNode.Value)
+			}
+			if err != nil {
+				return functional.Failure[Schema](p.errorf(optionNode, "%s: %v", rule.Key, err))
+			}
+		}
+
+		if err := validateRule(rule); err != nil {
+			return functional.Failure[Schema](p.errorf(keyNode, "%v", err))
+		}
+		rules = append(rules, rule)
+	}
+	return functional.Success(New(rules...))
+}
+
+// parseJSONSchema parses the supported subset of a JSON Schema object definition
+func (p schemaParser) parseJSONSchema(root, properties *yaml.Node) functional.Result[Schema] {
+	var requiredKeys []string
+	if requiredNode := mappingValue(root, "required"); requiredNode != nil {
+		if err := requiredNode.Decode(&requiredKeys); err != nil {
+			return functional.Failure[Schema](p.errorf(requiredNode, "'required' must be a list of property names"))
+		}
+	}
+	required := map[string]bool{}
+	for _, key := range requiredKeys {
+		required[key] = true
+	}
+
+	if properties.Kind != yaml.MappingNode {
+		return functional.Failure[Schema](p.errorf(properties, "'properties' must be an object"))
+	}
+
+	rules := []Rule{}
+	defined := map[string]bool{}
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		keyNode, propertyNode := properties.Content[i], properties.Content[i+1]
+		if propertyNode.Kind != yaml.MappingNode {
+			return functional.Failure[Schema](p.errorf(propertyNode, "property '%s' must be an object", keyNode.Value))
+		}
+
+		rule := Rule{
+			Key:      keyNode.Value,
+			Required: required[keyNode.Value],
+			Source:   fmt.Sprintf("%s:%d", p.path, keyNode.Line),
+		}
+		for j := 0; j+1 < len(propertyNode.Content); j += 2 {
+			keywordNode, valueNode := propertyNode.Content[j], propertyNode.Content[j+1]
+			var err error
+			switch keywordNode.Value {
+			case "pattern":
+				err = valueNode.Decode(&rule.Pattern)
+			case "minLength":
+				err = valueNode.Decode(&rule.MinLength)
+			case "type":
+				// "string" only constrains the type when no format is given
+				if valueNode.Value != string(TypeString) || rule.Type == TypeAny {
+					err = p.decodeType(valueNode, &rule.Type)
+				}
+			case "format":
+				// Formats are annotations in JSON Schema, so unknown ones are not an error
+				if formatResult := ParseType(valueNode.Value); formatResult.IsSuccess() && formatResult.Unwrap() != TypeString {
+					rule.Type = formatResult.Unwrap()
+				}
+			}
+			if err != nil {
+				return functional.Failure[Schema](p.errorf(keywordNode, "%s: %v", rule.Key, err))
+			}
+		}
+
+		if err := validateRule(rule); err != nil {
+			return functional.Failure[Schema](p.errorf(keyNode, "%v", err))
+		}
+		defined[rule.Key] = true
+		rules = append(rules, rule)
+	}
+
+	// Required properties without a definition still need to be present
+	for _, key := range requiredKeys {
+		if !defined[key] {
+			rules = append(rules, Rule{Key: key, Required: true, Source: p.path})
+		}
+	}
+
+	return functional.Success(New(rules...))
+}
+
+// decodeType decodes a type name node into a ValueType
+func (p schemaParser) decodeType(node *yaml.Node, target *ValueType) error {
+	typeResult := ParseType(node.Value)
+	if typeResult.IsFailure() {
+		return typeResult.GetError()
+	}
+	*target = typeResult.Unwrap()
+	return nil
+}
+
+// mappingValue returns the value node for a key in a mapping node, or nil when absent
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
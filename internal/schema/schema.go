@@ -0,0 +1,131 @@
+// Package schema validates resolved environment variables against the shape an application expects
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+// ValueType represents the expected type of a resolved value
+type ValueType string
+
+// Supported value types
+const (
+	TypeAny    ValueType = ""
+	TypeString ValueType = "string"
+	TypeInt    ValueType = "int"
+	TypeNumber ValueType = "number"
+	TypePort   ValueType = "port"
+	TypeURL    ValueType = "url"
+	TypeBool   ValueType = "bool"
+)
+
+// supportedTypes lists the value types accepted in schema definitions
+var supportedTypes = []ValueType{TypeString, TypeInt, TypeNumber, TypePort, TypeURL, TypeBool}
+
+// typeAliases maps JSON Schema type and format names to value types
+var typeAliases = map[string]ValueType{
+	"integer": TypeInt,
+	"uri":     TypeURL,
+	"boolean": TypeBool,
+}
+
+// Rule describes the constraints on a single environment variable
+type Rule struct {
+	Key       string    // Environment variable name in the resolved map
+	Required  bool      // The variable must be present with a non-empty value
+	Pattern   string    // Regular expression the value must match
+	MinLength int       // Minimum length of the value
+	Type      ValueType // Expected value type
+	Source    string    // Location where the rule was defined (e.g. "schema.yaml:3")
+}
+
+// Schema is an ordered collection of rules
+type Schema struct {
+	Rules []Rule
+}
+
+// New creates a Schema from the given rules
+func New(rules ...Rule) Schema {
+	return Schema{Rules: rules}
+}
+
+// IsEmpty checks if the schema has no rules
+func (s Schema) IsEmpty() bool {
+	return len(s.Rules) == 0
+}
+
+// Merge returns a new Schema containing the rules of both schemas
+func (s Schema) Merge(other Schema) Schema {
+	rules := make([]Rule, 0, len(s.Rules)+len(other.Rules))
+	rules = append(rules, s.Rules...)
+	rules = append(rules, other.Rules...)
+	return New(rules...)
+}
+
+// ParseType converts a type name into a ValueType
+func ParseType(name string) functional.Result[ValueType] {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == string(TypeAny) {
+		return functional.Success(TypeAny)
+	}
+	if alias, exists := typeAliases[normalized]; exists {
+		return functional.Success(alias)
+	}
+
+	names := make([]string, len(supportedTypes))
+	for i, supported := range supportedTypes {
+		if normalized == string(supported) {
+			return functional.Success(supported)
+		}
+		names[i] = string(supported)
+	}
+	return functional.Failure[ValueType](
+		fmt.Errorf("unknown type '%s' (supported: %s)", name, strings.Join(names, ", ")))
+}
+
+// validateRule checks that a rule is well-formed (valid pattern and length)
+func validateRule(rule Rule) error {
+	if rule.Pattern != "" {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern for '%s': %w", rule.Key, err)
+		}
+	}
+	if rule.MinLength < 0 {
+		return fmt.Errorf("min_length for '%s' must not be negative", rule.Key)
+	}
+	return nil
+}
+
+// FromEntriesResult builds a schema from the validation options of input file entries.
+// Required options are not included, as they are checked against the current environment separately.
+func FromEntriesResult(entries []env.Entry) functional.Result[Schema] {
+	rules := []Rule{}
+	for _, entry := range entries {
+		if !entry.Options.HasValidation() {
+			continue
+		}
+
+		typeResult := ParseType(entry.Options.Type)
+		if typeResult.IsFailure() {
+			return functional.Failure[Schema](fmt.Errorf("%s: %w", entry.Location(), typeResult.GetError()))
+		}
+
+		rule := Rule{
+			Key:       entry.Key,
+			Pattern:   entry.Options.Pattern,
+			MinLength: entry.Options.MinLength,
+			Type:      typeResult.Unwrap(),
+			Source:    entry.Location(),
+		}
+		if err := validateRule(rule); err != nil {
+			return functional.Failure[Schema](fmt.Errorf("%s: %w", entry.Location(), err))
+		}
+		rules = append(rules, rule)
+	}
+	return functional.Success(New(rules...))
+}
@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/model/env"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		values   map[string]string
+		expected []string
+	}{
+		{"Required present", Rule{Key: "A", Required: true}, map[string]string{"A": "x"}, nil},
+		{"Required missing", Rule{Key: "A", Required: true}, map[string]string{}, []string{"required value is missing or empty"}},
+		{"Required empty", Rule{Key: "A", Required: true}, map[string]string{"A": ""}, []string{"required value is missing or empty"}},
+		{"Optional missing skips other rules", Rule{Key: "A", Type: TypePort}, map[string]string{}, nil},
+		{"Min length", Rule{Key: "A", MinLength: 4}, map[string]string{"A": "abc"}, []string{"length 3 is shorter than the minimum of 4"}},
+		{"Pattern match", Rule{Key: "A", Pattern: "^sk_"}, map[string]string{"A": "sk_live"}, nil},
+		{"Pattern mismatch", Rule{Key: "A", Pattern: "^sk_"}, map[string]string{"A": "pk_live"}, []string{"does not match pattern ^sk_"}},
+		{"Int", Rule{Key: "A", Type: TypeInt}, map[string]string{"A": "-12"}, nil},
+		{"Not int", Rule{Key: "A", Type: TypeInt}, map[string]string{"A": "1.5"}, []string{"is not an integer"}},
+		{"Port", Rule{Key: "A", Type: TypePort}, map[string]string{"A": "5432"}, nil},
+		{"Port out of range", Rule{Key: "A", Type: TypePort}, map[string]string{"A": "70000"}, []string{"is not a valid port number (1-65535)"}},
+		{"URL", Rule{Key: "A", Type: TypeURL}, map[string]string{"A": "https://example.com/path"}, nil},
+		{"URL without host", Rule{Key: "A", Type: TypeURL}, map[string]string{"A": "example.com"}, []string{"is not a valid URL (scheme and host are required)"}},
+		{"Number", Rule{Key: "A", Type: TypeNumber}, map[string]string{"A": "-1.5e3"}, nil},
+		{"Not number", Rule{Key: "A", Type: TypeNumber}, map[string]string{"A": "NaN"}, []string{"is not a number"}},
+		{"Bool", Rule{Key: "A", Type: TypeBool}, map[string]string{"A": "yes"}, []string{"is not a boolean"}},
+		{
+			"Multiple violations",
+			Rule{Key: "A", MinLength: 10, Pattern: "^https", Type: TypeURL},
+			map[string]string{"A": "ftp"},
+			[]string{
+				"length 3 is shorter than the minimum of 10",
+				"does not match pattern ^https",
+				"is not a valid URL (scheme and host are required)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := Validate(New(tt.rule), tt.values)
+			messages := []string{}
+			for _, violation := range violations {
+				messages = append(messages, violation.Message)
+			}
+			if len(tt.expected) == 0 {
+				tt.expected = []string{}
+			}
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("Validate() messages = %v, want %v", messages, tt.expected)
+			}
+		})
+	}
+}
+
+func TestViolationDoesNotContainValue(t *testing.T) {
+	secret := "super-secret-value"
+	violations := Validate(New(Rule{Key: "TOKEN", Pattern: "^sk_", Type: TypeInt}), map[string]string{"TOKEN": secret})
+	if output := FormatViolations(violations); strings.Contains(output, secret) {
+		t.Errorf("FormatViolations() leaked the value: %q", output)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    []Rule
+		errContains string
+	}{
+		{
+			name:    "Simple format",
+			content: "DB_PASSWORD:\n  required: true\n  min_length: 16\nDB_PORT:\n  type: port\n",
+			expected: []Rule{
+				{Key: "DB_PASSWORD", Required: true, MinLength: 16, Source: "schema.yaml:1"},
+				{Key: "DB_PORT", Type: TypePort, Source: "schema.yaml:4"},
+			},
+		},
+		{
+			name: "JSON Schema",
+			content: `{"type": "object", "required": ["API_URL", "TOKEN"], "properties": {
+  "API_URL": {"type": "string", "format": "uri"},
+  "PORT": {"type": "integer"},
+  "KEY": {"type": "string", "pattern": "^k", "minLength": 2}
+}}`,
+			expected: []Rule{
+				{Key: "API_URL", Required: true, Type: TypeURL, Source: "schema.yaml:2"},
+				{Key: "PORT", Type: TypeInt, Source: "schema.yaml:3"},
+				{Key: "KEY", Type: TypeString, Pattern: "^k", MinLength: 2, Source: "schema.yaml:4"},
+				{Key: "TOKEN", Required: true, Source: "schema.yaml"},
+			},
+		},
+		{
+			name: "JSON Schema with unknown formats and numbers",
+			content: `{"properties": {
+  "ADMIN_EMAIL": {"type": "string", "format": "email"},
+  "RATIO": {"type": "number"},
+  "STARTS_AT": {"format": "date-time"},
+  "PORT": {"type": "integer", "format": "int32"}
+}}`,
+			expected: []Rule{
+				{Key: "ADMIN_EMAIL", Type: TypeString, Source: "schema.yaml:2"},
+				{Key: "RATIO", Type: TypeNumber, Source: "schema.yaml:3"},
+				{Key: "STARTS_AT", Source: "schema.yaml:4"},
+				{Key: "PORT", Type: TypeInt, Source: "schema.yaml:5"},
+			},
+		},
+		{
+			name:        "Unknown type",
+			content:     "PORT:\n  type: float\n",
+			errContains: "schema.yaml:2: PORT: unknown type 'float'",
+		},
+		{
+			name:        "Unknown rule",
+			content:     "PORT:\n  maximum: 10\n",
+			errContains: "unknown rule 'maximum'",
+		},
+		{
+			name:        "Invalid pattern",
+			content:     "KEY:\n  pattern: \"[\"\n",
+			errContains: "invalid pattern for 'KEY'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseResult("schema.yaml", []byte(tt.content))
+
+			if tt.errContains != "" {
+				if result.IsSuccess() {
+					t.Fatalf("expected error, got %+v", result.Unwrap())
+				}
+				if !strings.Contains(result.GetError().Error(), tt.errContains) {
+					t.Errorf("error %q does not contain %q", result.GetError().Error(), tt.errContains)
+				}
+				return
+			}
+
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if !reflect.DeepEqual(result.Unwrap().Rules, tt.expected) {
+				t.Errorf("rules = %+v, want %+v", result.Unwrap().Rules, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromEntriesResult(t *testing.T) {
+	entries := []env.Entry{
+		env.NewEntry(1, "PLAIN", "value"),
+		env.NewEntry(2, "PORT", "").WithSource("app.yaml").
+			WithOptions(env.EntryOptions{Required: true, Type: "port", MinLength: 2}),
+	}
+
+	result := FromEntriesResult(entries)
+	if result.IsFailure() {
+		t.Fatalf("unexpected error: %v", result.GetError())
+	}
+
+	expected := []Rule{{Key: "PORT", Type: TypePort, MinLength: 2, Source: "app.yaml:2"}}
+	if !reflect.DeepEqual(result.Unwrap().Rules, expected) {
+		t.Errorf("rules = %+v, want %+v", result.Unwrap().Rules, expected)
+	}
+
+	invalid := []env.Entry{env.NewEntry(3, "X", "").WithOptions(env.EntryOptions{Type: "float"})}
+	if FromEntriesResult(invalid).IsSuccess() {
+		t.Errorf("expected error for unknown type")
+	}
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation describes a resolved value that does not satisfy a rule.
+// Messages never include the value itself, as it is usually a secret.
+type Violation struct {
+	Key     string
+	Message string
+	Source  string
+}
+
+// String formats the violation for display
+func (v Violation) String() string {
+	if v.Source == "" {
+		return fmt.Sprintf("%s: %s", v.Key, v.Message)
+	}
+	return fmt.Sprintf("%s: %s (rule at %s)", v.Key, v.Message, v.Source)
+}
+
+// Validate checks the resolved values against every rule in the schema.
+// Rules other than required are only applied to variables that have a value.
+func Validate(s Schema, values map[string]string) []Violation {
+	violations := []Violation{}
+	for _, rule := range s.Rules {
+		violations = append(violations, checkRule(rule, values)...)
+	}
+	return violations
+}
+
+// checkRule checks a single rule against the resolved values
+func checkRule(rule Rule, values map[string]string) []Violation {
+	violation := func(format string, args ...interface{}) Violation {
+		return Violation{Key: rule.Key, Message: fmt.Sprintf(format, args...), Source: rule.Source}
+	}
+
+	value, exists := values[rule.Key]
+	if !exists || value == "" {
+		if rule.Required {
+			return []Violation{violation("required value is missing or empty")}
+		}
+		return nil
+	}
+
+	violations := []Violation{}
+	if rule.MinLength > 0 {
+		if length := utf8.RuneCountInString(value); length < rule.MinLength {
+			violations = append(violations, violation("length %d is shorter than the minimum of %d", length, rule.MinLength))
+		}
+	}
+	if rule.Pattern != "" {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			violations = append(violations, violation("invalid pattern: %v", err))
+		} else if !pattern.MatchString(value) {
+			violations = append(violations, violation("does not match pattern %s", rule.Pattern))
+		}
+	}
+	if message := checkType(rule.Type, value); message != "" {
+		violations = append(violations, violation("%s", message))
+	}
+	return violations
+}
+
+// checkType checks a value against the expected type, returning a message when it does not conform
+func checkType(valueType ValueType, value string) string {
+	switch valueType {
+	case TypeInt:
+		if _, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			return "is not an integer"
+		}
+	case TypeNumber:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "is not a number"
+		}
+	case TypePort:
+		port, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || port < 1 || port > 65535 {
+			return "is not a valid port number (1-65535)"
+		}
+	case TypeURL:
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return "is not a valid URL (scheme and host are required)"
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return "is not a boolean"
+		}
+	}
+	return ""
+}
+
+// FormatViolations formats violations for display, one per line
+func FormatViolations(violations []Violation) string {
+	lines := make([]string, len(violations))
+	for i, violation := range violations {
+		lines[i] = "  " + violation.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,8 @@
 package secret
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -30,6 +32,9 @@ var (
 
 	// ErrKeyNotFound indicates that the requested key was not found in the secret
 	ErrKeyNotFound = errors.New("key not found in secret")
+
+	// ErrNotJSONObject indicates that a key cannot be set because the secret is not a JSON object
+	ErrNotJSONObject = errors.New("secret value is not a JSON object")
 )
 
 // ValueOptions configures how secrets are processed
@@ -201,3 +206,26 @@ func CombinePaths(segments ...string) string {
 		return s != ""
 	}), ".")
 }
+
+// SetKeyResult sets a key of a JSON object secret to a string value, keeping its other keys.
+// A missing secret is treated as an empty object.
+func SetKeyResult(current functional.Option[string], key string, value string) functional.Result[string] {
+	object := map[string]interface{}{}
+	if current.IsSome() && strings.TrimSpace(current.Unwrap()) != "" {
+		// Numbers are kept as written instead of being converted to floats
+		decoder := json.NewDecoder(strings.NewReader(current.Unwrap()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil || object == nil {
+			return functional.Failure[string](ErrNotJSONObject)
+		}
+	}
+	object[key] = value
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(object); err != nil {
+		return functional.Failure[string](fmt.Errorf("failed to encode secret: %w", err))
+	}
+	return functional.Success(strings.TrimSuffix(buffer.String(), "\n"))
+}
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 )
 
 func TestIsURI(t *testing.T) {
@@ -543,3 +545,68 @@ func TestParseValueWithOptionsResult(t *testing.T) {
 		})
 	}
 }
+
+func TestSetKeyResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     functional.Option[string]
+		key         string
+		value       string
+		want        string
+		expectedErr error
+	}{
+		{
+			name:    "Missing secret",
+			current: functional.None[string](),
+			key:     "password",
+			value:   "secret",
+			want:    `{"password":"secret"}`,
+		},
+		{
+			name:    "Add a key",
+			current: functional.Some(`{"user": "admin", "port": 5432}`),
+			key:     "password",
+			value:   "secret",
+			want:    `{"password":"secret","port":5432,"user":"admin"}`,
+		},
+		{
+			name:    "Replace a key",
+			current: functional.Some(`{"user": "admin", "password": "old"}`),
+			key:     "password",
+			value:   "new&<value>",
+			want:    `{"password":"new&<value>","user":"admin"}`,
+		},
+		{
+			name:        "Plain text secret",
+			current:     functional.Some("plain text value"),
+			key:         "password",
+			value:       "secret",
+			expectedErr: ErrNotJSONObject,
+		},
+		{
+			name:        "JSON array secret",
+			current:     functional.Some(`["a", "b"]`),
+			key:         "password",
+			value:       "secret",
+			expectedErr: ErrNotJSONObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SetKeyResult(tt.current, tt.key, tt.value)
+			if tt.expectedErr != nil {
+				if !errors.Is(result.GetError(), tt.expectedErr) {
+					t.Fatalf("SetKeyResult() error = %v, want %v", result.GetError(), tt.expectedErr)
+				}
+				return
+			}
+			if result.IsFailure() {
+				t.Fatalf("unexpected error: %v", result.GetError())
+			}
+			if got := result.Unwrap(); got != tt.want {
+				t.Errorf("SetKeyResult() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
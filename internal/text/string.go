@@ -3,6 +3,7 @@ package text
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"unicode"
 
@@ -261,3 +262,60 @@ func EndsWith(s, suffix string) bool {
 func Split(s, sep string) []string {
 	return strings.Split(s, sep)
 }
+
+// Distance returns the Levenshtein edit distance between two strings
+func Distance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current := make([]int, len(target)+1)
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(target)]
+}
+
+// Closest returns the candidate nearest to s, ignoring case, when it is close enough
+// to be a likely typo (at most 2 edits, or a third of the length of s for longer strings)
+func Closest(s string, candidates []string) functional.Option[string] {
+	best := functional.None[string]()
+	bestDistance := max(2, len([]rune(s))/3) + 1
+	for _, candidate := range candidates {
+		if d := Distance(strings.ToLower(s), strings.ToLower(candidate)); d < bestDistance {
+			best = functional.Some(candidate)
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// ShannonEntropy returns the entropy of a string in bits per character
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -1132,3 +1132,47 @@ func TestJoinNonEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"secretmanager", "secretsmanager", 1},
+		{"kitten", "sitting", 3},
+		{"region", "regoin", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"version", "region", "key", "expand", "prefix"}
+
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"Typo", "regoin", "region"},
+		{"Case only", "KEY", "key"},
+		{"Missing letter", "verson", "version"},
+		{"Too different", "stage", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Closest(tt.s, candidates).UnwrapOr(""); got != tt.want {
+				t.Errorf("Closest(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
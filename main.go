@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/gumi-tsd/secret-env-manager/cmd"
+	"github.com/gumi-tsd/secret-env-manager/internal/audit"
 	"github.com/gumi-tsd/secret-env-manager/internal/formatting"
 	"github.com/gumi-tsd/secret-env-manager/internal/functional"
 	"github.com/gumi-tsd/secret-env-manager/internal/logging"
@@ -36,7 +37,22 @@ var (
 	}
 	endpointURLFlag = &cli.StringFlag{
 		Name:  "endpoint-url",
-		Usage: "Custom endpoint URL for AWS Secrets Manager",
+		Usage: "Custom endpoint URL for AWS Secrets Manager (combine with --aws-credentials static for LocalStack)",
+		Value: "",
+	}
+	awsCredentialsFlag = &cli.StringFlag{
+		Name:  "aws-credentials",
+		Usage: "Credential source for AWS Secrets Manager: default (the AWS profile of the secret) or static (dummy credentials for emulators)",
+		Value: "",
+	}
+	endpointsFileFlag = &cli.StringFlag{
+		Name:  "endpoints-file",
+		Usage: "Endpoint configuration file setting endpoints and credential sources per platform, account and region (default: .sem-endpoints.yaml when present)",
+		Value: "",
+	}
+	gcpEndpointFlag = &cli.StringFlag{
+		Name:  "gcp-endpoint",
+		Usage: "Custom endpoint (host:port) for Google Cloud Secret Manager; localhost endpoints use plaintext gRPC without credentials",
 		Value: "",
 	}
 	noQuotesFlag = &cli.BoolFlag{
@@ -50,6 +66,146 @@ var (
 		Usage: "Don't automatically expand JSON values into separate environment variables",
 		Value: false,
 	}
+	expandFlag = &cli.StringFlag{
+		Name:    "expand",
+		Usage:   "JSON expansion options, e.g. 'depth:1,sep:__,case:upper,include:db_*,arrays:join,sanitize' (overridable per URI with ?expand=)",
+		Value:   "",
+		EnvVars: []string{"SEM_EXPAND"},
+	}
+	envFlag = &cli.StringFlag{
+		Name:  "env",
+		Usage: "Named environment to use (resolves to <env>.env, .yaml, .yml or .json and its cache file; cannot be combined with --input)",
+		Value: "",
+	}
+	onlyUnsetFlag = &cli.BoolFlag{
+		Name:  "only-unset",
+		Usage: "Output only variables that are not already set in the current environment",
+		Value: false,
+	}
+	schemaFlag = &cli.StringFlag{
+		Name:  "schema",
+		Usage: "Schema file (YAML or JSON) used to validate resolved values",
+		Value: "",
+	}
+	strictFlag = &cli.BoolFlag{
+		Name:  "strict",
+		Usage: "Fail when a variable is produced by more than one entry, including redefined plain values",
+		Value: false,
+	}
+	lenientURIsFlag = &cli.BoolFlag{
+		Name:  "lenient-uris",
+		Usage: "Only warn about sem:// values that are not valid secret URIs instead of failing",
+		Value: false,
+	}
+	lockedFlag = &cli.BoolFlag{
+		Name:  "locked",
+		Usage: "Fetch the secret versions pinned in the lockfile ($input.lock) instead of the versions in the input file",
+		Value: false,
+	}
+	stageFlag = &cli.StringFlag{
+		Name:  "stage",
+		Usage: "Fetch this version stage (e.g. AWSPENDING) for every AWS secret instead of the version in the input file",
+	}
+	valueFileFlag = &cli.StringFlag{
+		Name:    "file",
+		Aliases: []string{"f"},
+		Usage:   "Read the secret value from this file instead of stdin",
+	}
+	toFlag = &cli.StringFlag{
+		Name:  "to",
+		Usage: "Secret URI to write the values to (e.g. sem://aws:secretsmanager/<profile>/<secret>)",
+	}
+	destinationFlag = &cli.StringFlag{
+		Name:  "to",
+		Usage: "Platform and account to copy the secrets to (e.g. aws:secretsmanager/<profile>?region=us-east-1 or googlecloud:secretmanager/<project>)",
+	}
+	perKeyFlag = &cli.BoolFlag{
+		Name:  "per-key",
+		Usage: "Write one secret per variable (<secret>/<KEY> on AWS, <secret>_<KEY> on Google Cloud) instead of one JSON secret",
+		Value: false,
+	}
+	dryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Show the changes without writing secrets or files",
+		Value: false,
+	}
+	forceFlag = &cli.BoolFlag{
+		Name:  "force",
+		Usage: "Replace an existing pre-commit hook",
+		Value: false,
+	}
+	frameworkFlag = &cli.BoolFlag{
+		Name:  "framework",
+		Usage: "Print the configuration for the pre-commit framework instead of installing a hook",
+		Value: false,
+	}
+	redactOutputFlag = &cli.BoolFlag{
+		Name:  "redact-output",
+		Usage: "Replace the secret values in the stdout and stderr of the command with [REDACTED]",
+		Value: false,
+	}
+	auditFileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "Audit log file to read (defaults to the --audit-log file)",
+	}
+	sinceFlag = &cli.StringFlag{
+		Name:  "since",
+		Usage: "Only show records after a duration ago (e.g. 24h) or a date (e.g. 2006-01-02)",
+	}
+	uriFilterFlag = &cli.StringFlag{
+		Name:  "uri",
+		Usage: "Only show records whose secret URI contains this text",
+	}
+	userFilterFlag = &cli.StringFlag{
+		Name:  "user",
+		Usage: "Only show records of this OS user",
+	}
+	commandFilterFlag = &cli.StringFlag{
+		Name:  "command",
+		Usage: "Only show records of this command (e.g. update)",
+	}
+	outcomeFilterFlag = &cli.StringFlag{
+		Name:  "outcome",
+		Usage: "Only show records with this outcome (success or failure)",
+	}
+	yesFlag = &cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Skip the confirmation prompt for production environments",
+		Value:   false,
+	}
+)
+
+// Global flags controlling diagnostics. Diagnostics are written to stderr so that
+// command output on stdout (e.g. 'sem load') can be used with eval and pipes.
+var (
+	quietFlag = &cli.BoolFlag{
+		Name:  "quiet",
+		Usage: "Only show warnings and errors",
+		Value: false,
+	}
+	verboseFlag = &cli.BoolFlag{
+		Name:    "verbose",
+		Aliases: []string{"debug"},
+		Usage:   "Show debug messages",
+		Value:   false,
+	}
+	logFormatFlag = &cli.StringFlag{
+		Name:    "log-format",
+		Usage:   "Format of diagnostic messages on stderr (text or json)",
+		Value:   "text",
+		EnvVars: []string{"SEM_LOG_FORMAT"},
+	}
+	lintFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the findings (text, json or sarif)",
+		Value: "text",
+	}
+	auditLogFlag = &cli.StringFlag{
+		Name:    "audit-log",
+		Usage:   "Append a record of every secret access to this JSONL file, or send it to syslog with 'syslog'",
+		EnvVars: []string{"SEM_AUDIT_LOG"},
+	}
 )
 
 // Logger instance
@@ -75,6 +231,13 @@ func createApp() *cli.App {
 		Name:    "secret-env-manager (sem)",
 		Usage:   "manage secret environment variables",
 		Version: version,
+		Flags: []cli.Flag{
+			quietFlag,
+			verboseFlag,
+			logFormatFlag,
+			auditLogFlag,
+		},
+		Before: setupApp,
 		Commands: []*cli.Command{
 			{
 				Name: "init",
@@ -82,10 +245,13 @@ func createApp() *cli.App {
 					"It supports both AWS Secrets Manager and Google Cloud Secret Manager.\n" +
 					"For AWS, it requires AWS_PROFILE and AWS_REGION environment variables to be set.\n" +
 					"For Google Cloud, it requires GOOGLE_CLOUD_PROJECT environment variable to be set.\n" +
-					"Custom AWS endpoints can be specified using the --endpoint-url flag for local development with services like LocalStack.\n",
+					"Custom AWS endpoints can be specified using the --endpoint-url flag (with --aws-credentials static) for local development with services like LocalStack.\n",
 				Action: cmd.Init,
 				Flags: []cli.Flag{
 					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
 				},
 			},
 			{
@@ -96,7 +262,9 @@ func createApp() *cli.App {
 				Action: cmd.Load,
 				Flags: []cli.Flag{
 					inputFlag,
+					envFlag,
 					exportFlag,
+					onlyUnsetFlag,
 				},
 			},
 			{
@@ -107,15 +275,337 @@ func createApp() *cli.App {
 				Action: cmd.Update,
 				Flags: []cli.Flag{
 					inputFlag,
+					envFlag,
 					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
 					noQuotesFlag,
 					noExpandJsonFlag,
+					expandFlag,
+					schemaFlag,
+					strictFlag,
+					lenientURIsFlag,
+					lockedFlag,
+					stageFlag,
+					yesFlag,
+				},
+			},
+			{
+				Name:      "exec",
+				ArgsUsage: "-- <command> [args...]",
+				Usage: "This command runs a command with the cached secrets of the specified env file added to its environment.\n" +
+					"With --redact-output, secret values (with their base64 or URL-encoded forms, and substrings of 6 or more characters of random-looking values) are removed from its output.\n" +
+					"The exit code of the command is returned. Run 'sem update' first to fetch the secrets.\n",
+				Action: cmd.Exec,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					onlyUnsetFlag,
+					redactOutputFlag,
+				},
+			},
+			{
+				Name:      "put",
+				ArgsUsage: "<uri>",
+				Usage: "This command stores a value read from stdin (or --file) as the new current version of the secret referenced by a URI, creating the secret if it does not exist.\n" +
+					"With ?key=, only that key of the JSON secret is set and its other keys are kept.\n" +
+					"Writing to a production account (an AWS profile or Google Cloud project named like 'prod') requires confirmation.\n",
+				Action: cmd.Put,
+				Flags: []cli.Flag{
+					valueFileFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					yesFlag,
+				},
+			},
+			{
+				Name: "push",
+				Usage: "This command moves the plaintext KEY=value lines of an env file into a cloud secret and rewrites them to KEY=sem://...?key=KEY references.\n" +
+					"By default the values are merged into one JSON secret; use --per-key to write one secret per variable.\n" +
+					"The changes are shown with masked values and must be confirmed before anything is written.\n",
+				Action: cmd.Push,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					toFlag,
+					perKeyFlag,
+					dryRunFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					yesFlag,
+				},
+			},
+			{
+				Name:      "copy",
+				ArgsUsage: "<src-uri> <dst-uri>",
+				Usage: "This command copies the value of a secret to another secret, on the same or another provider, creating the destination if it does not exist.\n" +
+					"With ?key= on the source only that key is copied, and with ?key= on the destination it is set in the JSON secret.\n",
+				Action: cmd.Copy,
+				Flags: []cli.Flag{
+					dryRunFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					yesFlag,
+				},
+			},
+			{
+				Name: "migrate",
+				Usage: "This command copies every secret referenced by an env file to another platform or account and rewrites the URIs of the file.\n" +
+					"Naming conflicts, existing destinations with different values and values the destination cannot store (binary, over 64 KiB) are reported before anything is written.\n",
+				Action: cmd.Migrate,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					destinationFlag,
+					dryRunFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					yesFlag,
+				},
+			},
+			{
+				Name: "rotation-status",
+				Usage: "This command reports the rotation configuration of every secret referenced by the input file: whether rotation is enabled, the rotator, the schedule and the last and next rotation dates.\n" +
+					"Secrets whose scheduled rotation date has passed are reported as overdue, and AWS secrets with a version still labeled AWSPENDING as pending.\n",
+				Action: cmd.RotationStatus,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+				},
+			},
+			{
+				Name:      "versions",
+				ArgsUsage: "<uri>",
+				Usage: "This command lists the versions of the secret referenced by a URI, with their stages (AWS) or aliases and states (Google Cloud) and creation dates.\n" +
+					"The version selected by the ?version= parameter of the URI is marked with '*'.\n",
+				Action: cmd.Versions,
+				Flags: []cli.Flag{
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+				},
+			},
+			{
+				Name:    "doctor",
+				Aliases: []string{"scan"},
+				Usage: "This command checks that no secret has been or could be committed to git.\n" +
+					"It verifies that .gitignore covers the cache, metadata and active environment files, that no such file is in the index\n" +
+					"or was ever committed on any branch or tag, and that no tracked file contains a value from the current cache files.\n" +
+					"It exits with an error when a check fails or git reports an error, so it can be used in CI.\n",
+				Action: cmd.Doctor,
+			},
+			{
+				Name: "lint",
+				Usage: "This command checks an input env file without contacting any provider.\n" +
+					"It reports literal values with a well-known credential format (error) or that look randomly generated (warning),\n" +
+					"sem:// values that are not valid secret URIs, duplicate keys and invalid variable names.\n" +
+					"It exits with an error when an error is reported; use --format json or sarif for CI and code scanning.\n",
+				Action: cmd.Lint,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					lintFormatFlag,
+				},
+			},
+			{
+				Name:  "hooks",
+				Usage: "This command manages the git pre-commit hook that keeps resolved secrets out of commits.\n",
+				Subcommands: []*cli.Command{
+					{
+						Name: "install",
+						Usage: "This command installs a pre-commit hook that runs 'sem hooks pre-commit'.\n" +
+							"When the repository uses the pre-commit framework (.pre-commit-config.yaml), the configuration to add is printed instead.\n",
+						Action: cmd.HooksInstall,
+						Flags: []cli.Flag{
+							forceFlag,
+							frameworkFlag,
+						},
+					},
+					{
+						Name: "pre-commit",
+						Usage: "This command checks the staged files: it rejects sem cache and metadata files and files containing a value\n" +
+							"from the current .cache.* files, and warns about literal credentials in .env input files.\n",
+						Action: cmd.HooksPreCommit,
+					},
+				},
+			},
+			{
+				Name:  "audit",
+				Usage: "This command reads the audit log of secret accesses enabled with --audit-log or SEM_AUDIT_LOG.\n",
+				Subcommands: []*cli.Command{
+					{
+						Name: "show",
+						Usage: "This command prints the records of a JSONL audit log: time, OS user and host, command, outcome, cache hit or miss, secret URI and resolved version.\n" +
+							"Records can be filtered by time, secret URI, user, command and outcome. Secret values are never recorded.\n",
+						Action: cmd.AuditShow,
+						Flags: []cli.Flag{
+							auditFileFlag,
+							sinceFlag,
+							uriFilterFlag,
+							userFilterFlag,
+							commandFilterFlag,
+							outcomeFilterFlag,
+						},
+					},
+				},
+			},
+			{
+				Name: "lock",
+				Usage: "This command resolves every secret of the specified env file and pins it to its concrete version in a lockfile named $input.lock.\n" +
+					"Version labels such as AWSCURRENT or latest are recorded as version IDs. Commit the lockfile and use 'update --locked' to fetch exactly those versions.\n" +
+					"Changed versions are reported so that secret rotations can be reviewed. The lockfile contains no secret values.\n",
+				Action: cmd.Lock,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+				},
+			},
+			{
+				Name: "check",
+				Usage: "This command reports required variables that are missing from both the cache file and the current environment.\n" +
+					"A bare KEY line in the env file (or required: true in YAML/JSON input files) declares a required variable that must come from the environment or a default.\n" +
+					"Required variables are not written to the cache file as empty values, so use 'load --only-unset' to keep the values from the environment.\n",
+				Action: cmd.Check,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+				},
+			},
+			{
+				Name: "validate",
+				Usage: "This command retrieves secrets based on the specified env file and validates the resolved values without writing the cache file.\n" +
+					"Rules come from the per-entry options of YAML/JSON input files and from the schema file given with --schema.\n" +
+					"update runs the same validation before writing the cache file.\n",
+				Action: cmd.Validate,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					noExpandJsonFlag,
+					expandFlag,
+					schemaFlag,
+					strictFlag,
+					lenientURIsFlag,
+				},
+			},
+			{
+				Name:      "explain",
+				ArgsUsage: "<KEY>",
+				Usage: "This command shows where a variable comes from: the input file line, the secret URI, the resolved version, the JSON path and whether its name was expanded from the secret.\n" +
+					"Earlier definitions overwritten by the entry are listed as well. Values are never printed.\n" +
+					"The information is read from the metadata file written next to the cache by update (.cache.$input.meta.json); without it, secrets are resolved from the providers.\n",
+				Action: cmd.Explain,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
+					endpointURLFlag,
+					gcpEndpointFlag,
+					awsCredentialsFlag,
+					endpointsFileFlag,
+					noExpandJsonFlag,
+					expandFlag,
+				},
+			},
+			{
+				Name:      "use",
+				ArgsUsage: "<env>",
+				Usage: "This command records the active environment (e.g. dev, staging, prod) in .sem-env.\n" +
+					"update, load and status use the active environment when neither --env nor -i is given, which makes it easy to switch environments with direnv.\n" +
+					"Production environments (prod, production, ...) require confirmation unless --yes is specified.\n",
+				Action: cmd.Use,
+				Flags: []cli.Flag{
+					yesFlag,
+				},
+			},
+			{
+				Name: "status",
+				Usage: "This command shows the active environment, its input and cache files, and how fresh each environment's cache is.\n" +
+					"A cache is reported as stale when its input file was modified after the last update.\n",
+				Action: cmd.Status,
+				Flags: []cli.Flag{
+					inputFlag,
+					envFlag,
 				},
 			},
 		},
 	}
 }
 
+// setupApp configures diagnostics and auditing before a command runs
+func setupApp(c *cli.Context) error {
+	if err := configureOutput(c); err != nil {
+		return err
+	}
+	return configureAudit(c)
+}
+
+// configureAudit enables the audit log when --audit-log or SEM_AUDIT_LOG is set
+func configureAudit(c *cli.Context) error {
+	target := c.String("audit-log")
+	if target == "" {
+		return nil
+	}
+
+	sinkResult := audit.OpenSinkResult(target)
+	if sinkResult.IsFailure() {
+		return sinkResult.GetError()
+	}
+
+	// The first argument after the global flags is the command name
+	audit.Configure(sinkResult.Unwrap(), c.Args().First())
+	return nil
+}
+
+// configureOutput applies the global log level, log format and color settings before a command runs
+func configureOutput(c *cli.Context) error {
+	// Command output is colored only on a terminal, so piped output stays free of escape codes
+	noColorEnv := os.Getenv("NO_COLOR")
+	if !formatting.ColorsWanted(noColorEnv, os.Stdout) {
+		formatting.DisableColors()
+	}
+	colorized := formatting.IsColorEnabled() && formatting.ColorsWanted(noColorEnv, os.Stderr)
+
+	if c.Bool("quiet") && c.Bool("verbose") {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+
+	formatResult := logging.ParseFormatResult(c.String("log-format"))
+	if formatResult.IsFailure() {
+		return formatResult.GetError()
+	}
+
+	level := logging.InfoLevel
+	if c.Bool("quiet") {
+		level = logging.WarnLevel
+	} else if c.Bool("verbose") {
+		level = logging.DebugLevel
+	}
+
+	logging.Configure(level, formatResult.Unwrap(), colorized)
+	return nil
+}
+
 // runApp runs the CLI application with the given arguments
 // Returns a Result monad to handle errors in a functional way
 func runApp(app *cli.App, args []string) functional.Result[bool] {
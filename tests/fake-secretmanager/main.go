@@ -0,0 +1,42 @@
+// Package main runs the in-memory Google Cloud Secret Manager used by the integration tests.
+//
+//	go run ./tests/fake-secretmanager -addr localhost:8085 -seed tests/googlecloud/secrets.json
+//
+// sem reaches it with --gcp-endpoint localhost:8085.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gumi-tsd/secret-env-manager/internal/provider/googlecloud/fake"
+)
+
+func main() {
+	address := flag.String("addr", "localhost:8085", "Address to listen on")
+	seedFile := flag.String("seed", "", "JSON file of the secrets to create")
+	flag.Parse()
+
+	server := fake.NewServer()
+	if *seedFile != "" {
+		if err := server.LoadSeedFile(*seedFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	listening, err := server.Start(*address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Fake Secret Manager listening on %s\n", listening)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	server.Stop()
+}
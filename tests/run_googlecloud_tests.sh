@@ -0,0 +1,175 @@
+#!/bin/bash
+
+# Integration tests for googlecloud URIs, run against the in-repo fake Secret Manager
+# (tests/fake-secretmanager) seeded with tests/googlecloud/secrets.json
+
+# Functions for colored output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[0;33m'
+NC='\033[0m' # No Color
+
+# Address of the fake server (override with FAKE_SECRET_MANAGER_ADDR)
+FAKE_ADDR="${FAKE_SECRET_MANAGER_ADDR:-localhost:8085}"
+FAKE_BIN=""
+FAKE_PID=""
+
+# Log output functions
+log_success() {
+  echo "${GREEN}[SUCCESS]${NC} $1"
+}
+
+log_error() {
+  echo "${RED}[ERROR]${NC} $1"
+}
+
+log_info() {
+  echo "${YELLOW}[INFO]${NC} $1"
+}
+
+# Build and start the fake server, waiting until it listens
+start_fake_server() {
+  FAKE_BIN=$(mktemp)
+  if ! go build -o "$FAKE_BIN" ./tests/fake-secretmanager; then
+    log_error "Failed to build the fake Secret Manager"
+    return 1
+  fi
+
+  log_file=$(mktemp)
+  "$FAKE_BIN" -addr "$FAKE_ADDR" -seed tests/googlecloud/secrets.json > "$log_file" 2>&1 &
+  FAKE_PID=$!
+
+  for _ in $(seq 1 50); do
+    if grep -q "listening" "$log_file"; then
+      log_info "$(cat "$log_file")"
+      rm -f "$log_file"
+      return 0
+    fi
+    if ! kill -0 "$FAKE_PID" 2>/dev/null; then
+      break
+    fi
+    sleep 0.1
+  done
+
+  log_error "The fake Secret Manager did not start: $(cat "$log_file")"
+  rm -f "$log_file"
+  return 1
+}
+
+# Stop the fake server and remove its binary
+stop_fake_server() {
+  if [ -n "$FAKE_PID" ]; then
+    kill "$FAKE_PID" 2>/dev/null
+    wait "$FAKE_PID" 2>/dev/null
+  fi
+  rm -f "$FAKE_BIN"
+}
+
+# Test execution function
+run_test() {
+  base_name=$1
+  input_file="tests/googlecloud/input/$base_name"
+  expected_file="tests/googlecloud/expected/$base_name"
+  output_file=".cache.tests_googlecloud_input_$base_name"
+
+  # Check if expected result file exists
+  if [ ! -f "$expected_file" ]; then
+    log_error "Expected result file does not exist: $expected_file"
+    return 1
+  fi
+
+  log_info "Running test: $input_file (expected: $expected_file)"
+
+  # Execute update command
+  go run main.go update -i "$input_file" --gcp-endpoint "$FAKE_ADDR"
+
+  if [ $? -ne 0 ]; then
+    log_error "Failed to execute update command: $input_file"
+    return 1
+  fi
+
+  # Check if output file exists
+  if [ ! -f "$output_file" ]; then
+    log_error "Output file was not generated: $output_file"
+    return 1
+  fi
+
+  # Execute load command and capture its output
+  load_output=$(go run main.go load -i "$input_file")
+
+  if [ $? -ne 0 ]; then
+    log_error "Failed to execute load command: $input_file"
+    return 1
+  fi
+
+  # Compare load command output with expected result
+  expected_content=$(cat "$expected_file")
+  if [ "$load_output" == "$expected_content" ]; then
+    log_success "Test passed: $input_file"
+    return 0
+  else
+    log_error "Test failed: $input_file - Load output does not match expected"
+    echo "Expected:"
+    echo "$expected_content"
+    echo "Got:"
+    echo "$load_output"
+    return 1
+  fi
+}
+
+# Main process
+main() {
+  # Check project root directory
+  if [ ! -f "main.go" ] || [ ! -d "tests" ]; then
+    log_error "Please run this script from the project root directory"
+    log_error "Current directory: $(pwd)"
+    exit 1
+  fi
+
+  trap stop_fake_server EXIT
+  if ! start_fake_server; then
+    exit 1
+  fi
+
+  # Dynamically find all input env files
+  input_files=()
+  for file in $(find tests/googlecloud/input -name "*.env" -type f); do
+    # Extract just the filename without path
+    filename=$(basename "$file")
+    input_files+=("$filename")
+  done
+
+  # Test variables
+  total_tests=${#input_files[@]}
+  passed_tests=0
+  failed_tests=0
+
+  log_info "Found $total_tests test files to process"
+
+  # Process each test file
+  for file in "${input_files[@]}"; do
+    if run_test "$file"; then
+      ((passed_tests++))
+    else
+      ((failed_tests++))
+    fi
+    echo "----------------------------------------"
+  done
+
+  # Output result summary
+  echo "Test Results Summary:"
+  echo "  Total Tests: $total_tests"
+  echo "  Passed: ${GREEN}$passed_tests${NC}"
+  echo "  Failed: ${RED}$failed_tests${NC}"
+
+  if [ $failed_tests -eq 0 ]; then
+    log_success "All tests passed!"
+    exit 0
+  else
+    log_error "$failed_tests test(s) failed"
+    exit 1
+  fi
+}
+
+# Run script
+main
@@ -35,7 +35,7 @@ run_test() {
   log_info "Running test: $input_file (expected: $expected_file)"
   
   # Execute update command
-  go run main.go update -i "$input_file" --endpoint-url http://localhost:4566
+  go run main.go update -i "$input_file" --endpoint-url http://localhost:4566 --aws-credentials static
   
   if [ $? -ne 0 ]; then
     log_error "Failed to execute update command: $input_file"